@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// runAgentsPending lists agents awaiting validation (or any other --status)
+// against a running server's GET /v1/agents endpoint.
+func runAgentsPending(args []string) error {
+	fs := flag.NewFlagSet("agents pending", flag.ExitOnError)
+	server := fs.String("server", "", "base URL of the insec server")
+	token := fs.String("token", "", "operator bearer token")
+	status := fs.String("status", "pending", "agent status to filter on")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *server == "" {
+		return fmt.Errorf("agents pending: --server is required")
+	}
+
+	req, err := http.NewRequest("GET", strings.TrimRight(*server, "/")+"/v1/agents?status="+*status, nil)
+	if err != nil {
+		return fmt.Errorf("agents pending: %w", err)
+	}
+	setAuth(req, *token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("agents pending: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("agents pending: server returned %s", resp.Status)
+	}
+
+	var body struct {
+		Agents []struct {
+			ID       string `json:"id"`
+			TenantID string `json:"tenant_id"`
+			Hostname string `json:"hostname"`
+			Status   string `json:"status"`
+		} `json:"agents"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("agents pending: decode response: %w", err)
+	}
+	for _, a := range body.Agents {
+		fmt.Printf("%s\t%s\t%s\t%s\n", a.ID, a.TenantID, a.Hostname, a.Status)
+	}
+	return nil
+}
+
+// runAgentsValidate bulk-validates a fleet of pending agents in one call via
+// POST /v1/agents/bulk-validate, so an operator clearing a large queue
+// doesn't have to approve each agent one at a time.
+func runAgentsValidate(args []string) error {
+	fs := flag.NewFlagSet("agents validate", flag.ExitOnError)
+	server := fs.String("server", "", "base URL of the insec server")
+	token := fs.String("token", "", "operator bearer token")
+	ids := fs.String("ids", "", "comma-separated agent IDs to validate")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *server == "" {
+		return fmt.Errorf("agents validate: --server is required")
+	}
+	if *ids == "" {
+		return fmt.Errorf("agents validate: --ids is required")
+	}
+
+	payload, err := json.Marshal(struct {
+		AgentIDs []string `json:"agent_ids"`
+	}{AgentIDs: strings.Split(*ids, ",")})
+	if err != nil {
+		return fmt.Errorf("agents validate: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", strings.TrimRight(*server, "/")+"/v1/agents/bulk-validate", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("agents validate: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setAuth(req, *token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("agents validate: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("agents validate: server returned %s: %s", resp.Status, body)
+	}
+
+	var result struct {
+		Validated int `json:"validated"`
+		Requested int `json:"requested"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("agents validate: decode response: %w", err)
+	}
+	fmt.Printf("validated %d/%d agents\n", result.Validated, result.Requested)
+	return nil
+}
+
+func setAuth(req *http.Request, token string) {
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
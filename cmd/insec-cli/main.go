@@ -0,0 +1,56 @@
+// Command insec-cli is a small cscli-style operator tool for this server:
+// enrolling a tenant with the upstream community signal hub (internal/intel)
+// and listing/disabling that sharing, plus clearing the fleet's pending
+// agent-registration queue.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "signals":
+		switch os.Args[2] {
+		case "list":
+			err = runSignalsList(os.Args[3:])
+		case "enroll":
+			err = runSignalsEnroll(os.Args[3:])
+		case "disable":
+			err = runSignalsDisable(os.Args[3:])
+		default:
+			usage()
+			os.Exit(2)
+		}
+	case "agents":
+		switch os.Args[2] {
+		case "pending":
+			err = runAgentsPending(os.Args[3:])
+		case "validate":
+			err = runAgentsValidate(os.Args[3:])
+		default:
+			usage()
+			os.Exit(2)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "insec-cli:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: insec-cli signals <list|enroll|disable> [flags]")
+	fmt.Fprintln(os.Stderr, "       insec-cli agents <pending|validate> [flags]")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"insec/internal/intel"
+)
+
+func defaultKeyFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".insec/signals.key"
+	}
+	return filepath.Join(home, ".insec", "signals.key")
+}
+
+// runSignalsEnroll generates this tenant's Ed25519 signing keypair, saves
+// it locally, and runs one pull against the hub to confirm connectivity
+// before the background Syncer takes over. Nothing is pushed here.
+func runSignalsEnroll(args []string) error {
+	fs := flag.NewFlagSet("signals enroll", flag.ExitOnError)
+	hubURL := fs.String("hub", "", "upstream community hub base URL")
+	keyFile := fs.String("key-file", defaultKeyFile(), "path to store this tenant's Ed25519 signing key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *hubURL == "" {
+		return fmt.Errorf("signals enroll: --hub is required")
+	}
+
+	signer, err := intel.GenerateSigner()
+	if err != nil {
+		return fmt.Errorf("signals enroll: generate keypair: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(*keyFile), 0o700); err != nil {
+		return fmt.Errorf("signals enroll: create key directory: %w", err)
+	}
+	if err := os.WriteFile(*keyFile, []byte(hex.EncodeToString(signer.PrivateKeyBytes())), 0o600); err != nil {
+		return fmt.Errorf("signals enroll: write key file: %w", err)
+	}
+
+	hub := intel.NewHubClient(*hubURL, signer)
+	decisions, err := hub.Pull()
+	if err != nil {
+		return fmt.Errorf("signals enroll: initial pull from hub: %w", err)
+	}
+
+	fmt.Printf("enrolled with hub %s, key saved to %s, pulled %d decisions\n", *hubURL, *keyFile, len(decisions))
+	return nil
+}
+
+// runSignalsList pulls the hub's current consolidated Decisions and prints
+// them. It doesn't mutate this server's DecisionStore - that only happens
+// via the server's own /api/v1/signals/pull endpoint or its Syncer.
+func runSignalsList(args []string) error {
+	fs := flag.NewFlagSet("signals list", flag.ExitOnError)
+	hubURL := fs.String("hub", "", "upstream community hub base URL")
+	keyFile := fs.String("key-file", defaultKeyFile(), "path to this tenant's Ed25519 signing key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *hubURL == "" {
+		return fmt.Errorf("signals list: --hub is required")
+	}
+
+	signer, err := loadSigner(*keyFile)
+	if err != nil {
+		return fmt.Errorf("signals list: %w", err)
+	}
+
+	decisions, err := intel.NewHubClient(*hubURL, signer).Pull()
+	if err != nil {
+		return fmt.Errorf("signals list: %w", err)
+	}
+	for _, d := range decisions {
+		fmt.Printf("%s\t%s\t%s\tconfidence=%d\texpires=%s\n", d.Type, d.Scope, d.Value, d.Confidence, d.ExpiresAt)
+	}
+	return nil
+}
+
+// runSignalsDisable stops this tenant's local participation in signal
+// sharing by removing its signing key, so the Syncer has nothing to sign
+// with. There is no server-side unenroll call yet (the hub has no notion
+// of revoking a previously-pushed signal); this is an honest, local-only
+// disable until that lands.
+func runSignalsDisable(args []string) error {
+	fs := flag.NewFlagSet("signals disable", flag.ExitOnError)
+	keyFile := fs.String("key-file", defaultKeyFile(), "path to this tenant's Ed25519 signing key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := os.Remove(*keyFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("signals disable: remove key file: %w", err)
+	}
+	fmt.Println("local signing key removed; this tenant will no longer push new signals to the hub")
+	fmt.Println("note: previously pushed signals remain on the hub until it expires them")
+	return nil
+}
+
+func loadSigner(keyFile string) (*intel.Signer, error) {
+	raw, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read key file (run 'signals enroll' first): %w", err)
+	}
+	return intel.SignerFromHex(string(raw))
+}
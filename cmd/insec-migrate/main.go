@@ -0,0 +1,137 @@
+// Command insec-migrate applies, rolls back, and reports on this server's
+// schema migrations (internal/migrations) against a running Postgres
+// instance - the reviewable, reversible replacement for the ad hoc
+// server.AutoMigrate call this project used to run on every boot.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"insec/internal/migrations"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "up":
+		err = runUp(os.Args[2:])
+	case "down":
+		err = runDown(os.Args[2:])
+	case "to":
+		err = runTo(os.Args[2:])
+	case "status":
+		err = runStatus(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "insec-migrate:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: insec-migrate <up|down|to|status> [flags]")
+}
+
+func runUp(args []string) error {
+	fs := flag.NewFlagSet("up", flag.ExitOnError)
+	dsn := fs.String("dsn", os.Getenv("INSEC_DATABASE_URL"), "database connection string (default: $INSEC_DATABASE_URL)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	db, err := dial(*dsn)
+	if err != nil {
+		return fmt.Errorf("up: %w", err)
+	}
+	if err := migrations.New(db).Migrate(); err != nil {
+		return fmt.Errorf("up: %w", err)
+	}
+	fmt.Println("up: applied all pending migrations")
+	return nil
+}
+
+func runDown(args []string) error {
+	fs := flag.NewFlagSet("down", flag.ExitOnError)
+	dsn := fs.String("dsn", os.Getenv("INSEC_DATABASE_URL"), "database connection string (default: $INSEC_DATABASE_URL)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	db, err := dial(*dsn)
+	if err != nil {
+		return fmt.Errorf("down: %w", err)
+	}
+	if err := migrations.New(db).RollbackLast(); err != nil {
+		return fmt.Errorf("down: %w", err)
+	}
+	fmt.Println("down: rolled back the most recently applied migration")
+	return nil
+}
+
+func runTo(args []string) error {
+	fs := flag.NewFlagSet("to", flag.ExitOnError)
+	dsn := fs.String("dsn", os.Getenv("INSEC_DATABASE_URL"), "database connection string (default: $INSEC_DATABASE_URL)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("to: a migration ID is required, e.g. insec-migrate to 20231115000000_initial_schema")
+	}
+	id := fs.Arg(0)
+	db, err := dial(*dsn)
+	if err != nil {
+		return fmt.Errorf("to: %w", err)
+	}
+	if err := migrations.New(db).MigrateTo(id); err != nil {
+		return fmt.Errorf("to %s: %w", id, err)
+	}
+	fmt.Printf("to: migrated to %s\n", id)
+	return nil
+}
+
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	dsn := fs.String("dsn", os.Getenv("INSEC_DATABASE_URL"), "database connection string (default: $INSEC_DATABASE_URL)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	db, err := dial(*dsn)
+	if err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+	entries, err := migrations.Status(db)
+	if err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+	for _, e := range entries {
+		state := "pending"
+		if e.Applied {
+			state = "applied"
+		}
+		fmt.Printf("%s\t%s\n", e.ID, state)
+	}
+	return nil
+}
+
+func dial(dsn string) (*gorm.DB, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("--dsn is required (or set INSEC_DATABASE_URL)")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	return db, nil
+}
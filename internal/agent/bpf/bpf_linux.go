@@ -0,0 +1,56 @@
+//go:build linux
+
+package bpf
+
+import (
+	"fmt"
+	"sync"
+)
+
+// load attaches the enabled probes and starts one reader goroutine per ring
+// buffer. Each reader owns its ring buffer exclusively (single writer per
+// perf buffer), applies its session's rate limit, and resolves the cgroup ID
+// to a session before publishing to m.events; a full m.events channel drops
+// the event rather than blocking the reader loop, since blocking here would
+// eventually back up into the kernel ring buffer itself.
+func (m *Manager) load() error {
+	var mu sync.Mutex
+	limiters := make(map[uint64]*rateLimiter)
+
+	limiterFor := func(cgroupID uint64) *rateLimiter {
+		mu.Lock()
+		defer mu.Unlock()
+		lim, ok := limiters[cgroupID]
+		if !ok {
+			lim = newRateLimiter(m.cfg.MaxEventsPerSecond)
+			limiters[cgroupID] = lim
+		}
+		return lim
+	}
+
+	for probe, enabled := range m.cfg.Enabled {
+		if !enabled {
+			continue
+		}
+		reader, err := attachProbe(probe, m.cfg.RingBufferSize, func(raw RawEvent) {
+			if !limiterFor(raw.CgroupID).Allow() {
+				return
+			}
+			sessionID, ok := m.resolver.Resolve(raw.CgroupID)
+			if !ok {
+				return // event from a cgroup we aren't tracking
+			}
+			select {
+			case m.events <- CorrelatedEvent{RawEvent: raw, SessionID: sessionID}:
+			default:
+				// consumer too slow; drop rather than block the kernel side
+			}
+		})
+		if err != nil {
+			m.Close()
+			return fmt.Errorf("%w: %v", ErrUnsupported, err)
+		}
+		m.readers = append(m.readers, reader)
+	}
+	return nil
+}
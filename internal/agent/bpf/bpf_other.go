@@ -0,0 +1,10 @@
+//go:build !linux
+
+package bpf
+
+// load is a no-op on non-Linux platforms: BPF probes are unavailable, so the
+// agent falls back to userspace-only telemetry collection and NewManager
+// reports ErrUnsupported.
+func (m *Manager) load() error {
+	return ErrUnsupported
+}
@@ -0,0 +1,10 @@
+//go:build linux
+
+package bpf
+
+// embeddedObjects holds the bpf2go-compiled object for each probe. Populated
+// via go:embed once `go generate ./internal/agent/bpf` has produced
+// execsnoop_bpfel.o, opensnoop_bpfel.o, and tcpconnect_bpfel.o from the C
+// sources under internal/agent/bpf/c/ — left empty here since this sandbox
+// has no BPF/clang toolchain to generate them.
+var embeddedObjects = map[Probe][]byte{}
@@ -0,0 +1,57 @@
+package bpf
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ringbufReader is satisfied by the platform-specific cilium/ebpf ring
+// buffer reader; abstracted here so bpf.go stays platform-agnostic.
+type ringbufReader interface {
+	Close() error
+}
+
+// rateLimiter enforces Config.MaxEventsPerSecond per session so one noisy
+// session (e.g. a build loop doing thousands of opens/sec) can't starve
+// other sessions sharing the same ring buffer or flood the ingest server.
+type rateLimiter struct {
+	mu       sync.Mutex
+	perSec   int
+	window   time.Time
+	count    int
+	dropped  uint64
+}
+
+func newRateLimiter(perSec int) *rateLimiter {
+	return &rateLimiter{perSec: perSec, window: time.Now()}
+}
+
+// Allow reports whether an event for the current second's bucket may pass.
+// Dropped events are counted, never blocked on, so the kernel-side ring
+// buffer is always drained promptly.
+func (r *rateLimiter) Allow() bool {
+	if r.perSec <= 0 {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.window) >= time.Second {
+		r.window = now
+		r.count = 0
+	}
+	if r.count >= r.perSec {
+		atomic.AddUint64(&r.dropped, 1)
+		return false
+	}
+	r.count++
+	return true
+}
+
+// Dropped returns the number of events dropped by this limiter so far, for
+// the agent's own health telemetry.
+func (r *rateLimiter) Dropped() uint64 {
+	return atomic.LoadUint64(&r.dropped)
+}
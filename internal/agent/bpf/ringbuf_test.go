@@ -0,0 +1,24 @@
+package bpf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_AllowsUpToPerSecondThenDrops(t *testing.T) {
+	r := newRateLimiter(3)
+	for i := 0; i < 3; i++ {
+		assert.True(t, r.Allow(), "event %d should be allowed within the per-second cap", i)
+	}
+	assert.False(t, r.Allow(), "the 4th event within the same second must be dropped")
+	assert.Equal(t, uint64(1), r.Dropped())
+}
+
+func TestRateLimiter_UnlimitedWhenPerSecNonPositive(t *testing.T) {
+	r := newRateLimiter(0)
+	for i := 0; i < 1000; i++ {
+		assert.True(t, r.Allow())
+	}
+	assert.Equal(t, uint64(0), r.Dropped())
+}
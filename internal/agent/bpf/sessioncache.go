@@ -0,0 +1,59 @@
+package bpf
+
+import "container/list"
+
+// SessionCache is an LRU-bounded SessionResolver mapping cgroup IDs to
+// session UUIDs. It's bounded because a long-lived agent will churn through
+// many short sessions and an unbounded map would leak memory.
+type SessionCache struct {
+	capacity int
+	entries  map[uint64]*list.Element
+	order    *list.List // front = most recently used
+}
+
+type sessionCacheEntry struct {
+	cgroupID  uint64
+	sessionID string
+}
+
+// NewSessionCache returns a SessionCache holding at most capacity entries.
+func NewSessionCache(capacity int) *SessionCache {
+	if capacity <= 0 {
+		capacity = 4096
+	}
+	return &SessionCache{
+		capacity: capacity,
+		entries:  make(map[uint64]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Put records that cgroupID belongs to sessionID, evicting the
+// least-recently-used entry if the cache is full.
+func (c *SessionCache) Put(cgroupID uint64, sessionID string) {
+	if el, ok := c.entries[cgroupID]; ok {
+		el.Value.(*sessionCacheEntry).sessionID = sessionID
+		c.order.MoveToFront(el)
+		return
+	}
+	el := c.order.PushFront(&sessionCacheEntry{cgroupID: cgroupID, sessionID: sessionID})
+	c.entries[cgroupID] = el
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*sessionCacheEntry).cgroupID)
+		}
+	}
+}
+
+// Resolve implements SessionResolver.
+func (c *SessionCache) Resolve(cgroupID uint64) (string, bool) {
+	el, ok := c.entries[cgroupID]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*sessionCacheEntry).sessionID, true
+}
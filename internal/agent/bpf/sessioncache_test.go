@@ -0,0 +1,65 @@
+package bpf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionCache_PutAndResolve(t *testing.T) {
+	c := NewSessionCache(2)
+	c.Put(1, "session-1")
+
+	got, ok := c.Resolve(1)
+	require.True(t, ok)
+	assert.Equal(t, "session-1", got)
+}
+
+func TestSessionCache_ResolveUnknownMiss(t *testing.T) {
+	c := NewSessionCache(2)
+	_, ok := c.Resolve(99)
+	assert.False(t, ok)
+}
+
+func TestSessionCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewSessionCache(2)
+	c.Put(1, "s1")
+	c.Put(2, "s2")
+	c.Put(3, "s3") // capacity 2: evicts cgroup 1, the least recently used
+
+	_, ok := c.Resolve(1)
+	assert.False(t, ok, "cgroup 1 should have been evicted once a third entry was added beyond capacity")
+	_, ok = c.Resolve(2)
+	assert.True(t, ok)
+	_, ok = c.Resolve(3)
+	assert.True(t, ok)
+}
+
+func TestSessionCache_ResolveRefreshesRecency(t *testing.T) {
+	c := NewSessionCache(2)
+	c.Put(1, "s1")
+	c.Put(2, "s2")
+	c.Resolve(1) // touch 1, making 2 the least recently used
+	c.Put(3, "s3")
+
+	_, ok := c.Resolve(2)
+	assert.False(t, ok, "cgroup 2 should have been evicted after being touched least recently")
+	_, ok = c.Resolve(1)
+	assert.True(t, ok, "cgroup 1 should survive eviction since it was resolved most recently")
+}
+
+func TestSessionCache_PutOverwritesExistingEntry(t *testing.T) {
+	c := NewSessionCache(2)
+	c.Put(1, "s1")
+	c.Put(1, "s1-updated")
+
+	got, ok := c.Resolve(1)
+	require.True(t, ok)
+	assert.Equal(t, "s1-updated", got)
+}
+
+func TestNewSessionCache_NonPositiveCapacityDefaults(t *testing.T) {
+	c := NewSessionCache(0)
+	assert.Equal(t, 4096, c.capacity)
+}
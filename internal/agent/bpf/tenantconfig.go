@@ -0,0 +1,36 @@
+package bpf
+
+import "insec/internal/models"
+
+// capabilityProbe maps an AgentRegistration.Capabilities / ProbeConfig key
+// to the Probe it controls.
+var capabilityProbe = map[string]Probe{
+	models.CapabilityBPFExec: ProbeExec,
+	models.CapabilityBPFOpen: ProbeOpen,
+	models.CapabilityBPFTCP:  ProbeTCP,
+}
+
+// ApplyTenantConfig overrides cfg.Enabled per a server-pushed
+// models.ProbeConfig, letting an operator disable an individual probe
+// fleet-wide for one tenant (e.g. a known-noisy opensnoop on a build farm)
+// without redeploying agents.
+func ApplyTenantConfig(cfg Config, pc models.ProbeConfig) Config {
+	for capability, probeEnabled := range pc.Enabled {
+		if probe, ok := capabilityProbe[capability]; ok {
+			cfg.Enabled[probe] = probeEnabled
+		}
+	}
+	return cfg
+}
+
+// Capabilities returns the AgentRegistration.Capabilities strings this
+// Manager's configuration would advertise to the server.
+func (cfg Config) Capabilities() []string {
+	var caps []string
+	for capability, probe := range capabilityProbe {
+		if cfg.Enabled[probe] {
+			caps = append(caps, capability)
+		}
+	}
+	return caps
+}
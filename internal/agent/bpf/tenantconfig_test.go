@@ -0,0 +1,31 @@
+package bpf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"insec/internal/models"
+)
+
+func TestApplyTenantConfig_OverridesEnabledProbes(t *testing.T) {
+	cfg := DefaultConfig()
+	pc := models.ProbeConfig{Enabled: map[string]bool{models.CapabilityBPFOpen: false}}
+
+	got := ApplyTenantConfig(cfg, pc)
+	assert.False(t, got.Enabled[ProbeOpen], "a tenant override disabling bpf_open must take effect")
+	assert.True(t, got.Enabled[ProbeExec], "probes not mentioned in the override must keep their prior value")
+}
+
+func TestApplyTenantConfig_IgnoresUnknownCapability(t *testing.T) {
+	cfg := DefaultConfig()
+	pc := models.ProbeConfig{Enabled: map[string]bool{"some_other_capability": true}}
+
+	got := ApplyTenantConfig(cfg, pc)
+	assert.Equal(t, cfg.Enabled, got.Enabled, "a capability this package doesn't know about must be ignored, not error")
+}
+
+func TestConfig_CapabilitiesListsOnlyEnabledProbes(t *testing.T) {
+	cfg := Config{Enabled: map[Probe]bool{ProbeExec: true, ProbeOpen: false, ProbeTCP: true}}
+	assert.ElementsMatch(t, []string{models.CapabilityBPFExec, models.CapabilityBPFTCP}, cfg.Capabilities())
+}
@@ -0,0 +1,64 @@
+// Package cgroup places monitored processes into per-session cgroup v2
+// directories so BPF probes can tag every event with a numeric cgroup ID
+// that userspace maps back to a session UUID. This is what lets the
+// execsnoop/opensnoop/tcpconnect events in internal/agent/bpf be correlated
+// back to "everything this one shell invocation did".
+package cgroup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// DefaultRoot is where per-session cgroup v2 directories are created. It
+// must be a delegated subtree of the host's unified cgroup2 hierarchy.
+const DefaultRoot = "/sys/fs/cgroup/insec"
+
+// Session is a tracked cgroup v2 directory created for one monitored child
+// process tree.
+type Session struct {
+	ID       string // session UUID, also returned to the server as Event.SessionID
+	Path     string // cgroup v2 directory on disk
+	CgroupID uint64 // numeric cgroup ID, matches bpf_get_current_cgroup_id()
+}
+
+// New creates a fresh cgroup v2 directory under root (DefaultRoot if empty),
+// ready to receive a child PID via Session.Attach. Callers should create the
+// session, attach the not-yet-exec'd child, and only then exec the target
+// binary so no event escapes the cgroup.
+func New(root string) (*Session, error) {
+	if root == "" {
+		root = DefaultRoot
+	}
+	id := uuid.NewString()
+	path := filepath.Join(root, id)
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, fmt.Errorf("cgroup: create session dir: %w", err)
+	}
+
+	cgroupID, err := resolveCgroupID(path)
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("cgroup: resolve cgroup id: %w", err)
+	}
+
+	return &Session{ID: id, Path: path, CgroupID: cgroupID}, nil
+}
+
+// Attach writes pid into the session's cgroup.procs file, moving it (and any
+// future children it forks before exec) into the tracked cgroup.
+func (s *Session) Attach(pid int) error {
+	procsFile := filepath.Join(s.Path, "cgroup.procs")
+	return os.WriteFile(procsFile, []byte(strconv.Itoa(pid)), 0o644)
+}
+
+// Close removes the session's cgroup directory. The kernel refuses to rmdir
+// a non-empty cgroup, so callers must wait for the monitored process to exit
+// first.
+func (s *Session) Close() error {
+	return os.Remove(s.Path)
+}
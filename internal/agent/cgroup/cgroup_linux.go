@@ -0,0 +1,26 @@
+//go:build linux
+
+package cgroup
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// resolveCgroupID resolves the numeric cgroup ID (the same value the BPF
+// helper bpf_get_current_cgroup_id() returns for processes in this cgroup)
+// via name_to_handle_at, which on cgroup2 filesystems returns the cgroup's
+// inode/kernfs ID as an 8-byte handle.
+func resolveCgroupID(path string) (uint64, error) {
+	handle, _, err := unix.NameToHandleAt(unix.AT_FDCWD, path, 0)
+	if err != nil {
+		return 0, fmt.Errorf("name_to_handle_at: %w", err)
+	}
+	bytes := handle.Bytes()
+	if len(bytes) < 8 {
+		return 0, fmt.Errorf("unexpected file handle size %d", len(bytes))
+	}
+	return binary.LittleEndian.Uint64(bytes[:8]), nil
+}
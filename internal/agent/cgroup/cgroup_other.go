@@ -0,0 +1,11 @@
+//go:build !linux
+
+package cgroup
+
+import "fmt"
+
+// resolveCgroupID is unavailable off Linux; callers fall back to userspace
+// telemetry collection (see internal/agent/bpf's non-Linux stub).
+func resolveCgroupID(path string) (uint64, error) {
+	return 0, fmt.Errorf("cgroup: not supported on this platform")
+}
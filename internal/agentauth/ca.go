@@ -0,0 +1,140 @@
+// Package agentauth implements mutual-TLS enrollment and authentication for
+// telemetry agents, mirroring the agents/bouncers trust model: agents present
+// a one-time enrollment token to obtain a short-lived client certificate, and
+// every subsequent request is authenticated by that certificate rather than
+// by anything carried in the request body.
+package agentauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+)
+
+// CAConfig configures the internal agent certificate authority.
+type CAConfig struct {
+	// KeyPEM is the PEM-encoded ECDSA private key that roots the CA. It is
+	// read from config (e.g. agentauth.ca_key_path) rather than generated
+	// at startup so the trust anchor survives restarts.
+	KeyPEM []byte
+	// CertPEM is the PEM-encoded CA certificate corresponding to KeyPEM.
+	CertPEM []byte
+	// CertTTL is how long issued agent certificates remain valid. Renewal
+	// must happen before this elapses.
+	CertTTL time.Duration
+}
+
+// CA signs short-lived agent client certificates against a fixed root.
+type CA struct {
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+	ttl  time.Duration
+}
+
+// NewCA loads the CA key/cert pair from cfg and returns a CA ready to sign
+// agent certificates.
+func NewCA(cfg CAConfig) (*CA, error) {
+	cert, err := parseCertPEM(cfg.CertPEM)
+	if err != nil {
+		return nil, fmt.Errorf("agentauth: parse ca cert: %w", err)
+	}
+	key, err := parseECKeyPEM(cfg.KeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("agentauth: parse ca key: %w", err)
+	}
+	ttl := cfg.CertTTL
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &CA{key: key, cert: cert, ttl: ttl}, nil
+}
+
+// AgentIdentity is encoded into the signed certificate's Subject (OU) and
+// SAN URIs so that downstream middleware can trust it without consulting the
+// request body.
+type AgentIdentity struct {
+	TenantID string
+	HostID   string
+	AgentID  string
+}
+
+// SignCSR checks csrDER's self-signature and returns a freshly signed leaf
+// certificate bound to identity, valid for the CA's configured TTL. The
+// CSR's own subject is ignored; it is not compared against identity.
+func (ca *CA) SignCSR(csrDER []byte, identity AgentIdentity) (*x509.Certificate, error) {
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, fmt.Errorf("agentauth: parse csr: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("agentauth: csr signature invalid: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("agentauth: generate serial: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:         identity.AgentID,
+			OrganizationalUnit: []string{identity.TenantID},
+		},
+		URIs:                  agentSANs(identity),
+		NotBefore:             now.Add(-5 * time.Minute), // tolerate agent clock skew
+		NotAfter:              now.Add(ca.ttl),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("agentauth: sign certificate: %w", err)
+	}
+	return x509.ParseCertificate(der)
+}
+
+// Chain returns the CA certificate agents should use to verify future
+// rotations (a single-level chain for now).
+func (ca *CA) Chain() *x509.Certificate {
+	return ca.cert
+}
+
+func agentSANs(id AgentIdentity) []*url.URL {
+	return []*url.URL{
+		{Scheme: "insec-agent", Host: id.TenantID, Path: "/" + id.HostID + "/" + id.AgentID},
+	}
+}
+
+func parseCertPEM(pemBytes []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parseECKeyPEM(pemBytes []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if key.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("unsupported curve %s, want P-256", key.Curve.Params().Name)
+	}
+	return key, nil
+}
@@ -0,0 +1,92 @@
+package agentauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCA generates a throwaway self-signed P-256 CA and wraps it in a
+// *CA, so SignCSR can be exercised without any external key material or
+// database.
+func newTestCA(t *testing.T, ttl time.Duration) *CA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	ca, err := NewCA(CAConfig{CertPEM: certPEM, KeyPEM: keyPEM, CertTTL: ttl})
+	require.NoError(t, err)
+	return ca
+}
+
+func newTestCSR(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.CertificateRequest{Subject: pkix.Name{CommonName: "agent"}}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	require.NoError(t, err)
+	return der
+}
+
+func TestCA_SignCSR_BindsAgentIdentity(t *testing.T) {
+	ca := newTestCA(t, time.Hour)
+	csr := newTestCSR(t)
+	identity := AgentIdentity{TenantID: "tenant-1", HostID: "host-1", AgentID: "agent-1"}
+
+	cert, err := ca.SignCSR(csr, identity)
+	require.NoError(t, err)
+	assert.Equal(t, "agent-1", cert.Subject.CommonName)
+	assert.Equal(t, []string{"tenant-1"}, cert.Subject.OrganizationalUnit)
+	require.Len(t, cert.URIs, 1)
+	assert.Equal(t, "tenant-1", cert.URIs[0].Host)
+	assert.Contains(t, cert.URIs[0].Path, "host-1")
+	assert.Contains(t, cert.URIs[0].Path, "agent-1")
+}
+
+func TestCA_SignCSR_RejectsBadSignature(t *testing.T) {
+	ca := newTestCA(t, time.Hour)
+	csr := newTestCSR(t)
+	csr[len(csr)-1] ^= 0xFF // corrupt the trailing signature bytes
+
+	_, err := ca.SignCSR(csr, AgentIdentity{AgentID: "agent-1"})
+	assert.Error(t, err)
+}
+
+func TestCA_SignCSR_ExpiryMatchesConfiguredTTL(t *testing.T) {
+	ca := newTestCA(t, 2*time.Hour)
+	csr := newTestCSR(t)
+
+	cert, err := ca.SignCSR(csr, AgentIdentity{AgentID: "agent-1"})
+	require.NoError(t, err)
+
+	ttl := cert.NotAfter.Sub(cert.NotBefore)
+	assert.InDelta(t, (2*time.Hour+5*time.Minute).Seconds(), ttl.Seconds(), 2, "cert validity should be ~TTL plus the 5m clock-skew tolerance baked into NotBefore")
+}
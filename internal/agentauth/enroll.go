@@ -0,0 +1,130 @@
+package agentauth
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EnrollmentToken is a one-time token an operator hands to a new agent
+// (out of band) so it can bootstrap a client certificate.
+type EnrollmentToken struct {
+	Token    string
+	TenantID string
+	HostID   string
+	ExpireAt time.Time
+	used     bool
+}
+
+// TokenStore persists enrollment tokens. The in-memory implementation is
+// sufficient for a single-node deployment; a DB-backed one should be swapped
+// in for multi-node setups.
+type TokenStore interface {
+	Get(token string) (*EnrollmentToken, error)
+	// Redeem atomically checks that token exists, isn't expired, and isn't
+	// already used, and marks it used, all under one critical section - so
+	// two concurrent enrollments presenting the same one-time token can't
+	// both pass the check before either marks it used.
+	Redeem(token string) (*EnrollmentToken, error)
+	Put(t *EnrollmentToken) error
+}
+
+// MemTokenStore is a TokenStore backed by an in-memory map, guarded by a
+// mutex since enrollment is low-volume and not worth a lock-free structure.
+type MemTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]*EnrollmentToken
+}
+
+// NewMemTokenStore returns an empty MemTokenStore.
+func NewMemTokenStore() *MemTokenStore {
+	return &MemTokenStore{tokens: make(map[string]*EnrollmentToken)}
+}
+
+func (s *MemTokenStore) Get(token string) (*EnrollmentToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[token]
+	if !ok {
+		return nil, fmt.Errorf("agentauth: unknown enrollment token")
+	}
+	return t, nil
+}
+
+func (s *MemTokenStore) Redeem(token string) (*EnrollmentToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[token]
+	if !ok {
+		return nil, fmt.Errorf("agentauth: unknown enrollment token")
+	}
+	if t.used {
+		return nil, fmt.Errorf("agentauth: enrollment token already used")
+	}
+	if time.Now().After(t.ExpireAt) {
+		return nil, fmt.Errorf("agentauth: enrollment token expired")
+	}
+	t.used = true
+	return t, nil
+}
+
+func (s *MemTokenStore) Put(t *EnrollmentToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[t.Token] = t
+	return nil
+}
+
+// Enroller handles the enrollment and renewal flows for agents.
+type Enroller struct {
+	ca     *CA
+	tokens TokenStore
+	revs   *RevocationList
+}
+
+// NewEnroller wires a CA, enrollment token store, and revocation list into
+// an Enroller ready to serve POST /v1/agents/enroll and the renewal endpoint.
+func NewEnroller(ca *CA, tokens TokenStore, revs *RevocationList) *Enroller {
+	return &Enroller{ca: ca, tokens: tokens, revs: revs}
+}
+
+// Enroll validates a one-time token and signs a CSR into a short-lived agent
+// certificate. The returned AgentIdentity is derived from the enrollment
+// token, never from anything the caller sends in the CSR's subject.
+//
+// Redeem checks and marks the token used atomically, so two concurrent
+// Enroll calls presenting the same token can't both get past the check
+// before either marks it used and both walk away with a signed certificate.
+func (e *Enroller) Enroll(token string, csrDER []byte) (cert []byte, identity AgentIdentity, err error) {
+	t, err := e.tokens.Redeem(token)
+	if err != nil {
+		return nil, AgentIdentity{}, err
+	}
+
+	identity = AgentIdentity{
+		TenantID: t.TenantID,
+		HostID:   t.HostID,
+		AgentID:  uuid.NewString(),
+	}
+	signed, err := e.ca.SignCSR(csrDER, identity)
+	if err != nil {
+		return nil, AgentIdentity{}, err
+	}
+	return signed.Raw, identity, nil
+}
+
+// Renew re-signs a CSR for an already-authenticated agent (identity comes
+// from the verified client certificate on the request, not from the CSR),
+// refusing agents whose current certificate has been revoked.
+func (e *Enroller) Renew(identity AgentIdentity, currentSerial string, csrDER []byte) ([]byte, error) {
+	if e.revs.IsRevoked(currentSerial) {
+		return nil, fmt.Errorf("agentauth: cannot renew a revoked certificate")
+	}
+	signed, err := e.ca.SignCSR(csrDER, identity)
+	if err != nil {
+		return nil, err
+	}
+	return signed.Raw, nil
+}
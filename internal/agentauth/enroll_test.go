@@ -0,0 +1,63 @@
+package agentauth
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemTokenStore_RedeemIsAtomicUnderConcurrency(t *testing.T) {
+	store := NewMemTokenStore()
+	require.NoError(t, store.Put(&EnrollmentToken{Token: "tok", TenantID: "t1", HostID: "h1", ExpireAt: time.Now().Add(time.Hour)}))
+
+	const attempts = 50
+	var wg sync.WaitGroup
+	successes := make([]bool, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.Redeem("tok")
+			successes[i] = err == nil
+		}(i)
+	}
+	wg.Wait()
+
+	successCount := 0
+	for _, ok := range successes {
+		if ok {
+			successCount++
+		}
+	}
+	assert.Equal(t, 1, successCount, "a one-time enrollment token must be redeemable exactly once, even under concurrent redemption")
+}
+
+func TestMemTokenStore_RedeemRejectsUnknownToken(t *testing.T) {
+	store := NewMemTokenStore()
+	_, err := store.Redeem("nonexistent")
+	assert.Error(t, err)
+}
+
+func TestMemTokenStore_RedeemRejectsExpiredToken(t *testing.T) {
+	store := NewMemTokenStore()
+	require.NoError(t, store.Put(&EnrollmentToken{Token: "tok", ExpireAt: time.Now().Add(-time.Minute)}))
+
+	_, err := store.Redeem("tok")
+	assert.Error(t, err)
+}
+
+func TestMemTokenStore_RedeemReturnsTokenDataOnSuccess(t *testing.T) {
+	store := NewMemTokenStore()
+	require.NoError(t, store.Put(&EnrollmentToken{Token: "tok", TenantID: "tenant-1", HostID: "host-1", ExpireAt: time.Now().Add(time.Hour)}))
+
+	got, err := store.Redeem("tok")
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-1", got.TenantID)
+	assert.Equal(t, "host-1", got.HostID)
+
+	_, err = store.Redeem("tok")
+	assert.Error(t, err, "a redeemed token must not be redeemable again")
+}
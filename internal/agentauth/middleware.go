@@ -0,0 +1,69 @@
+package agentauth
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAgentCert returns Gin middleware for agent endpoints (/v1/events
+// and friends) that enforces TLS client auth and trusts the verified
+// certificate's identity over anything present in the request body. The
+// server's TLS listener must be configured with
+// tls.Config{ClientAuth: tls.RequireAndVerifyClientCert} for this middleware
+// to ever see a populated PeerCertificates slice.
+func RequireAgentCert(revs *RevocationList) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.TLS == nil || len(c.Request.TLS.PeerCertificates) == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "client certificate required"})
+			return
+		}
+
+		leaf := c.Request.TLS.PeerCertificates[0]
+		serial := hex.EncodeToString(leaf.SerialNumber.Bytes())
+		if revs.IsRevoked(serial) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "certificate revoked"})
+			return
+		}
+
+		identity, err := identityFromCert(leaf)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "malformed agent certificate"})
+			return
+		}
+
+		// Downstream handlers must read identity from context, not from the
+		// JSON body, so a compromised or misconfigured agent cannot spoof
+		// another tenant's events.
+		c.Set("agent_id", identity.AgentID)
+		c.Set("agent_tenant_id", identity.TenantID)
+		c.Set("agent_host_id", identity.HostID)
+		c.Set("agent_cert_serial", serial)
+		c.Next()
+	}
+}
+
+// identityFromCert extracts the AgentIdentity embedded in the certificate's
+// Subject OU (tenant) and URI SAN (host/agent), the inverse of agentSANs.
+func identityFromCert(cert *x509.Certificate) (AgentIdentity, error) {
+	var tenantID string
+	if len(cert.Subject.OrganizationalUnit) > 0 {
+		tenantID = cert.Subject.OrganizationalUnit[0]
+	}
+
+	var hostID, agentID string
+	for _, u := range cert.URIs {
+		if u.Scheme != "insec-agent" {
+			continue
+		}
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) == 2 {
+			hostID, agentID = parts[0], parts[1]
+		}
+	}
+
+	return AgentIdentity{TenantID: tenantID, HostID: hostID, AgentID: agentID}, nil
+}
@@ -0,0 +1,61 @@
+package agentauth
+
+import (
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RevokedCert is a persisted revocation record, checked on every agent
+// request so a compromised agent certificate can be shut out immediately
+// rather than waiting for natural expiry.
+type RevokedCert struct {
+	SerialHex string    `json:"serial" gorm:"primaryKey"`
+	AgentID   string    `json:"agent_id" gorm:"index"`
+	Reason    string    `json:"reason"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// RevocationList checks and persists revoked agent certificate serials. It
+// keeps an in-memory mirror of the DB table so the hot path (one lookup per
+// request) never blocks on a round trip.
+type RevocationList struct {
+	db *gorm.DB
+
+	mu     sync.RWMutex
+	serials map[string]struct{}
+}
+
+// NewRevocationList loads existing revocations from db into memory.
+func NewRevocationList(db *gorm.DB) (*RevocationList, error) {
+	rl := &RevocationList{db: db, serials: make(map[string]struct{})}
+	var rows []RevokedCert
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		rl.serials[r.SerialHex] = struct{}{}
+	}
+	return rl, nil
+}
+
+// Revoke persists serialHex as revoked and updates the in-memory cache.
+func (rl *RevocationList) Revoke(serialHex, agentID, reason string) error {
+	rec := RevokedCert{SerialHex: serialHex, AgentID: agentID, Reason: reason, RevokedAt: time.Now()}
+	if err := rl.db.Create(&rec).Error; err != nil {
+		return err
+	}
+	rl.mu.Lock()
+	rl.serials[serialHex] = struct{}{}
+	rl.mu.Unlock()
+	return nil
+}
+
+// IsRevoked reports whether serialHex has been revoked.
+func (rl *RevocationList) IsRevoked(serialHex string) bool {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	_, revoked := rl.serials[serialHex]
+	return revoked
+}
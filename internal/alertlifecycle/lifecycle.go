@@ -0,0 +1,195 @@
+// Package alertlifecycle replaces Alert.Status's free-form string (and the
+// naked Assignee/ResolvedAt fields UpdateAlert used to set directly) with a
+// proper state machine: a fixed set of allowed transitions, an append-only
+// audit log of every transition an analyst makes (AlertTransition), and a
+// per-tenant per-severity SLA target (AlertSLA) a background monitor
+// (monitor.go) checks alerts against, emitting an sla_breach meta-alert
+// when one's been open too long.
+//
+// This package owns its own frozen, column-level views of the alerts,
+// alert_transitions, and alert_sla tables (store.go) rather than importing
+// server.Alert - server is package main, which nothing outside it can
+// import, the same reason internal/eventstore and internal/ueba each
+// define their own local row types for tables server owns.
+package alertlifecycle
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTransition is wrapped with the attempted from/to pair by
+// Transition, so a caller can errors.Is it while still getting a useful
+// message out of Error().
+var ErrInvalidTransition = errors.New("alertlifecycle: transition not allowed")
+
+// Status is one state in an alert's lifecycle.
+type Status string
+
+const (
+	StatusOpen          Status = "open"
+	StatusTriaged       Status = "triaged"
+	StatusInvestigating Status = "investigating"
+	StatusContained     Status = "contained"
+	StatusResolved      Status = "resolved"
+	StatusFalsePositive Status = "false_positive"
+	StatusSuppressed    Status = "suppressed"
+)
+
+// transitions is the full allowed-next-state table: open -> triaged ->
+// investigating -> contained -> one of the three terminal states. Triage
+// can also go straight to a terminal state (e.g. an alert triaged as
+// obviously benign shouldn't have to walk through investigating and
+// contained first to be marked false_positive).
+var transitions = map[Status][]Status{
+	StatusOpen:          {StatusTriaged, StatusFalsePositive, StatusSuppressed},
+	StatusTriaged:       {StatusInvestigating, StatusFalsePositive, StatusSuppressed},
+	StatusInvestigating: {StatusContained, StatusFalsePositive, StatusSuppressed},
+	StatusContained:     {StatusResolved, StatusFalsePositive, StatusSuppressed},
+	StatusResolved:      nil,
+	StatusFalsePositive: nil,
+	StatusSuppressed:    nil,
+}
+
+// terminalStatuses is checked by SLA monitoring: an alert in one of these
+// states has left the clock, regardless of how it got there.
+var terminalStatuses = map[Status]bool{
+	StatusResolved:      true,
+	StatusFalsePositive: true,
+	StatusSuppressed:    true,
+}
+
+// IsTerminal reports whether s is an end state no further transition
+// leaves.
+func IsTerminal(s Status) bool {
+	return terminalStatuses[s]
+}
+
+// IsValidTransition reports whether an alert may move from `from` to `to`.
+// Re-entering the same status is never valid - Transition is for state
+// changes, not for re-stamping a note onto the current one.
+func IsValidTransition(from, to Status) bool {
+	if from == to {
+		return false
+	}
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition is one append-only entry in an alert's AlertTransition audit
+// log: who moved it, from which status to which, and why.
+type Transition struct {
+	ID         string
+	AlertID    string
+	FromStatus Status
+	ToStatus   Status
+	Actor      string
+	Note       string
+	CreatedAt  time.Time
+}
+
+// AlertStore is the subset of alert persistence AlertService needs: reading
+// an alert's current status/tenant/severity and writing back a new status
+// (and, for a terminal one, the time it landed there).
+type AlertStore interface {
+	Load(alertID string) (*AlertSummary, error)
+	UpdateStatus(alertID string, status Status, resolvedAt *time.Time) error
+	BulkAssign(alertIDs []string, assignee string) error
+}
+
+// AlertSummary is the slice of an alert AlertService needs to validate and
+// apply a transition - not the full row, which server/alert_lifecycle_handlers.go's
+// GormAlertStore owns the shape of.
+type AlertSummary struct {
+	ID       string
+	TenantID string
+	Severity string
+	Status   Status
+}
+
+// TransitionStore persists and lists the append-only AlertTransition log.
+type TransitionStore interface {
+	Append(t Transition) error
+	ListByAlert(alertID string) ([]Transition, error)
+}
+
+// AlertService enforces the transition state machine and records every
+// change TransitionStore sees, the mechanism server/alert_lifecycle_handlers.go's
+// REST endpoints for transitions, bulk-assign, and an alert's timeline are
+// all built on.
+type AlertService struct {
+	alerts      AlertStore
+	transitions TransitionStore
+	idGen       func() string
+}
+
+// NewAlertService builds an AlertService. idGen mints each Transition's ID
+// (uuid.New().String in production, a fixed sequence in tests).
+func NewAlertService(alerts AlertStore, transitions TransitionStore, idGen func() string) *AlertService {
+	return &AlertService{alerts: alerts, transitions: transitions, idGen: idGen}
+}
+
+// Transition moves alertID from its current status to `to`, recording actor
+// and note in the append-only log, or returns an error without touching
+// anything if the move isn't allowed from the alert's current status.
+func (s *AlertService) Transition(alertID string, to Status, actor, note string) (*AlertSummary, error) {
+	alert, err := s.alerts.Load(alertID)
+	if err != nil {
+		return nil, fmt.Errorf("alertlifecycle: load alert %s: %w", alertID, err)
+	}
+	if !IsValidTransition(alert.Status, to) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, alert.Status, to)
+	}
+
+	var resolvedAt *time.Time
+	if IsTerminal(to) {
+		now := time.Now()
+		resolvedAt = &now
+	}
+	if err := s.alerts.UpdateStatus(alertID, to, resolvedAt); err != nil {
+		return nil, fmt.Errorf("alertlifecycle: update alert %s status: %w", alertID, err)
+	}
+
+	entry := Transition{
+		ID:         s.idGen(),
+		AlertID:    alertID,
+		FromStatus: alert.Status,
+		ToStatus:   to,
+		Actor:      actor,
+		Note:       note,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.transitions.Append(entry); err != nil {
+		return nil, fmt.Errorf("alertlifecycle: record transition for alert %s: %w", alertID, err)
+	}
+
+	alert.Status = to
+	return alert, nil
+}
+
+// BulkAssign reassigns every alert in alertIDs to assignee in one call,
+// without touching their status - assignment and lifecycle status are
+// independent axes (an alert can be reassigned mid-investigation).
+func (s *AlertService) BulkAssign(alertIDs []string, assignee string) error {
+	if len(alertIDs) == 0 {
+		return nil
+	}
+	if err := s.alerts.BulkAssign(alertIDs, assignee); err != nil {
+		return fmt.Errorf("alertlifecycle: bulk assign: %w", err)
+	}
+	return nil
+}
+
+// Timeline returns alertID's full transition history, oldest first.
+func (s *AlertService) Timeline(alertID string) ([]Transition, error) {
+	history, err := s.transitions.ListByAlert(alertID)
+	if err != nil {
+		return nil, fmt.Errorf("alertlifecycle: load timeline for alert %s: %w", alertID, err)
+	}
+	return history, nil
+}
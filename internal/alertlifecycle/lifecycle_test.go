@@ -0,0 +1,135 @@
+package alertlifecycle
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memAlertStore struct {
+	alerts map[string]*AlertSummary
+}
+
+func newMemAlertStore(alerts ...*AlertSummary) *memAlertStore {
+	m := &memAlertStore{alerts: make(map[string]*AlertSummary)}
+	for _, a := range alerts {
+		m.alerts[a.ID] = a
+	}
+	return m
+}
+
+func (m *memAlertStore) Load(alertID string) (*AlertSummary, error) {
+	a, ok := m.alerts[alertID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	cp := *a
+	return &cp, nil
+}
+
+func (m *memAlertStore) UpdateStatus(alertID string, status Status, resolvedAt *time.Time) error {
+	a, ok := m.alerts[alertID]
+	if !ok {
+		return errors.New("not found")
+	}
+	a.Status = status
+	return nil
+}
+
+func (m *memAlertStore) BulkAssign(alertIDs []string, assignee string) error {
+	for _, id := range alertIDs {
+		if _, ok := m.alerts[id]; !ok {
+			return errors.New("not found")
+		}
+	}
+	return nil
+}
+
+type memTransitionStore struct {
+	byAlert map[string][]Transition
+}
+
+func newMemTransitionStore() *memTransitionStore {
+	return &memTransitionStore{byAlert: make(map[string][]Transition)}
+}
+
+func (m *memTransitionStore) Append(t Transition) error {
+	m.byAlert[t.AlertID] = append(m.byAlert[t.AlertID], t)
+	return nil
+}
+
+func (m *memTransitionStore) ListByAlert(alertID string) ([]Transition, error) {
+	return m.byAlert[alertID], nil
+}
+
+func sequentialIDs() func() string {
+	n := 0
+	return func() string {
+		n++
+		return "id-" + string(rune('0'+n))
+	}
+}
+
+func TestIsValidTransition(t *testing.T) {
+	assert.True(t, IsValidTransition(StatusOpen, StatusTriaged))
+	assert.True(t, IsValidTransition(StatusTriaged, StatusFalsePositive), "triage can skip straight to a terminal state")
+	assert.False(t, IsValidTransition(StatusOpen, StatusResolved), "open must go through triaged before resolution")
+	assert.False(t, IsValidTransition(StatusOpen, StatusOpen), "re-entering the same status is never a valid transition")
+	assert.False(t, IsValidTransition(StatusResolved, StatusOpen), "a terminal status has no outgoing transitions")
+}
+
+func TestIsTerminal(t *testing.T) {
+	assert.True(t, IsTerminal(StatusResolved))
+	assert.True(t, IsTerminal(StatusFalsePositive))
+	assert.True(t, IsTerminal(StatusSuppressed))
+	assert.False(t, IsTerminal(StatusOpen))
+	assert.False(t, IsTerminal(StatusInvestigating))
+}
+
+func TestAlertService_TransitionAppliesValidMoveAndRecordsHistory(t *testing.T) {
+	alerts := newMemAlertStore(&AlertSummary{ID: "a1", TenantID: "t1", Severity: "high", Status: StatusOpen})
+	transitions := newMemTransitionStore()
+	svc := NewAlertService(alerts, transitions, sequentialIDs())
+
+	updated, err := svc.Transition("a1", StatusTriaged, "analyst-1", "looks real")
+	require.NoError(t, err)
+	assert.Equal(t, StatusTriaged, updated.Status)
+
+	history, err := svc.Timeline("a1")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, StatusOpen, history[0].FromStatus)
+	assert.Equal(t, StatusTriaged, history[0].ToStatus)
+	assert.Equal(t, "analyst-1", history[0].Actor)
+}
+
+func TestAlertService_TransitionRejectsInvalidMove(t *testing.T) {
+	alerts := newMemAlertStore(&AlertSummary{ID: "a1", Status: StatusOpen})
+	svc := NewAlertService(alerts, newMemTransitionStore(), sequentialIDs())
+
+	_, err := svc.Transition("a1", StatusResolved, "analyst-1", "")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidTransition))
+}
+
+func TestAlertService_TransitionLeavesAlertUntouchedOnInvalidMove(t *testing.T) {
+	alerts := newMemAlertStore(&AlertSummary{ID: "a1", Status: StatusOpen})
+	transitions := newMemTransitionStore()
+	svc := NewAlertService(alerts, transitions, sequentialIDs())
+
+	_, err := svc.Transition("a1", StatusResolved, "analyst-1", "")
+	require.Error(t, err)
+
+	loaded, err := alerts.Load("a1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusOpen, loaded.Status, "a rejected transition must not mutate the alert")
+	assert.Empty(t, transitions.byAlert["a1"], "a rejected transition must not be recorded")
+}
+
+func TestAlertService_BulkAssignIsNoopOnEmptyInput(t *testing.T) {
+	svc := NewAlertService(newMemAlertStore(), newMemTransitionStore(), sequentialIDs())
+	assert.NoError(t, svc.BulkAssign(nil, "analyst-1"))
+}
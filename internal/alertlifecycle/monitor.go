@@ -0,0 +1,111 @@
+package alertlifecycle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OpenAlert is the slice of a non-terminal alert the SLA monitor needs to
+// compute time-in-state against its tenant's target.
+type OpenAlert struct {
+	ID        string
+	TenantID  string
+	Severity  string
+	Status    Status
+	CreatedAt time.Time
+}
+
+// BreachAlertStore lists the alerts the SLA monitor has to check, and
+// records which ones it's already flagged so the same breach doesn't fire
+// a new meta-alert every time the monitor wakes up.
+type BreachAlertStore interface {
+	ListOpen() ([]OpenAlert, error)
+	HasBreachAlert(alertID string) (bool, error)
+}
+
+// BreachNotifier emits the sla_breach meta-alert for one breached alert.
+// Implemented in server/alert_lifecycle_handlers.go, since creating a real
+// Alert row means touching server.Alert, which this package can't import.
+type BreachNotifier interface {
+	NotifyBreach(alert OpenAlert, target time.Duration, elapsed time.Duration) error
+}
+
+// Monitor computes time-in-state for every open alert each time it runs,
+// and asks its BreachNotifier to raise an sla_breach meta-alert for any
+// that have crossed their tenant's severity target and haven't already
+// been flagged.
+type Monitor struct {
+	alerts   BreachAlertStore
+	slas     SLAStore
+	notifier BreachNotifier
+	logger   *logrus.Logger
+	now      func() time.Time
+}
+
+// NewMonitor builds a Monitor. slas may be nil, in which case every tenant
+// gets DefaultTargets.
+func NewMonitor(alerts BreachAlertStore, slas SLAStore, notifier BreachNotifier, logger *logrus.Logger) *Monitor {
+	return &Monitor{alerts: alerts, slas: slas, notifier: notifier, logger: logger, now: time.Now}
+}
+
+// CheckOnce runs a single SLA sweep, returning how many alerts it flagged
+// as newly breached.
+func (m *Monitor) CheckOnce() (int, error) {
+	open, err := m.alerts.ListOpen()
+	if err != nil {
+		return 0, fmt.Errorf("alertlifecycle: list open alerts: %w", err)
+	}
+
+	breached := 0
+	now := m.now()
+	for _, alert := range open {
+		target := TargetFor(m.slas, alert.TenantID, alert.Severity)
+		elapsed := now.Sub(alert.CreatedAt)
+		if elapsed < target {
+			continue
+		}
+		already, err := m.alerts.HasBreachAlert(alert.ID)
+		if err != nil {
+			return breached, fmt.Errorf("alertlifecycle: check existing breach for alert %s: %w", alert.ID, err)
+		}
+		if already {
+			continue
+		}
+		if err := m.notifier.NotifyBreach(alert, target, elapsed); err != nil {
+			return breached, fmt.Errorf("alertlifecycle: notify breach for alert %s: %w", alert.ID, err)
+		}
+		breached++
+	}
+	return breached, nil
+}
+
+// DefaultMonitorInterval is how often RunSLAMonitor wakes up when main
+// doesn't pick a different one.
+const DefaultMonitorInterval = 15 * time.Minute
+
+// RunSLAMonitor runs one sweep immediately, then blocks running another
+// every interval until stop is closed - meant to run in its own goroutine
+// from main, the alert-lifecycle equivalent of eventstore.RunPartitionMaintenance
+// and notifications.RunRetryLoop.
+func RunSLAMonitor(m *Monitor, interval time.Duration, stop <-chan struct{}) {
+	m.checkAndLog()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.checkAndLog()
+		}
+	}
+}
+
+func (m *Monitor) checkAndLog() {
+	if _, err := m.CheckOnce(); err != nil && m.logger != nil {
+		m.logger.WithError(err).Error("SLA breach sweep failed")
+	}
+}
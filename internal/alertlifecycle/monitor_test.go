@@ -0,0 +1,76 @@
+package alertlifecycle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memBreachAlertStore struct {
+	open     []OpenAlert
+	breached map[string]bool
+}
+
+func newMemBreachAlertStore(open ...OpenAlert) *memBreachAlertStore {
+	return &memBreachAlertStore{open: open, breached: make(map[string]bool)}
+}
+
+func (m *memBreachAlertStore) ListOpen() ([]OpenAlert, error) { return m.open, nil }
+
+func (m *memBreachAlertStore) HasBreachAlert(alertID string) (bool, error) {
+	return m.breached[alertID], nil
+}
+
+type recordingBreachNotifier struct {
+	notified []string
+}
+
+func (r *recordingBreachNotifier) NotifyBreach(alert OpenAlert, target, elapsed time.Duration) error {
+	r.notified = append(r.notified, alert.ID)
+	return nil
+}
+
+func TestMonitor_CheckOnceFlagsOnlyBreachedAlerts(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	alerts := newMemBreachAlertStore(
+		OpenAlert{ID: "fresh", TenantID: "t1", Severity: "critical", CreatedAt: now.Add(-time.Hour)},
+		OpenAlert{ID: "breached", TenantID: "t1", Severity: "critical", CreatedAt: now.Add(-5 * time.Hour)},
+	)
+	notifier := &recordingBreachNotifier{}
+	m := NewMonitor(alerts, nil, notifier, nil)
+	m.now = func() time.Time { return now }
+
+	count, err := m.CheckOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"breached"}, notifier.notified)
+}
+
+func TestMonitor_CheckOnceSkipsAlreadyFlaggedBreach(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	alerts := newMemBreachAlertStore(OpenAlert{ID: "breached", TenantID: "t1", Severity: "critical", CreatedAt: now.Add(-5 * time.Hour)})
+	alerts.breached["breached"] = true
+	notifier := &recordingBreachNotifier{}
+	m := NewMonitor(alerts, nil, notifier, nil)
+	m.now = func() time.Time { return now }
+
+	count, err := m.CheckOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Empty(t, notifier.notified, "an alert already flagged for breach must not be re-notified")
+}
+
+func TestMonitor_CheckOnceUsesTenantSLAOverride(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	alerts := newMemBreachAlertStore(OpenAlert{ID: "a1", TenantID: "t1", Severity: "low", CreatedAt: now.Add(-time.Hour)})
+	slas := newMemSLAStore(SLA{TenantID: "t1", Severity: "low", Target: 30 * time.Minute})
+	notifier := &recordingBreachNotifier{}
+	m := NewMonitor(alerts, slas, notifier, nil)
+	m.now = func() time.Time { return now }
+
+	count, err := m.CheckOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "a tightened tenant-specific SLA target should breach sooner than the generous default")
+}
@@ -0,0 +1,44 @@
+package alertlifecycle
+
+import "time"
+
+// DefaultTargets is the per-severity time-to-resolution target a tenant
+// gets when it has no AlertSLA rows of its own - generous enough not to
+// false-positive on a tenant that hasn't configured SLAs yet, tightening
+// with severity the same way this project's other severity-keyed defaults
+// do (see server/handlers.go's validSeverities).
+var DefaultTargets = map[string]time.Duration{
+	"critical": 4 * time.Hour,
+	"high":     24 * time.Hour,
+	"medium":   72 * time.Hour,
+	"low":      7 * 24 * time.Hour,
+}
+
+// SLA is one tenant's time-to-resolution target for one severity.
+type SLA struct {
+	TenantID string
+	Severity string
+	Target   time.Duration
+}
+
+// SLAStore reads (and lets an admin set) a tenant's per-severity SLA
+// targets - the AlertSLA table.
+type SLAStore interface {
+	Get(tenantID, severity string) (SLA, error)
+	Set(sla SLA) error
+	List(tenantID string) ([]SLA, error)
+}
+
+// TargetFor returns tenantID's configured target for severity, or
+// DefaultTargets' fallback if store has none on file.
+func TargetFor(store SLAStore, tenantID, severity string) time.Duration {
+	if store != nil {
+		if sla, err := store.Get(tenantID, severity); err == nil {
+			return sla.Target
+		}
+	}
+	if target, ok := DefaultTargets[severity]; ok {
+		return target
+	}
+	return DefaultTargets["low"]
+}
@@ -0,0 +1,62 @@
+package alertlifecycle
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memSLAStore struct {
+	byTenantSeverity map[string]SLA
+}
+
+func newMemSLAStore(slas ...SLA) *memSLAStore {
+	m := &memSLAStore{byTenantSeverity: make(map[string]SLA)}
+	for _, s := range slas {
+		m.byTenantSeverity[s.TenantID+"/"+s.Severity] = s
+	}
+	return m
+}
+
+func (m *memSLAStore) Get(tenantID, severity string) (SLA, error) {
+	s, ok := m.byTenantSeverity[tenantID+"/"+severity]
+	if !ok {
+		return SLA{}, errors.New("not found")
+	}
+	return s, nil
+}
+
+func (m *memSLAStore) Set(sla SLA) error {
+	m.byTenantSeverity[sla.TenantID+"/"+sla.Severity] = sla
+	return nil
+}
+
+func (m *memSLAStore) List(tenantID string) ([]SLA, error) {
+	var out []SLA
+	for _, s := range m.byTenantSeverity {
+		if s.TenantID == tenantID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func TestTargetFor_UsesConfiguredOverride(t *testing.T) {
+	store := newMemSLAStore(SLA{TenantID: "t1", Severity: "high", Target: time.Hour})
+	assert.Equal(t, time.Hour, TargetFor(store, "t1", "high"))
+}
+
+func TestTargetFor_FallsBackToDefaultWhenUnconfigured(t *testing.T) {
+	store := newMemSLAStore()
+	assert.Equal(t, DefaultTargets["critical"], TargetFor(store, "t1", "critical"))
+}
+
+func TestTargetFor_FallsBackToDefaultWhenStoreIsNil(t *testing.T) {
+	assert.Equal(t, DefaultTargets["low"], TargetFor(nil, "t1", "low"))
+}
+
+func TestTargetFor_UnknownSeverityFallsBackToLow(t *testing.T) {
+	assert.Equal(t, DefaultTargets["low"], TargetFor(nil, "t1", "made_up_severity"))
+}
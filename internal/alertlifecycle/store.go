@@ -0,0 +1,227 @@
+package alertlifecycle
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// alertRow is this package's own frozen, column-level view of the alerts
+// table (owned by server.Alert), covering only the columns GormAlertStore
+// needs. ID stays a uint here, matching server.Alert's auto-increment
+// primary key - AlertStore's own interface trades in string IDs (the same
+// c.Param("id") shape every other alert-touching handler uses), so this
+// package's stores are the only place that conversion happens.
+type alertRow struct {
+	ID         uint       `gorm:"column:id;primaryKey"`
+	TenantID   string     `gorm:"column:tenant_id"`
+	Severity   string     `gorm:"column:severity"`
+	Status     string     `gorm:"column:status"`
+	Assignee   *string    `gorm:"column:assignee"`
+	ResolvedAt *time.Time `gorm:"column:resolved_at"`
+	CreatedAt  time.Time  `gorm:"column:created"`
+}
+
+func (alertRow) TableName() string { return "alerts" }
+
+// GormAlertStore implements AlertStore and BreachAlertStore against the
+// alerts table.
+type GormAlertStore struct {
+	db *gorm.DB
+}
+
+// NewGormAlertStore wraps an existing *gorm.DB.
+func NewGormAlertStore(db *gorm.DB) *GormAlertStore {
+	return &GormAlertStore{db: db}
+}
+
+// Load implements AlertStore.
+func (s *GormAlertStore) Load(alertID string) (*AlertSummary, error) {
+	var row alertRow
+	if err := s.db.First(&row, "id = ?", alertID).Error; err != nil {
+		return nil, fmt.Errorf("load alert %s: %w", alertID, err)
+	}
+	return &AlertSummary{ID: strconv.FormatUint(uint64(row.ID), 10), TenantID: row.TenantID, Severity: row.Severity, Status: Status(row.Status)}, nil
+}
+
+// UpdateStatus implements AlertStore.
+func (s *GormAlertStore) UpdateStatus(alertID string, status Status, resolvedAt *time.Time) error {
+	updates := map[string]interface{}{"status": string(status)}
+	if resolvedAt != nil {
+		updates["resolved_at"] = *resolvedAt
+	}
+	err := s.db.Model(&alertRow{}).Where("id = ?", alertID).Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("update alert %s status: %w", alertID, err)
+	}
+	return nil
+}
+
+// BulkAssign implements AlertStore.
+func (s *GormAlertStore) BulkAssign(alertIDs []string, assignee string) error {
+	err := s.db.Model(&alertRow{}).Where("id IN ?", alertIDs).Update("assignee", assignee).Error
+	if err != nil {
+		return fmt.Errorf("bulk assign %d alerts: %w", len(alertIDs), err)
+	}
+	return nil
+}
+
+// openStatuses is every non-terminal Status - what ListOpen scans.
+var openStatuses = []string{
+	string(StatusOpen), string(StatusTriaged), string(StatusInvestigating), string(StatusContained),
+}
+
+// ListOpen implements BreachAlertStore.
+func (s *GormAlertStore) ListOpen() ([]OpenAlert, error) {
+	var rows []alertRow
+	if err := s.db.Where("status IN ?", openStatuses).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list open alerts: %w", err)
+	}
+	open := make([]OpenAlert, len(rows))
+	for i, row := range rows {
+		open[i] = OpenAlert{ID: strconv.FormatUint(uint64(row.ID), 10), TenantID: row.TenantID, Severity: row.Severity, Status: Status(row.Status), CreatedAt: row.CreatedAt}
+	}
+	return open, nil
+}
+
+// breachMetaRuleID is the Alert.RuleID value every sla_breach meta-alert
+// carries, so HasBreachAlert can find one for a given source alert without
+// a dedicated linking table.
+const breachMetaRuleID = "sla_breach"
+
+// HasBreachAlert implements BreachAlertStore, looking for a prior sla_breach
+// meta-alert whose Entities reference alertID (see
+// server/alert_lifecycle_handlers.go's NotifyBreach for how it's stamped).
+func (s *GormAlertStore) HasBreachAlert(alertID string) (bool, error) {
+	var count int64
+	err := s.db.Table("alerts").
+		Where("rule_id = ? AND entities ->> 'source_alert_id' = ?", breachMetaRuleID, alertID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("check existing breach alert for %s: %w", alertID, err)
+	}
+	return count > 0, nil
+}
+
+// transitionRow is this package's own gorm-mapped view of the
+// alert_transitions table it owns outright (unlike alerts, created by the
+// alert_lifecycle migration for this package specifically).
+type transitionRow struct {
+	ID         string    `gorm:"column:id;primaryKey"`
+	AlertID    string    `gorm:"column:alert_id;index"`
+	FromStatus string    `gorm:"column:from_status"`
+	ToStatus   string    `gorm:"column:to_status"`
+	Actor      string    `gorm:"column:actor"`
+	Note       string    `gorm:"column:note"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (transitionRow) TableName() string { return "alert_transitions" }
+
+// GormTransitionStore implements TransitionStore against the
+// alert_transitions table.
+type GormTransitionStore struct {
+	db *gorm.DB
+}
+
+// NewGormTransitionStore wraps an existing *gorm.DB.
+func NewGormTransitionStore(db *gorm.DB) *GormTransitionStore {
+	return &GormTransitionStore{db: db}
+}
+
+// Append implements TransitionStore. The table is append-only by
+// convention: no method here ever updates or deletes a row.
+func (s *GormTransitionStore) Append(t Transition) error {
+	row := transitionRow{
+		ID:         t.ID,
+		AlertID:    t.AlertID,
+		FromStatus: string(t.FromStatus),
+		ToStatus:   string(t.ToStatus),
+		Actor:      t.Actor,
+		Note:       t.Note,
+		CreatedAt:  t.CreatedAt,
+	}
+	if err := s.db.Create(&row).Error; err != nil {
+		return fmt.Errorf("append transition for alert %s: %w", t.AlertID, err)
+	}
+	return nil
+}
+
+// ListByAlert implements TransitionStore.
+func (s *GormTransitionStore) ListByAlert(alertID string) ([]Transition, error) {
+	var rows []transitionRow
+	if err := s.db.Where("alert_id = ?", alertID).Order("created_at ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list transitions for alert %s: %w", alertID, err)
+	}
+	history := make([]Transition, len(rows))
+	for i, row := range rows {
+		history[i] = Transition{
+			ID:         row.ID,
+			AlertID:    row.AlertID,
+			FromStatus: Status(row.FromStatus),
+			ToStatus:   Status(row.ToStatus),
+			Actor:      row.Actor,
+			Note:       row.Note,
+			CreatedAt:  row.CreatedAt,
+		}
+	}
+	return history, nil
+}
+
+// slaRow is this package's own gorm-mapped view of the alert_sla table.
+type slaRow struct {
+	TenantID      string    `gorm:"column:tenant_id;primaryKey"`
+	Severity      string    `gorm:"column:severity;primaryKey"`
+	TargetSeconds float64   `gorm:"column:target_seconds"`
+	UpdatedAt     time.Time `gorm:"column:updated_at"`
+}
+
+func (slaRow) TableName() string { return "alert_sla" }
+
+// GormSLAStore implements SLAStore against the alert_sla table.
+type GormSLAStore struct {
+	db *gorm.DB
+}
+
+// NewGormSLAStore wraps an existing *gorm.DB.
+func NewGormSLAStore(db *gorm.DB) *GormSLAStore {
+	return &GormSLAStore{db: db}
+}
+
+// Get implements SLAStore.
+func (s *GormSLAStore) Get(tenantID, severity string) (SLA, error) {
+	var row slaRow
+	err := s.db.Where("tenant_id = ? AND severity = ?", tenantID, severity).First(&row).Error
+	if err != nil {
+		return SLA{}, fmt.Errorf("load sla for %s/%s: %w", tenantID, severity, err)
+	}
+	return SLA{TenantID: row.TenantID, Severity: row.Severity, Target: time.Duration(row.TargetSeconds * float64(time.Second))}, nil
+}
+
+// Set implements SLAStore, creating tenantID's target for severity if it
+// doesn't already have one, or overwriting it if it does.
+func (s *GormSLAStore) Set(sla SLA) error {
+	row := slaRow{TenantID: sla.TenantID, Severity: sla.Severity, TargetSeconds: sla.Target.Seconds(), UpdatedAt: time.Now()}
+	err := s.db.Where(slaRow{TenantID: sla.TenantID, Severity: sla.Severity}).
+		Assign(row).
+		FirstOrCreate(&row).Error
+	if err != nil {
+		return fmt.Errorf("set sla for %s/%s: %w", sla.TenantID, sla.Severity, err)
+	}
+	return nil
+}
+
+// List implements SLAStore.
+func (s *GormSLAStore) List(tenantID string) ([]SLA, error) {
+	var rows []slaRow
+	if err := s.db.Where("tenant_id = ?", tenantID).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list slas for %s: %w", tenantID, err)
+	}
+	slas := make([]SLA, len(rows))
+	for i, row := range rows {
+		slas[i] = SLA{TenantID: row.TenantID, Severity: row.Severity, Target: time.Duration(row.TargetSeconds * float64(time.Second))}
+	}
+	return slas, nil
+}
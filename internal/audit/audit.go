@@ -0,0 +1,93 @@
+// Package audit provides a pluggable, tamper-evident audit trail. Every
+// Entry is chained to the previous one via a SHA-256 hash over its
+// canonicalized JSON, so a single fan-out Logger can write to several
+// Sink implementations (file, syslog, webhook) while still letting an
+// operator detect if any one of them was edited after the fact.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Entry is one audit record. Hash is computed over everything except itself:
+// SHA-256(PrevHash || canonicalJSON(entry without PrevHash/Hash)).
+type Entry struct {
+	ID        string                 `json:"id"`
+	Timestamp time.Time              `json:"timestamp"`
+	Actor     string                 `json:"actor"`
+	Operation string                 `json:"operation"` // CREATE, UPDATE, DELETE, ...
+	Resource  string                 `json:"resource"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	PrevHash  string                 `json:"prev_hash"`
+	Hash      string                 `json:"hash"`
+}
+
+// Sink is one audit backend. Implementations must be safe for concurrent
+// use, since the Logger fans writes out to every configured sink.
+type Sink interface {
+	Write(ctx context.Context, e Entry) error
+	Reload() error
+	Close() error
+}
+
+// canonicalJSON produces a stable byte representation of an entry's
+// content fields (object keys sorted) so the hash is reproducible
+// independent of map iteration order.
+func canonicalJSON(e Entry) ([]byte, error) {
+	// Re-marshal through a map with sorted keys so the hash depends on
+	// the field names, not on the struct's field declaration order that
+	// encoding/json would otherwise emit.
+	raw, err := json.Marshal(struct {
+		ID        string                 `json:"id"`
+		Timestamp time.Time              `json:"timestamp"`
+		Actor     string                 `json:"actor"`
+		Operation string                 `json:"operation"`
+		Resource  string                 `json:"resource"`
+		Details   map[string]interface{} `json:"details,omitempty"`
+	}{e.ID, e.Timestamp, e.Actor, e.Operation, e.Resource, e.Details})
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	ordered := make([]byte, 0, len(raw))
+	ordered = append(ordered, '{')
+	for i, k := range keys {
+		if i > 0 {
+			ordered = append(ordered, ',')
+		}
+		kb, _ := json.Marshal(k)
+		vb, _ := json.Marshal(m[k])
+		ordered = append(ordered, kb...)
+		ordered = append(ordered, ':')
+		ordered = append(ordered, vb...)
+	}
+	ordered = append(ordered, '}')
+	return ordered, nil
+}
+
+// ComputeHash returns SHA-256(prevHash || canonicalJSON(e)) hex-encoded.
+func ComputeHash(prevHash string, e Entry) (string, error) {
+	body, err := canonicalJSON(e)
+	if err != nil {
+		return "", fmt.Errorf("audit: canonicalize entry: %w", err)
+	}
+	h := sha256.New()
+	h.Write([]byte(prevHash))
+	h.Write(body)
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
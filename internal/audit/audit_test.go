@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tamperFileEntry rewrites the first occurrence of old with new in path's
+// raw bytes, simulating an after-the-fact edit to an already-written audit
+// entry without touching its recorded Hash.
+func tamperFileEntry(t *testing.T, path, old, new string) {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	tampered := bytes.Replace(raw, []byte(old), []byte(new), 1)
+	require.NoError(t, os.WriteFile(path, tampered, 0o640))
+}
+
+func TestComputeHash_DeterministicAndSensitiveToPrevHash(t *testing.T) {
+	e := Entry{ID: "1", Timestamp: time.Unix(0, 0).UTC(), Actor: "alice", Operation: "CREATE", Resource: "alert:1"}
+
+	h1, err := ComputeHash("", e)
+	require.NoError(t, err)
+	h2, err := ComputeHash("", e)
+	require.NoError(t, err)
+	assert.Equal(t, h1, h2, "hashing the same entry twice must be deterministic")
+
+	h3, err := ComputeHash("some-prev-hash", e)
+	require.NoError(t, err)
+	assert.NotEqual(t, h1, h3, "changing prevHash must change the resulting hash")
+}
+
+func TestComputeHash_IgnoresMapIterationOrder(t *testing.T) {
+	e1 := Entry{ID: "1", Operation: "CREATE", Details: map[string]interface{}{"a": 1, "b": 2}}
+	e2 := Entry{ID: "1", Operation: "CREATE", Details: map[string]interface{}{"b": 2, "a": 1}}
+
+	h1, err := ComputeHash("", e1)
+	require.NoError(t, err)
+	h2, err := ComputeHash("", e2)
+	require.NoError(t, err)
+	assert.Equal(t, h1, h2, "canonicalJSON must sort keys so map order never changes the hash")
+}
+
+func TestVerifyChain_CleanChainAndTailHash(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.ndjson")
+
+	sink, err := NewFileSink(path, 0)
+	require.NoError(t, err)
+	logger := NewLogger(logrus.New(), "", sink)
+
+	for i := 0; i < 3; i++ {
+		e := Entry{ID: string(rune('a' + i)), Operation: "CREATE", Resource: "alert"}
+		require.NoError(t, logger.Log(context.Background(), e))
+	}
+	require.NoError(t, logger.Close())
+
+	brokenID, tailHash, err := VerifyChain(path)
+	require.NoError(t, err)
+	assert.Empty(t, brokenID, "a chain written straight through Logger must verify clean")
+	assert.NotEmpty(t, tailHash)
+}
+
+func TestVerifyChain_DetectsTamperedEntry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.ndjson")
+
+	sink, err := NewFileSink(path, 0)
+	require.NoError(t, err)
+	logger := NewLogger(logrus.New(), "", sink)
+	require.NoError(t, logger.Log(context.Background(), Entry{ID: "1", Operation: "CREATE", Resource: "alert"}))
+	require.NoError(t, logger.Log(context.Background(), Entry{ID: "2", Operation: "UPDATE", Resource: "alert"}))
+	require.NoError(t, logger.Close())
+
+	tamperFileEntry(t, path, "UPDATE", "DELETE")
+
+	brokenID, _, err := VerifyChain(path)
+	require.NoError(t, err)
+	assert.Equal(t, "2", brokenID, "the entry whose content was edited after signing should be reported broken")
+}
+
+func TestVerifyChain_MissingFileVerifiesCleanWithEmptyHash(t *testing.T) {
+	brokenID, lastHash, err := VerifyChain(filepath.Join(t.TempDir(), "does-not-exist.ndjson"))
+	require.NoError(t, err)
+	assert.Empty(t, brokenID)
+	assert.Empty(t, lastHash)
+}
+
+func TestNewLoggerVerifyingChain_SeedsFromExistingTail(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.ndjson")
+
+	sink, err := NewFileSink(path, 0)
+	require.NoError(t, err)
+	first := NewLogger(logrus.New(), "", sink)
+	require.NoError(t, first.Log(context.Background(), Entry{ID: "1", Operation: "CREATE", Resource: "alert"}))
+	require.NoError(t, first.Close())
+
+	sink2, err := NewFileSink(path, 0)
+	require.NoError(t, err)
+	restarted, err := NewLoggerVerifyingChain(logrus.New(), path, false, sink2)
+	require.NoError(t, err)
+	require.NoError(t, restarted.Log(context.Background(), Entry{ID: "2", Operation: "UPDATE", Resource: "alert"}))
+	require.NoError(t, restarted.Close())
+
+	brokenID, _, err := VerifyChain(path)
+	require.NoError(t, err)
+	assert.Empty(t, brokenID, "restarting with NewLoggerVerifyingChain must extend the existing chain, not break it")
+}
+
+func TestNewLoggerVerifyingChain_RefusesStartWhenBrokenAndStrict(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.ndjson")
+
+	sink, err := NewFileSink(path, 0)
+	require.NoError(t, err)
+	logger := NewLogger(logrus.New(), "", sink)
+	require.NoError(t, logger.Log(context.Background(), Entry{ID: "1", Operation: "CREATE", Resource: "alert"}))
+	require.NoError(t, logger.Close())
+
+	tamperFileEntry(t, path, "CREATE", "DESTROY")
+
+	_, err = NewLoggerVerifyingChain(logrus.New(), path, true)
+	assert.Error(t, err, "StrictChain must refuse to start when the existing chain is already broken")
+
+	l, err := NewLoggerVerifyingChain(logrus.New(), path, false)
+	require.NoError(t, err, "without StrictChain, a broken chain should only warn, not refuse to start")
+	require.NotNil(t, l)
+}
@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileSink writes newline-delimited JSON audit entries with simple
+// size-based rotation: when the active file exceeds MaxBytes, it's renamed
+// with a timestamp suffix and a fresh file is opened.
+type FileSink struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+
+	f       *os.File
+	w       *bufio.Writer
+	written int64
+}
+
+// NewFileSink opens (or creates) path for appending, ready to accept
+// Write calls.
+func NewFileSink(path string, maxBytes int64) (*FileSink, error) {
+	s := &FileSink{path: path, maxBytes: maxBytes}
+	if err := s.openCurrent(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) openCurrent() error {
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("audit: open file sink: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.f = f
+	s.w = bufio.NewWriter(f)
+	s.written = info.Size()
+	return nil
+}
+
+// Write appends e as one NDJSON line, rotating first if MaxBytes would be
+// exceeded.
+func (s *FileSink) Write(_ context.Context, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("audit: marshal entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	if s.maxBytes > 0 && s.written+int64(len(line)) > s.maxBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.w.Write(line); err != nil {
+		return err
+	}
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	s.written += int64(len(line))
+	return nil
+}
+
+func (s *FileSink) rotate() error {
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	s.f.Close()
+
+	rotated := s.path + "." + time.Now().UTC().Format("20060102T150405")
+	if err := os.Rename(s.path, rotated); err != nil {
+		return fmt.Errorf("audit: rotate file sink: %w", err)
+	}
+	return s.openCurrent()
+}
+
+// Reload is a no-op for FileSink; there's no external config to re-read.
+func (s *FileSink) Reload() error { return nil }
+
+// Close flushes and closes the underlying file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	return s.f.Close()
+}
+
+// VerifyChain walks an NDJSON audit file and reports the first entry whose
+// Hash doesn't match ComputeHash(PrevHash, entry) — i.e. the first sign of
+// tampering. It returns ("", nil) if the whole chain verifies. lastHash is
+// always the Hash of the last entry successfully walked (empty for a
+// missing/empty file), letting callers seed a new Logger so it extends the
+// existing chain instead of starting a fresh one.
+func VerifyChain(path string) (brokenEntryID string, lastHash string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	var prevHash string
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return "", prevHash, fmt.Errorf("audit: parse entry: %w", err)
+		}
+		if e.PrevHash != prevHash {
+			return e.ID, prevHash, nil
+		}
+		want, err := ComputeHash(e.PrevHash, e)
+		if err != nil {
+			return "", prevHash, err
+		}
+		if want != e.Hash {
+			return e.ID, prevHash, nil
+		}
+		prevHash = e.Hash
+	}
+	return "", prevHash, scanner.Err()
+}
@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Logger chains and fans out audit entries to every configured Sink. A
+// failing sink never stops the others from being written: every sink is
+// tried in turn, each failure is logged, and Log returns the first one.
+type Logger struct {
+	mu       sync.Mutex
+	lastHash string
+	sinks    []Sink
+	logger   *logrus.Logger
+
+	// StrictChain refuses to append further entries once a broken chain is
+	// detected at startup, rather than just warning. Operators flip this on
+	// once they trust their verification tooling.
+	StrictChain bool
+
+	// chainBroken is set by NewLoggerVerifyingChain when startup
+	// verification found a tampered entry; Log honors it once StrictChain
+	// is set.
+	chainBroken bool
+}
+
+// NewLogger builds a Logger with the given sinks, seeding the chain from
+// lastHash (the Hash of the last entry previously written, or "" for a
+// fresh chain). Callers that can verify an existing audit file before
+// constructing the logger should prefer NewLoggerVerifyingChain instead.
+func NewLogger(logger *logrus.Logger, lastHash string, sinks ...Sink) *Logger {
+	return &Logger{logger: logger, lastHash: lastHash, sinks: sinks}
+}
+
+// NewLoggerVerifyingChain verifies the hash chain already on disk at path
+// (if any) and seeds the new Logger's lastHash from its tail, so the first
+// entry this Logger writes extends the existing chain instead of starting a
+// fresh, disconnected one - a plain restart must not look like tampering. A
+// missing file verifies clean with an empty lastHash.
+//
+// If verification finds a broken link, the break is logged at warn level
+// and, when strictChain is true, an error is returned so the caller refuses
+// to start rather than append onto a chain already known to be tampered
+// with.
+func NewLoggerVerifyingChain(logger *logrus.Logger, path string, strictChain bool, sinks ...Sink) (*Logger, error) {
+	brokenID, lastHash, err := VerifyChain(path)
+	if err != nil {
+		return nil, fmt.Errorf("audit: verify chain: %w", err)
+	}
+
+	l := &Logger{logger: logger, lastHash: lastHash, sinks: sinks, StrictChain: strictChain}
+	if brokenID != "" {
+		logger.WithField("broken_entry_id", brokenID).Warn("audit: existing hash chain is broken")
+		l.chainBroken = true
+		if strictChain {
+			return nil, fmt.Errorf("audit: chain verification failed at entry %s, refusing to start with StrictChain enabled", brokenID)
+		}
+	}
+	return l, nil
+}
+
+// Log appends e to the chain (filling in PrevHash/Hash) and fans it out to
+// every sink. Per-sink failures are isolated: a webhook timing out must not
+// stop the file sink from getting written.
+func (l *Logger) Log(ctx context.Context, e Entry) error {
+	l.mu.Lock()
+	if l.chainBroken && l.StrictChain {
+		l.mu.Unlock()
+		return fmt.Errorf("audit: refusing to append, chain was broken at startup and StrictChain is set")
+	}
+	e.PrevHash = l.lastHash
+	hash, err := ComputeHash(e.PrevHash, e)
+	if err != nil {
+		l.mu.Unlock()
+		return fmt.Errorf("audit: compute hash: %w", err)
+	}
+	e.Hash = hash
+	l.lastHash = hash
+	l.mu.Unlock()
+
+	var firstErr error
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, e); err != nil {
+			l.logger.WithError(err).WithField("entry_id", e.ID).Error("audit sink write failed")
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// Close closes every sink, returning the first error encountered.
+func (l *Logger) Close() error {
+	var firstErr error
+	for _, sink := range l.sinks {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
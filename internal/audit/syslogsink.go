@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+)
+
+// SyslogSink forwards audit entries to a local or remote syslog daemon as
+// RFC 5424 messages, JSON-encoded in the message body so downstream log
+// pipelines can still parse structured fields.
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink dials network (e.g. "udp", "tcp") at raddr, or the local
+// syslog daemon if network/raddr are empty.
+func NewSyslogSink(network, raddr, tag string) (*SyslogSink, error) {
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, fmt.Errorf("audit: dial syslog: %w", err)
+	}
+	return &SyslogSink{writer: w}, nil
+}
+
+// Write emits e as a single syslog INFO message.
+func (s *SyslogSink) Write(_ context.Context, e Entry) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("audit: marshal entry: %w", err)
+	}
+	return s.writer.Info(string(body))
+}
+
+// Reload is a no-op; syslog connections don't have reloadable config here.
+func (s *SyslogSink) Reload() error { return nil }
+
+// Close closes the underlying syslog connection.
+func (s *SyslogSink) Close() error { return s.writer.Close() }
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSink POSTs each audit entry to a configured URL, retrying with
+// exponential backoff. Write blocks for the whole retry sequence, and
+// Logger.Log calls its sinks one after another, so a failing webhook
+// delays, but does not prevent, writes to the sinks after it.
+type WebhookSink struct {
+	url        string
+	httpClient *http.Client
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+// NewWebhookSink posts to url with the given retry policy.
+func NewWebhookSink(url string, maxRetries int, baseDelay time.Duration) *WebhookSink {
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	if baseDelay <= 0 {
+		baseDelay = 200 * time.Millisecond
+	}
+	return &WebhookSink{
+		url:        url,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		maxRetries: maxRetries,
+		baseDelay:  baseDelay,
+	}
+}
+
+// Write posts e as JSON, retrying non-2xx responses and network errors with
+// exponential backoff before giving up.
+func (w *WebhookSink) Write(ctx context.Context, e Entry) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("audit: marshal entry: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= w.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := w.baseDelay * time.Duration(1<<(attempt-1))
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("audit: build webhook request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := w.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("audit: webhook returned %d", resp.StatusCode)
+	}
+	return fmt.Errorf("audit: webhook delivery failed after %d attempts: %w", w.maxRetries+1, lastErr)
+}
+
+// Reload is a no-op; the webhook URL is set at construction.
+func (w *WebhookSink) Reload() error { return nil }
+
+// Close is a no-op; WebhookSink holds no persistent connection.
+func (w *WebhookSink) Close() error { return nil }
@@ -0,0 +1,408 @@
+// Package auth implements user authentication: password hashing, JWT
+// issuance/validation, and (see refresh.go) refresh-token rotation with
+// server-side revocation.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// User is the persisted account record AuthService authenticates against.
+type User struct {
+	ID        string
+	Email     string
+	Name      string
+	Password  string // PHC-formatted hash (see password.go)
+	Role      string
+	TenantID  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// MFA fields back the TOTP enrollment/verification flow in totp.go.
+	// MFASecret is set by EnrollTOTP and stays populated across
+	// re-enrollment; MFAEnabled only flips true once ConfirmTOTP proves the
+	// user can generate valid codes with it.
+	MFASecret  string
+	MFAEnabled bool
+	// MFARecoveryCodes holds single-use recovery codes as sha256 hex
+	// digests, never in cleartext - ConfirmTOTP hands the cleartext codes
+	// back to the caller exactly once, the same way a password is never
+	// stored in cleartext either.
+	MFARecoveryCodes []string
+	// MFALastUsedStep is the TOTP time-step of the last code this user
+	// redeemed, so the same 30s code can't be replayed twice.
+	MFALastUsedStep int64
+}
+
+// UserRepository is the persistence boundary AuthService depends on, so it
+// can be unit-tested against a mock rather than a real database.
+type UserRepository interface {
+	GetUserByID(id string) (*User, error)
+	GetUserByEmail(email string) (*User, error)
+	CreateUser(user *User) error
+	UpdateUser(user *User) error
+	DeleteUser(id string) error
+}
+
+// Claims is the JWT payload issued for an authenticated session.
+type Claims struct {
+	UserID   string `json:"user_id"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	TenantID string `json:"tenant_id"`
+
+	// MFAVerifiedAt, when set, lets MFAMiddleware accept this access token
+	// for a gated route without a fresh X-MFA-Token assertion as long as
+	// it's within the policy window (see mfa.go).
+	MFAVerifiedAt *time.Time `json:"mfa_verified_at,omitempty"`
+
+	// Impersonator, Justification, and Nonce are set only on tokens minted
+	// by Impersonate (see impersonate.go): Impersonator is the original
+	// caller's user_id, Justification is the reason they gave for the
+	// impersonation request, and Nonce lets that specific token be revoked
+	// server-side without touching any other token either party holds.
+	// Carrying Justification on the token (not just in the /impersonate
+	// audit entry) lets every request made under it record why, not just
+	// who, without a second lookup.
+	Impersonator  *string `json:"impersonator,omitempty"`
+	Justification string  `json:"justification,omitempty"`
+	Nonce         string  `json:"nonce,omitempty"`
+
+	jwt.RegisteredClaims
+}
+
+// DefaultAccessTokenTTL is how long an access token is valid when callers
+// use NewAuthService instead of NewAuthServiceWithExpiration.
+const DefaultAccessTokenTTL = 15 * time.Minute
+
+// AuthService authenticates users and issues/validates their JWTs.
+type AuthService struct {
+	repo      UserRepository
+	jwtSecret string
+	accessTTL time.Duration
+
+	// storeMu/store back the revocation and refresh-token rotation flow
+	// (this file and refresh.go/impersonate.go); left as a
+	// lazily-initialized zero value so code built against the original
+	// NewAuthService/NewAuthServiceWithExpiration constructors keeps
+	// working unchanged, defaulting to an in-memory TokenStore.
+	storeMu sync.Mutex
+	store   TokenStore
+
+	// hasherMu/hasher back HashPassword/VerifyPassword's password-hashing
+	// policy (password.go); lazily initialized to bcrypt at the package
+	// default cost for the same reason as store above.
+	hasherMu sync.Mutex
+	hasher   PasswordHasher
+
+	// clock backs every time.Now() this service would otherwise call
+	// directly (clock.go); nil defaults to the real wall clock, so only
+	// tests that need deterministic expiry - via NewAuthServiceWithClock and
+	// a FakeClock - ever see anything else.
+	clock Clock
+
+	// dummyHashMu/dummyHash cache the fixed password hash AuthenticateUser
+	// verifies against on a user-not-found path, so that path costs the
+	// same one Verify call a real password mismatch does (see
+	// dummyPasswordHash).
+	dummyHashMu sync.Mutex
+	dummyHash   string
+
+	// challengeMu/challenges back IssueMFAChallenge/redeemMFAChallenge
+	// (mfa.go), lazily initialized the same way store/hasher above are -
+	// in-memory is correct for a single instance; a multi-replica
+	// deployment would need this moved onto the same Redis store as
+	// tokenStore.
+	challengeMu sync.Mutex
+	challenges  map[string]MFAChallenge
+}
+
+// NewAuthService builds an AuthService with the default access-token TTL
+// and an in-memory TokenStore.
+func NewAuthService(repo UserRepository, jwtSecret string) *AuthService {
+	return NewAuthServiceWithExpiration(repo, jwtSecret, DefaultAccessTokenTTL)
+}
+
+// NewAuthServiceWithExpiration builds an AuthService with a custom
+// access-token TTL, primarily so tests can exercise expiry deterministically
+// without waiting out the real default. Its TokenStore is in-memory; use
+// NewAuthServiceWithStore to share revocation state across replicas.
+func NewAuthServiceWithExpiration(repo UserRepository, jwtSecret string, accessTTL time.Duration) *AuthService {
+	return &AuthService{repo: repo, jwtSecret: jwtSecret, accessTTL: accessTTL}
+}
+
+// NewAuthServiceWithStore builds an AuthService backed by store instead of
+// the default in-memory TokenStore - typically a RedisTokenStore, so
+// revocation and refresh-family state is consistent across every server
+// replica rather than per-process.
+func NewAuthServiceWithStore(repo UserRepository, jwtSecret string, accessTTL time.Duration, store TokenStore) *AuthService {
+	return &AuthService{repo: repo, jwtSecret: jwtSecret, accessTTL: accessTTL, store: store}
+}
+
+// NewAuthServiceWithOptions is the fully-configurable constructor: store
+// may be nil to default to an in-memory TokenStore (see NewAuthServiceWithStore),
+// and hasher may be nil to default to bcrypt at the package default cost
+// (see HashPassword). Pass an Argon2idHasher or ScryptHasher here to move a
+// deployment's password-hashing policy off bcrypt.
+func NewAuthServiceWithOptions(repo UserRepository, jwtSecret string, accessTTL time.Duration, store TokenStore, hasher PasswordHasher) *AuthService {
+	return &AuthService{repo: repo, jwtSecret: jwtSecret, accessTTL: accessTTL, store: store, hasher: hasher}
+}
+
+// NewAuthServiceWithClock builds on NewAuthServiceWithOptions with one more
+// override: clock, which may be nil to default to the real wall clock.
+// Tests that need to exercise expiry, revocation TTLs, or clock-skew
+// tolerance deterministically should pass a tests/helpers.FakeClock here
+// instead of sleeping past a short-lived token's expiry.
+func NewAuthServiceWithClock(repo UserRepository, jwtSecret string, accessTTL time.Duration, store TokenStore, hasher PasswordHasher, clock Clock) *AuthService {
+	return &AuthService{repo: repo, jwtSecret: jwtSecret, accessTTL: accessTTL, store: store, hasher: hasher, clock: clock}
+}
+
+// tokenStore lazily initializes the service's TokenStore so the zero value
+// of AuthService (as constructed by the existing test suite, which predates
+// revocation support) still works.
+func (s *AuthService) tokenStore() TokenStore {
+	s.storeMu.Lock()
+	defer s.storeMu.Unlock()
+	if s.store == nil {
+		s.store = NewMemoryTokenStore()
+	}
+	return s.store
+}
+
+// passwordHasher lazily initializes the service's PasswordHasher so the
+// zero value of AuthService (as constructed by the existing test suite,
+// which predates pluggable hashing) still works, defaulting to bcrypt.
+func (s *AuthService) passwordHasher() PasswordHasher {
+	s.hasherMu.Lock()
+	defer s.hasherMu.Unlock()
+	if s.hasher == nil {
+		s.hasher = NewBcryptHasher(bcrypt.DefaultCost)
+	}
+	return s.hasher
+}
+
+// AuthenticateUser validates email/password and returns a signed access
+// token on success. If user has TOTP MFA enabled (see totp.go), it instead
+// returns a short-lived challenge token and ErrMFARequired; the caller must
+// redeem that token via AuthenticateMFA to complete login.
+//
+// When email doesn't match any user, AuthenticateUser still runs a dummy
+// password verify against a fixed hash before returning ErrUserNotFound, so
+// that path costs the same wall-clock time as a real password mismatch
+// (ErrInvalidCredentials) - otherwise an attacker could distinguish a valid
+// email from an invalid one purely by response latency.
+func (s *AuthService) AuthenticateUser(email, password string) (string, error) {
+	user, err := s.repo.GetUserByEmail(email)
+	if err != nil {
+		s.VerifyPassword(password, s.dummyPasswordHash())
+		return "", newAuthError(CodeUserNotFound, "user not found", err)
+	}
+	if !s.VerifyPassword(password, user.Password) {
+		return "", ErrInvalidCredentials
+	}
+	s.rehashIfNeeded(user, password)
+	if user.MFAEnabled {
+		return s.issueMFAChallengeToken(user.ID), ErrMFARequired
+	}
+	return s.issueAccessToken(user)
+}
+
+// rehashIfNeeded transparently upgrades user's stored hash to the
+// service's current PasswordHasher policy when it was hashed under a
+// different algorithm or with weaker parameters, so an account migrates to
+// a stronger policy the next time it logs in rather than needing a bulk
+// migration. Best-effort: a hashing or persistence failure here must never
+// fail an otherwise-successful login.
+func (s *AuthService) rehashIfNeeded(user *User, password string) {
+	hasher := s.passwordHasher()
+	if hasher.Owns(user.Password) && !hasher.NeedsRehash(user.Password) {
+		return
+	}
+	newHash, err := hasher.Hash(password)
+	if err != nil {
+		return
+	}
+	user.Password = newHash
+	_ = s.repo.UpdateUser(user)
+}
+
+// dummyVerifyPassword is hashed once per service (lazily, via
+// dummyPasswordHash) and never compared against a real user - it only
+// exists so AuthenticateUser's user-not-found path has something to run a
+// real Verify against.
+const dummyVerifyPassword = "insec-timing-parity-dummy-password"
+
+// fallbackDummyHash is a well-known bcrypt test vector (the hash of
+// "secret"), used only if hashing dummyVerifyPassword with the service's
+// configured PasswordHasher fails - so a hashing error never skips the
+// timing-parity verify entirely.
+const fallbackDummyHash = "$2a$10$N9qo8uLOickgx2ZMRZoHeqJfHyTt3W1NuBXS2uFw4cqzabhmvp6Eu"
+
+// dummyPasswordHash returns a fixed, lazily-computed password hash for
+// AuthenticateUser to verify against when no such user exists, so that
+// path takes as long as verifying a real (and wrong) password does.
+func (s *AuthService) dummyPasswordHash() string {
+	s.dummyHashMu.Lock()
+	defer s.dummyHashMu.Unlock()
+	if s.dummyHash == "" {
+		hash, err := s.passwordHasher().Hash(dummyVerifyPassword)
+		if err != nil {
+			hash = fallbackDummyHash
+		}
+		s.dummyHash = hash
+	}
+	return s.dummyHash
+}
+
+func (s *AuthService) issueAccessToken(user *User) (string, error) {
+	now := s.now()
+	claims := &Claims{
+		UserID:   user.ID,
+		Email:    user.Email,
+		Role:     user.Role,
+		TenantID: user.TenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        s.GenerateSecureToken(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.jwtSecret))
+}
+
+// ValidateToken parses and verifies a JWT, returning its claims. A token is
+// rejected - independent of its own exp claim - if its jti was revoked by
+// RevokeToken, or if it was issued to a user whose tokens were all revoked
+// by RevokeAllForUser at or after its IssuedAt.
+func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(s.jwtSecret), nil
+	}, jwt.WithTimeFunc(s.now))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, newAuthError(CodeTokenExpired, "token is expired", err)
+		}
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	if claims.Nonce != "" {
+		revoked, err := s.tokenStore().IsNonceRevoked(claims.Nonce)
+		if err != nil {
+			return nil, fmt.Errorf("check nonce revocation: %w", err)
+		}
+		if revoked {
+			return nil, newAuthError(CodeTokenRevoked, "impersonation token revoked", nil)
+		}
+	}
+	if claims.ID != "" {
+		revoked, err := s.tokenStore().IsJTIRevoked(claims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("check token revocation: %w", err)
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+	issuedAt := time.Time{}
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	userRevoked, err := s.tokenStore().IsUserRevokedAt(claims.UserID, issuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("check user revocation: %w", err)
+	}
+	if userRevoked {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// RevokeToken immediately denylists tokenID (a JWT jti, as returned by
+// ValidateToken's claims.ID) so any token still carrying it is rejected by
+// ValidateToken on its next use, regardless of its stated expiry.
+func (s *AuthService) RevokeToken(tokenID string) error {
+	if tokenID == "" {
+		return errors.New("token id is required")
+	}
+	if err := s.tokenStore().RevokeJTI(tokenID, s.now().Add(maxJTIRevocationTTL)); err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllForUser denylists every token already issued to userID - access
+// tokens via ValidateToken's user-wide check, and refresh tokens via the
+// same cutoff in Refresh (see refresh.go). A token issued in a later second
+// than this call remains valid; one issued within the same second is denied
+// too, since a token's iat has whole-second precision.
+func (s *AuthService) RevokeAllForUser(userID string) error {
+	if userID == "" {
+		return errors.New("user id is required")
+	}
+	if err := s.tokenStore().RevokeUser(userID); err != nil {
+		return fmt.Errorf("revoke all tokens for user: %w", err)
+	}
+	return nil
+}
+
+// RefreshToken validates tokenString and issues a brand new access token for
+// the same user, so a client can stay logged in without re-entering
+// credentials. See refresh.go for the rotation-on-use refresh-token flow
+// added on top of this.
+func (s *AuthService) RefreshToken(tokenString string) (string, error) {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return "", err
+	}
+	user, err := s.repo.GetUserByID(claims.UserID)
+	if err != nil {
+		return "", newAuthError(CodeUserNotFound, "user not found", err)
+	}
+	return s.issueAccessToken(user)
+}
+
+// HashPassword hashes password with the service's configured
+// PasswordHasher (bcrypt at the package default cost, unless the service
+// was built with NewAuthServiceWithOptions).
+func (s *AuthService) HashPassword(password string) (string, error) {
+	hash, err := s.passwordHasher().Hash(password)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+	return hash, nil
+}
+
+// VerifyPassword reports whether password matches hash, dispatching to the
+// right PasswordHasher by hash's PHC prefix regardless of which algorithm
+// this service is currently configured to hash new passwords with - so a
+// bcrypt hash verifies correctly even after the policy has moved to
+// Argon2id, until rehashIfNeeded upgrades it.
+func (s *AuthService) VerifyPassword(password, hash string) bool {
+	ok, err := verifyPasswordHash(password, hash)
+	if err != nil {
+		return false
+	}
+	return ok
+}
+
+// GenerateSecureToken returns a random, URL-safe-hex token suitable for
+// one-time use (enrollment tokens, password reset links, refresh tokens).
+func (s *AuthService) GenerateSecureToken() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("auth: failed to read random bytes: %v", err))
+	}
+	return hex.EncodeToString(buf)
+}
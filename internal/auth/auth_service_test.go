@@ -0,0 +1,731 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+
+	"insec/tests/helpers"
+)
+
+// MockUserRepository is a mock implementation of UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetUserByID(id string) (*User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByEmail(email string) (*User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateUser(user *User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateUser(user *User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DeleteUser(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// TestSuite for AuthService
+type AuthServiceTestSuite struct {
+	suite.Suite
+	service         *AuthService
+	mockRepo        *MockUserRepository
+	testUser        *User
+	validPassword   string
+	invalidPassword string
+}
+
+func (suite *AuthServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(MockUserRepository)
+	suite.service = NewAuthService(suite.mockRepo, "test-secret-key")
+
+	suite.validPassword = "correctpassword"
+	suite.invalidPassword = "wrongpassword"
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(suite.validPassword), bcrypt.DefaultCost)
+	suite.Require().NoError(err)
+
+	suite.testUser = &User{
+		ID:        "test-user-123",
+		Email:     "test@example.com",
+		Name:      "Test User",
+		Password:  string(hash),
+		Role:      "analyst",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func (suite *AuthServiceTestSuite) TearDownTest() {
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestAuthenticateUser_Success tests successful user authentication
+func (suite *AuthServiceTestSuite) TestAuthenticateUser_Success() {
+	// Arrange
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+
+	// Act
+	token, err := suite.service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), token)
+}
+
+// TestAuthenticateUser_InvalidPassword tests authentication with invalid password
+func (suite *AuthServiceTestSuite) TestAuthenticateUser_InvalidPassword() {
+	// Arrange
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+
+	// Act
+	token, err := suite.service.AuthenticateUser(suite.testUser.Email, suite.invalidPassword)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Empty(suite.T(), token)
+	assert.True(suite.T(), errors.Is(err, ErrInvalidCredentials))
+}
+
+// TestAuthenticateUser_UserNotFound tests authentication when user doesn't exist
+func (suite *AuthServiceTestSuite) TestAuthenticateUser_UserNotFound() {
+	// Arrange
+	suite.mockRepo.On("GetUserByEmail", "nonexistent@example.com").Return(nil, errors.New("user not found"))
+
+	// Act
+	token, err := suite.service.AuthenticateUser("nonexistent@example.com", suite.validPassword)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Empty(suite.T(), token)
+	assert.True(suite.T(), errors.Is(err, ErrUserNotFound))
+}
+
+// TestAuthenticateUser_TimingParityMissingUserVsWrongPassword tests that a
+// nonexistent email and a wrong password for a real email take
+// indistinguishable time, so a client can't fingerprint which emails are
+// registered purely by response latency (see dummyPasswordHash in
+// auth_service.go).
+func (suite *AuthServiceTestSuite) TestAuthenticateUser_TimingParityMissingUserVsWrongPassword() {
+	realHash, err := bcrypt.GenerateFromPassword([]byte(suite.validPassword), bcrypt.DefaultCost)
+	assert.NoError(suite.T(), err)
+	suite.testUser.Password = string(realHash)
+
+	const iterations = 100
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil).Times(iterations)
+	suite.mockRepo.On("GetUserByEmail", "nonexistent@example.com").Return(nil, errors.New("user not found")).Times(iterations)
+
+	start := time.Now()
+	for i := 0; i < iterations; i++ {
+		_, err := suite.service.AuthenticateUser(suite.testUser.Email, suite.invalidPassword)
+		assert.True(suite.T(), errors.Is(err, ErrInvalidCredentials))
+	}
+	wrongPasswordElapsed := time.Since(start)
+
+	start = time.Now()
+	for i := 0; i < iterations; i++ {
+		_, err := suite.service.AuthenticateUser("nonexistent@example.com", suite.invalidPassword)
+		assert.True(suite.T(), errors.Is(err, ErrUserNotFound))
+	}
+	missingUserElapsed := time.Since(start)
+
+	diff := wrongPasswordElapsed - missingUserElapsed
+	if diff < 0 {
+		diff = -diff
+	}
+	assert.Less(suite.T(), diff, iterations*10*time.Millisecond, "missing-user and wrong-password paths should differ by less than 10ms per call on average over the run")
+}
+
+// TestValidateToken_Success tests successful token validation
+func (suite *AuthServiceTestSuite) TestValidateToken_Success() {
+	// Arrange
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	token, _ := suite.service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+
+	// Act
+	claims, err := suite.service.ValidateToken(token)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), claims)
+	assert.Equal(suite.T(), suite.testUser.ID, claims.UserID)
+	assert.Equal(suite.T(), suite.testUser.Email, claims.Email)
+}
+
+// TestValidateToken_InvalidToken tests validation of invalid token
+func (suite *AuthServiceTestSuite) TestValidateToken_InvalidToken() {
+	// Act
+	claims, err := suite.service.ValidateToken("invalid.jwt.token")
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), claims)
+}
+
+// TestValidateToken_ExpiredToken tests validation of expired token. Uses a
+// FakeClock advanced deterministically past expiry instead of a real sleep,
+// which was inherently flaky under load.
+func (suite *AuthServiceTestSuite) TestValidateToken_ExpiredToken() {
+	// Arrange - Create service with very short expiration for testing
+	clock := helpers.NewFakeClock(time.Now())
+	shortLivedService := NewAuthServiceWithClock(suite.mockRepo, "test-secret", time.Millisecond*1, nil, nil, clock)
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	token, _ := shortLivedService.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+
+	// Act - advance past expiry deterministically
+	clock.Advance(time.Millisecond * 2)
+	claims, err := shortLivedService.ValidateToken(token)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), claims)
+	assert.True(suite.T(), errors.Is(err, ErrTokenExpired))
+}
+
+// TestValidateToken_ExpiryBoundary tests that a token is still valid the
+// instant before its exp, and rejected the instant exp is reached.
+func (suite *AuthServiceTestSuite) TestValidateToken_ExpiryBoundary() {
+	clock := helpers.NewFakeClock(time.Now())
+	service := NewAuthServiceWithClock(suite.mockRepo, "test-secret", time.Minute, nil, nil, clock)
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	token, err := service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+	assert.NoError(suite.T(), err)
+
+	// One second before exp, the token still validates.
+	clock.Advance(time.Minute - time.Second)
+	_, err = service.ValidateToken(token)
+	assert.NoError(suite.T(), err, "a token must still validate right up to its exp")
+
+	// At exp exactly, the token is rejected.
+	clock.Advance(time.Second)
+	_, err = service.ValidateToken(token)
+	assert.Error(suite.T(), err, "a token at exactly its exp must be rejected")
+}
+
+// TestValidateToken_NotBeforeFuture tests that a token carrying an nbf in
+// the future is rejected until the clock reaches it.
+func (suite *AuthServiceTestSuite) TestValidateToken_NotBeforeFuture() {
+	clock := helpers.NewFakeClock(time.Now())
+	service := NewAuthServiceWithClock(suite.mockRepo, "test-secret", time.Minute, nil, nil, clock)
+
+	now := clock.Now()
+	claims := &Claims{
+		UserID: suite.testUser.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now.Add(10 * time.Second)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	assert.NoError(suite.T(), err)
+
+	// Before nbf, the token must be rejected.
+	_, err = service.ValidateToken(token)
+	assert.Error(suite.T(), err, "a token must be rejected before its nbf")
+
+	// Once the clock reaches nbf, the same token validates.
+	clock.Advance(10 * time.Second)
+	_, err = service.ValidateToken(token)
+	assert.NoError(suite.T(), err, "a token must validate once the clock reaches its nbf")
+}
+
+// TestValidateToken_IATClockSkewTolerance tests that a token whose iat is
+// slightly ahead of the validating replica's own clock - the expected
+// situation when two hosts' clocks have drifted apart slightly - is still
+// accepted, since AuthService never rejects a token purely for an iat in
+// the (near) future.
+func (suite *AuthServiceTestSuite) TestValidateToken_IATClockSkewTolerance() {
+	issuerClock := helpers.NewFakeClock(time.Now().Add(2 * time.Second))
+	issuer := NewAuthServiceWithClock(suite.mockRepo, "test-secret", time.Minute, nil, nil, issuerClock)
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	token, err := issuer.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+	assert.NoError(suite.T(), err)
+
+	validatorClock := helpers.NewFakeClock(time.Now())
+	validator := NewAuthServiceWithClock(suite.mockRepo, "test-secret", time.Minute, nil, nil, validatorClock)
+
+	claims, err := validator.ValidateToken(token)
+	assert.NoError(suite.T(), err, "a token whose iat is slightly ahead of the validator's clock must still validate")
+	assert.Equal(suite.T(), suite.testUser.ID, claims.UserID)
+}
+
+// TestRefreshToken_Success tests successful token refresh
+func (suite *AuthServiceTestSuite) TestRefreshToken_Success() {
+	// Arrange
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	suite.mockRepo.On("GetUserByID", suite.testUser.ID).Return(suite.testUser, nil)
+	token, _ := suite.service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+
+	// Act
+	newToken, err := suite.service.RefreshToken(token)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), newToken)
+	assert.NotEqual(suite.T(), token, newToken) // Should be a different token
+}
+
+// TestRefreshToken_InvalidToken tests refresh with invalid token
+func (suite *AuthServiceTestSuite) TestRefreshToken_InvalidToken() {
+	// Act
+	newToken, err := suite.service.RefreshToken("invalid.jwt.token")
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Empty(suite.T(), newToken)
+}
+
+// TestHashPassword tests password hashing
+func (suite *AuthServiceTestSuite) TestHashPassword() {
+	// Act
+	hashedPassword, err := suite.service.HashPassword(suite.validPassword)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), hashedPassword)
+	assert.NotEqual(suite.T(), suite.validPassword, hashedPassword)
+	assert.True(suite.T(), len(hashedPassword) > len(suite.validPassword))
+}
+
+// TestVerifyPassword_Success tests successful password verification
+func (suite *AuthServiceTestSuite) TestVerifyPassword_Success() {
+	// Arrange
+	hashedPassword, _ := suite.service.HashPassword(suite.validPassword)
+
+	// Act
+	isValid := suite.service.VerifyPassword(suite.validPassword, hashedPassword)
+
+	// Assert
+	assert.True(suite.T(), isValid)
+}
+
+// TestVerifyPassword_InvalidPassword tests password verification with wrong password
+func (suite *AuthServiceTestSuite) TestVerifyPassword_InvalidPassword() {
+	// Arrange
+	hashedPassword, _ := suite.service.HashPassword(suite.validPassword)
+
+	// Act
+	isValid := suite.service.VerifyPassword(suite.invalidPassword, hashedPassword)
+
+	// Assert
+	assert.False(suite.T(), isValid)
+}
+
+// TestGenerateSecureToken tests secure token generation
+func (suite *AuthServiceTestSuite) TestGenerateSecureToken() {
+	// Act
+	token1 := suite.service.GenerateSecureToken()
+	token2 := suite.service.GenerateSecureToken()
+
+	// Assert
+	assert.NotEmpty(suite.T(), token1)
+	assert.NotEmpty(suite.T(), token2)
+	assert.NotEqual(suite.T(), token1, token2) // Should be unique
+	assert.True(suite.T(), len(token1) >= 32)  // Should be sufficiently long
+}
+
+// TestRefreshRotation_ReuseRevokesFamily tests that replaying a refresh
+// token that was already rotated away kills the whole token family,
+// including the token that the legitimate rotation issued.
+func (suite *AuthServiceTestSuite) TestRefreshRotation_ReuseRevokesFamily() {
+	// Arrange
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	suite.mockRepo.On("GetUserByID", suite.testUser.ID).Return(suite.testUser, nil)
+
+	pair, err := suite.service.Login(suite.testUser.Email, suite.validPassword)
+	assert.NoError(suite.T(), err)
+
+	// Act - legitimate rotation
+	rotated, err := suite.service.Refresh(pair.RefreshToken)
+	assert.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), pair.RefreshToken, rotated.RefreshToken)
+
+	// Act - replay of the now-rotated-away token
+	_, err = suite.service.Refresh(pair.RefreshToken)
+
+	// Assert
+	assert.Error(suite.T(), err, "reusing a rotated refresh token must fail")
+
+	// Assert - the whole family, including the token from the legitimate
+	// rotation above, is now dead too
+	_, err = suite.service.Refresh(rotated.RefreshToken)
+	assert.Error(suite.T(), err, "reuse detection must revoke the entire token family")
+}
+
+// TestPasswordHasher_Argon2id tests that Argon2idHasher produces a
+// PHC-formatted hash and verifies correctly against it.
+func (suite *AuthServiceTestSuite) TestPasswordHasher_Argon2id() {
+	hasher := NewArgon2idHasher(DefaultArgon2idParams())
+
+	hash, err := hasher.Hash(suite.validPassword)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=3,p=2$"))
+
+	ok, err := hasher.Verify(suite.validPassword, hash)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+
+	ok, err = hasher.Verify(suite.invalidPassword, hash)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), ok)
+}
+
+// TestPasswordHasher_Scrypt tests that ScryptHasher produces a
+// PHC-formatted hash and verifies correctly against it.
+func (suite *AuthServiceTestSuite) TestPasswordHasher_Scrypt() {
+	hasher := NewScryptHasher(DefaultScryptParams())
+
+	hash, err := hasher.Hash(suite.validPassword)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(hash, "$scrypt$ln=15,r=8,p=1$"))
+
+	ok, err := hasher.Verify(suite.validPassword, hash)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+
+	ok, err = hasher.Verify(suite.invalidPassword, hash)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), ok)
+}
+
+// TestPasswordHasher_Bcrypt tests that BcryptHasher still round-trips
+// through VerifyPassword's prefix dispatch.
+func (suite *AuthServiceTestSuite) TestPasswordHasher_Bcrypt() {
+	hasher := NewBcryptHasher(bcrypt.DefaultCost)
+
+	hash, err := hasher.Hash(suite.validPassword)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(hash, "$2a$"))
+
+	assert.True(suite.T(), suite.service.VerifyPassword(suite.validPassword, hash))
+	assert.False(suite.T(), suite.service.VerifyPassword(suite.invalidPassword, hash))
+}
+
+// TestArgon2idPHCRoundTrip tests that a hash produced by one Argon2idHasher
+// verifies under a second, independently-constructed one with the same
+// parameters - i.e. all the information Verify needs travels in the PHC
+// string itself.
+func (suite *AuthServiceTestSuite) TestArgon2idPHCRoundTrip() {
+	producer := NewArgon2idHasher(Argon2idParams{Memory: 19 * 1024, Time: 2, Threads: 1, SaltLen: 16, KeyLen: 32})
+	hash, err := producer.Hash(suite.validPassword)
+	assert.NoError(suite.T(), err)
+
+	verifier := NewArgon2idHasher(DefaultArgon2idParams())
+	ok, err := verifier.Verify(suite.validPassword, hash)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+
+	assert.True(suite.T(), verifier.NeedsRehash(hash), "a hash from weaker params must be flagged for rehash under a stronger policy")
+}
+
+// TestAuthenticateUser_UpgradesBcryptHashUnderArgon2idPolicy tests that
+// logging in with a bcrypt-hashed password under an Argon2id policy
+// transparently rehashes and persists the upgrade via UpdateUser.
+func (suite *AuthServiceTestSuite) TestAuthenticateUser_UpgradesBcryptHashUnderArgon2idPolicy() {
+	// Arrange - a user whose password is still bcrypt-hashed
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(suite.validPassword), bcrypt.DefaultCost)
+	assert.NoError(suite.T(), err)
+	suite.testUser.Password = string(bcryptHash)
+
+	service := NewAuthServiceWithOptions(suite.mockRepo, "test-secret-key", DefaultAccessTokenTTL, nil, NewArgon2idHasher(DefaultArgon2idParams()))
+
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	suite.mockRepo.On("UpdateUser", mock.MatchedBy(func(u *User) bool {
+		return strings.HasPrefix(u.Password, "$argon2id$")
+	})).Return(nil)
+
+	// Act
+	token, err := service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), token)
+	suite.mockRepo.AssertCalled(suite.T(), "UpdateUser", mock.Anything)
+}
+
+// TestRevokeToken_DeniesFurtherValidation tests that RevokeToken denylists
+// an access token's jti so ValidateToken rejects it afterward, even though
+// it hasn't expired.
+func (suite *AuthServiceTestSuite) TestRevokeToken_DeniesFurtherValidation() {
+	// Arrange
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	token, err := suite.service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+	assert.NoError(suite.T(), err)
+
+	claims, err := suite.service.ValidateToken(token)
+	assert.NoError(suite.T(), err)
+
+	// Act
+	err = suite.service.RevokeToken(claims.ID)
+	assert.NoError(suite.T(), err)
+
+	// Assert
+	_, err = suite.service.ValidateToken(token)
+	assert.Error(suite.T(), err, "a revoked token must fail validation before it expires")
+}
+
+// TestRevokeToken_EmptyTokenID tests that RevokeToken rejects an empty id
+// rather than silently denylisting nothing.
+func (suite *AuthServiceTestSuite) TestRevokeToken_EmptyTokenID() {
+	err := suite.service.RevokeToken("")
+	assert.Error(suite.T(), err)
+}
+
+// TestRevokeAllForUser_DeniesExistingTokens tests that RevokeAllForUser
+// invalidates a token already issued to that user, while a token issued
+// afterward remains valid. Uses a FakeClock advanced a full second past the
+// revocation instead of a real sleep, since the cutoff and a token's iat
+// both truncate to second precision and a sub-second sleep can't reliably
+// land on the other side of that truncation.
+func (suite *AuthServiceTestSuite) TestRevokeAllForUser_DeniesExistingTokens() {
+	// Arrange
+	clock := helpers.NewFakeClock(time.Now())
+	service := NewAuthServiceWithClock(suite.mockRepo, "test-secret-key", time.Hour, nil, nil, clock)
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	oldToken, err := service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+	assert.NoError(suite.T(), err)
+
+	// Act
+	err = service.RevokeAllForUser(suite.testUser.ID)
+	assert.NoError(suite.T(), err)
+
+	// Assert - the token issued before the revocation is now dead
+	_, err = service.ValidateToken(oldToken)
+	assert.Error(suite.T(), err, "a token issued before RevokeAllForUser must fail validation")
+
+	// Assert - a token issued after the revocation is unaffected
+	clock.Advance(time.Second)
+	newToken, err := service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+	assert.NoError(suite.T(), err)
+	_, err = service.ValidateToken(newToken)
+	assert.NoError(suite.T(), err, "a token issued after RevokeAllForUser must still validate")
+}
+
+// TestMemoryTokenStore_TrimDropsExpiredDenylistEntries tests that Trim
+// reclaims jti denylist entries once their own TTL has passed, and that a
+// still-live entry survives a Trim pass.
+func (suite *AuthServiceTestSuite) TestMemoryTokenStore_TrimDropsExpiredDenylistEntries() {
+	store := NewMemoryTokenStore()
+	now := time.Now()
+
+	assert.NoError(suite.T(), store.RevokeJTI("expired-jti", now.Add(-time.Second)))
+	assert.NoError(suite.T(), store.RevokeJTI("live-jti", now.Add(time.Hour)))
+
+	store.Trim(now)
+
+	expiredRevoked, err := store.IsJTIRevoked("expired-jti")
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), expiredRevoked, "Trim must drop entries past their own TTL")
+
+	liveRevoked, err := store.IsJTIRevoked("live-jti")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), liveRevoked, "Trim must not drop entries still inside their TTL")
+}
+
+// TestEnrollTOTP_ConfirmEnablesMFA tests that EnrollTOTP stores a secret the
+// user can immediately confirm with a code generated from it, and that
+// confirmation enables MFA and hands back 8 recovery codes.
+func (suite *AuthServiceTestSuite) TestEnrollTOTP_ConfirmEnablesMFA() {
+	// Arrange
+	suite.mockRepo.On("GetUserByID", suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockRepo.On("UpdateUser", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*suite.testUser = *args.Get(0).(*User)
+	})
+
+	// Act
+	secret, qrPNG, err := suite.service.EnrollTOTP(suite.testUser.ID)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), secret)
+	assert.NotEmpty(suite.T(), qrPNG)
+	assert.Equal(suite.T(), secret, suite.testUser.MFASecret)
+	assert.False(suite.T(), suite.testUser.MFAEnabled, "MFA must not be enabled until ConfirmTOTP succeeds")
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	assert.NoError(suite.T(), err)
+
+	codes, err := suite.service.ConfirmTOTP(suite.testUser.ID, code)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), codes, recoveryCodeCount)
+	assert.True(suite.T(), suite.testUser.MFAEnabled)
+	assert.Len(suite.T(), suite.testUser.MFARecoveryCodes, recoveryCodeCount)
+	for _, c := range codes {
+		assert.NotContains(suite.T(), suite.testUser.MFARecoveryCodes, c, "only the hash, never the cleartext code, is persisted")
+	}
+}
+
+// TestConfirmTOTP_InvalidCode tests that ConfirmTOTP rejects a code that
+// doesn't match the enrolled secret, and leaves MFA disabled.
+func (suite *AuthServiceTestSuite) TestConfirmTOTP_InvalidCode() {
+	suite.testUser.MFASecret = "JBSWY3DPEHPK3PXP"
+	suite.mockRepo.On("GetUserByID", suite.testUser.ID).Return(suite.testUser, nil)
+
+	codes, err := suite.service.ConfirmTOTP(suite.testUser.ID, "000000")
+
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), codes)
+	assert.False(suite.T(), suite.testUser.MFAEnabled)
+}
+
+// TestAuthenticateUser_MFAEnabledReturnsChallenge tests that logging in as
+// an MFA-enabled user yields a challenge token and ErrMFARequired instead of
+// an access token, and that AuthenticateMFA redeems that challenge for a
+// real access token given a valid TOTP code.
+func (suite *AuthServiceTestSuite) TestAuthenticateUser_MFAEnabledReturnsChallenge() {
+	// Arrange
+	suite.testUser.MFAEnabled = true
+	suite.testUser.MFASecret = "JBSWY3DPEHPK3PXP"
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	suite.mockRepo.On("GetUserByID", suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockRepo.On("UpdateUser", mock.Anything).Return(nil)
+
+	// Act
+	challenge, err := suite.service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, ErrMFARequired)
+	assert.NotEmpty(suite.T(), challenge)
+
+	code, err := totp.GenerateCode(suite.testUser.MFASecret, time.Now())
+	assert.NoError(suite.T(), err)
+
+	accessToken, err := suite.service.AuthenticateMFA(challenge, code)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), accessToken)
+}
+
+// TestAuthenticateMFA_ReplayProtection tests that a TOTP code already
+// redeemed once is rejected on a second attempt within the same 30s step,
+// even though it would otherwise still be mathematically valid.
+func (suite *AuthServiceTestSuite) TestAuthenticateMFA_ReplayProtection() {
+	// Arrange
+	suite.testUser.MFAEnabled = true
+	suite.testUser.MFASecret = "JBSWY3DPEHPK3PXP"
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil)
+	suite.mockRepo.On("GetUserByID", suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockRepo.On("UpdateUser", mock.Anything).Return(nil)
+
+	challenge, err := suite.service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+	assert.ErrorIs(suite.T(), err, ErrMFARequired)
+	code, err := totp.GenerateCode(suite.testUser.MFASecret, time.Now())
+	assert.NoError(suite.T(), err)
+
+	// Act
+	_, err = suite.service.AuthenticateMFA(challenge, code)
+	assert.NoError(suite.T(), err)
+
+	// Assert - the same code, reused, is rejected
+	_, err = suite.service.AuthenticateMFA(challenge, code)
+	assert.Error(suite.T(), err, "a TOTP code must not validate twice within the same time step")
+}
+
+// TestConsumeRecoveryCode tests that a recovery code redeems exactly once.
+func (suite *AuthServiceTestSuite) TestConsumeRecoveryCode() {
+	// Arrange
+	code := "abcdef0123"
+	suite.testUser.MFARecoveryCodes = []string{hashRecoveryCode(code)}
+	suite.mockRepo.On("GetUserByID", suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockRepo.On("UpdateUser", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*suite.testUser = *args.Get(0).(*User)
+	})
+
+	// Act
+	err := suite.service.ConsumeRecoveryCode(suite.testUser.ID, code)
+	assert.NoError(suite.T(), err)
+
+	// Assert - the same code cannot be consumed twice
+	err = suite.service.ConsumeRecoveryCode(suite.testUser.ID, code)
+	assert.Error(suite.T(), err, "a recovery code must be single-use")
+}
+
+// TestConcurrentAuthentication tests concurrent authentication requests
+func (suite *AuthServiceTestSuite) TestConcurrentAuthentication() {
+	// Arrange
+	suite.mockRepo.On("GetUserByEmail", suite.testUser.Email).Return(suite.testUser, nil).Maybe()
+
+	// Act - Run multiple authentication requests concurrently
+	done := make(chan bool, 10)
+	for i := 0; i < 10; i++ {
+		go func() {
+			_, err := suite.service.AuthenticateUser(suite.testUser.Email, suite.validPassword)
+			assert.NoError(suite.T(), err)
+			done <- true
+		}()
+	}
+
+	// Assert - Wait for all goroutines to complete
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+}
+
+// Run the test suite
+func TestAuthServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthServiceTestSuite))
+}
+
+// Benchmark tests
+func BenchmarkAuthenticateUser(b *testing.B) {
+	mockRepo := new(MockUserRepository)
+	service := NewAuthService(mockRepo, "benchmark-secret")
+
+	user := &User{
+		ID:       "bench-user",
+		Email:    "bench@example.com",
+		Password: "$2a$10$hashedpassword",
+	}
+
+	mockRepo.On("GetUserByEmail", user.Email).Return(user, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = service.AuthenticateUser(user.Email, "password")
+	}
+}
+
+func BenchmarkValidateToken(b *testing.B) {
+	mockRepo := new(MockUserRepository)
+	service := NewAuthService(mockRepo, "benchmark-secret")
+
+	user := &User{
+		ID:    "bench-user",
+		Email: "bench@example.com",
+	}
+
+	mockRepo.On("GetUserByEmail", user.Email).Return(user, nil)
+	token, _ := service.AuthenticateUser(user.Email, "password")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = service.ValidateToken(token)
+	}
+}
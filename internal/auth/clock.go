@@ -0,0 +1,27 @@
+package auth
+
+import "time"
+
+// Clock abstracts time.Now() so AuthService's token issuance, expiry
+// checks, and TTL math can be driven deterministically in tests instead of
+// relying on real sleeps past a short-lived token's expiry - the kind of
+// race that produces intermittent CI failures under load.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the production Clock every AuthService uses unless built
+// with NewAuthServiceWithClock.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// now returns the service's current time, falling back to the real wall
+// clock so a zero-value AuthService (and every constructor that predates
+// Clock) behaves exactly as before.
+func (s *AuthService) now() time.Time {
+	if s.clock == nil {
+		return realClock{}.Now()
+	}
+	return s.clock.Now()
+}
@@ -0,0 +1,81 @@
+package auth
+
+import "net/http"
+
+// AuthCode identifies a specific, expected auth failure independent of the
+// message text attached to it, so callers branch with errors.Is instead of
+// matching substrings in err.Error() - which is fragile and leaks internal
+// wording straight through to API clients.
+type AuthCode string
+
+const (
+	CodeInvalidCredentials AuthCode = "invalid_credentials"
+	CodeUserNotFound       AuthCode = "user_not_found"
+	CodeTokenExpired       AuthCode = "token_expired"
+	CodeTokenRevoked       AuthCode = "token_revoked"
+	CodeMFARequired        AuthCode = "mfa_required"
+	CodeAccountLocked      AuthCode = "account_locked"
+)
+
+// AuthError is the typed error every exported AuthService method returns
+// for an expected auth failure (as opposed to an unexpected one, like a
+// repository outage), so an HTTP handler can map it to a status code via
+// HTTPStatus instead of parsing its message.
+type AuthError struct {
+	Code AuthCode
+	msg  string
+	// cause is the underlying error this AuthError was derived from, if
+	// any (e.g. the repository error behind CodeUserNotFound); exposed via
+	// Unwrap so errors.Is/As still walk the full chain.
+	cause error
+}
+
+func (e *AuthError) Error() string { return e.msg }
+
+func (e *AuthError) Unwrap() error { return e.cause }
+
+// Is lets errors.Is(err, ErrUserNotFound) succeed for any AuthError sharing
+// ErrUserNotFound's Code, not just the exact sentinel value - so a wrapped
+// AuthError built from a repository error still compares equal by code.
+func (e *AuthError) Is(target error) bool {
+	t, ok := target.(*AuthError)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code
+}
+
+// HTTPStatus maps Code to the HTTP status a handler should respond with.
+func (e *AuthError) HTTPStatus() int {
+	switch e.Code {
+	case CodeInvalidCredentials, CodeUserNotFound, CodeTokenExpired, CodeTokenRevoked:
+		return http.StatusUnauthorized
+	case CodeMFARequired, CodeAccountLocked:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func newAuthError(code AuthCode, msg string, cause error) *AuthError {
+	return &AuthError{Code: code, msg: msg, cause: cause}
+}
+
+// Sentinel AuthErrors every caller can match on with errors.Is. Returning
+// one of these directly (rather than a wrapped copy) is fine too - Is
+// compares by Code either way.
+var (
+	ErrInvalidCredentials = newAuthError(CodeInvalidCredentials, "invalid credentials", nil)
+	ErrUserNotFound       = newAuthError(CodeUserNotFound, "user not found", nil)
+	ErrTokenExpired       = newAuthError(CodeTokenExpired, "token is expired", nil)
+	ErrTokenRevoked       = newAuthError(CodeTokenRevoked, "token has been revoked", nil)
+	// ErrMFARequired is returned by AuthenticateUser, alongside a
+	// short-lived challenge token in place of an access token, when the
+	// user has TOTP MFA enabled (see totp.go).
+	ErrMFARequired = newAuthError(CodeMFARequired, "mfa required", nil)
+	// ErrAccountLocked is reserved for an account-lockout policy this
+	// package doesn't implement yet; it exists now so that feature can
+	// slot into the same taxonomy instead of inventing its own error style
+	// later.
+	ErrAccountLocked = newAuthError(CodeAccountLocked, "account locked", nil)
+)
@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MFAAssertionTTL bounds how long an X-MFA-Token assertion is accepted
+// after issuance.
+const MFAAssertionTTL = 60 * time.Second
+
+// MFAVerifiedWindow bounds how long a JWT's MFAVerifiedAt is accepted as a
+// substitute for a fresh assertion header.
+const MFAVerifiedWindow = 5 * time.Minute
+
+// MFAChallenge is returned by POST /api/v1/auth/mfa/challenge; the client
+// completes it out of band (TOTP app, WebAuthn) and presents the resulting
+// code to POST /api/v1/auth/mfa/verify.
+type MFAChallenge struct {
+	ChallengeID string
+	UserID      string
+	ExpiresAt   time.Time
+}
+
+// IssueMFAChallenge starts a step-up MFA challenge for userID, persisting it
+// so RedeemMFAChallenge can later confirm the code MFAVerify is redeeming is
+// actually answering this specific challenge, rather than floating free of
+// any challenge at all.
+func (s *AuthService) IssueMFAChallenge(userID string) MFAChallenge {
+	challenge := MFAChallenge{
+		ChallengeID: s.GenerateSecureToken(),
+		UserID:      userID,
+		ExpiresAt:   s.now().Add(5 * time.Minute),
+	}
+	s.challengeMu.Lock()
+	if s.challenges == nil {
+		s.challenges = make(map[string]MFAChallenge)
+	}
+	s.challenges[challenge.ChallengeID] = challenge
+	s.challengeMu.Unlock()
+	return challenge
+}
+
+// RedeemMFAChallenge atomically checks and consumes challengeID for userID:
+// it must exist, not be expired, and belong to userID. Like an agent
+// enrollment token, a challenge is single-use - the lookup and delete happen
+// under the same lock so two concurrent MFAVerify calls can't both succeed
+// against it.
+func (s *AuthService) RedeemMFAChallenge(challengeID, userID string) error {
+	s.challengeMu.Lock()
+	defer s.challengeMu.Unlock()
+
+	challenge, ok := s.challenges[challengeID]
+	if !ok {
+		return errors.New("mfa challenge not found or already used")
+	}
+	delete(s.challenges, challengeID)
+
+	if challenge.UserID != userID {
+		return errors.New("mfa challenge does not belong to this user")
+	}
+	if s.now().After(challenge.ExpiresAt) {
+		return errors.New("mfa challenge expired")
+	}
+	return nil
+}
+
+// IssueMFAAssertion signs a short-lived assertion binding userID to the
+// specific request (method+path) it authorizes, so an assertion obtained
+// for one sensitive action can't be replayed against a different one.
+func (s *AuthService) IssueMFAAssertion(userID, method, path string) string {
+	exp := s.now().Add(MFAAssertionTTL).Unix()
+	payload := fmt.Sprintf("%s|%s|%s|%d", userID, method, path, exp)
+	mac := hmac.New(sha256.New, []byte(s.jwtSecret))
+	mac.Write([]byte(payload))
+	sig := hex.EncodeToString(mac.Sum(nil))
+	return fmt.Sprintf("%s.%s", payload, sig)
+}
+
+// VerifyMFAAssertion checks an X-MFA-Token header value against the
+// user_id/method/path it should be bound to, and that it hasn't expired.
+func (s *AuthService) VerifyMFAAssertion(assertion, userID, method, path string) error {
+	parts := splitLast(assertion, '.')
+	if len(parts) != 2 {
+		return errors.New("malformed mfa assertion")
+	}
+	payload, sig := parts[0], parts[1]
+
+	mac := hmac.New(sha256.New, []byte(s.jwtSecret))
+	mac.Write([]byte(payload))
+	want := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(sig), []byte(want)) {
+		return errors.New("mfa assertion signature invalid")
+	}
+
+	fields := splitPipe(payload)
+	if len(fields) != 4 {
+		return errors.New("malformed mfa assertion payload")
+	}
+	gotUser, gotMethod, gotPath := fields[0], fields[1], fields[2]
+	var exp int64
+	if _, err := fmt.Sscanf(fields[3], "%d", &exp); err != nil {
+		return errors.New("malformed mfa assertion expiry")
+	}
+
+	if gotUser != userID || gotMethod != method || gotPath != path {
+		return errors.New("mfa assertion does not match this request")
+	}
+	if s.now().Unix() > exp {
+		return errors.New("mfa assertion expired")
+	}
+	return nil
+}
+
+// VerifyStepUpCode checks code against userID's enrolled TOTP secret,
+// falling back to consuming it as a one-time recovery code, the same
+// validateTOTP-then-ConsumeRecoveryCode order AuthenticateMFA uses for
+// login. MFAVerify calls this before IssueMFAAssertion so a signed
+// assertion is never handed out for a code that was never actually
+// checked.
+func (s *AuthService) VerifyStepUpCode(userID, code string) error {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	if !user.MFAEnabled {
+		return errors.New("mfa is not enabled for this user")
+	}
+	if s.validateTOTP(user, code) {
+		return nil
+	}
+	if err := s.ConsumeRecoveryCode(user.ID, code); err == nil {
+		return nil
+	}
+	return errors.New("invalid mfa code")
+}
+
+// HasFreshMFA reports whether claims.MFAVerifiedAt is recent enough to
+// satisfy a gated route without a new assertion header.
+func (c *Claims) HasFreshMFA() bool {
+	return c.MFAVerifiedAt != nil && time.Since(*c.MFAVerifiedAt) <= MFAVerifiedWindow
+}
+
+func splitLast(s string, sep byte) []string {
+	idx := -1
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == sep {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return []string{s}
+	}
+	return []string{s[:idx], s[idx+1:]}
+}
+
+func splitPipe(s string) []string {
+	var fields []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '|' {
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+	}
+	fields = append(fields, s[start:])
+	return fields
+}
@@ -0,0 +1,303 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PasswordHasher hashes and verifies passwords for one particular
+// algorithm, encoding the algorithm and its parameters into the stored
+// hash using the PHC string format (e.g.
+// "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>"), so VerifyPassword can
+// dispatch to the right implementation by prefix instead of needing a
+// side-channel "algorithm" column.
+type PasswordHasher interface {
+	// Hash returns a new PHC-formatted hash of password under this
+	// hasher's current parameters.
+	Hash(password string) (string, error)
+	// Verify reports whether password matches encoded, which must have
+	// been produced by this algorithm's Hash (any compatible parameters).
+	Verify(password, encoded string) (bool, error)
+	// Owns reports whether encoded carries this algorithm's PHC prefix.
+	Owns(encoded string) bool
+	// NeedsRehash reports whether encoded (already confirmed Owns) was
+	// hashed with weaker parameters than this hasher is configured for.
+	NeedsRehash(encoded string) bool
+}
+
+func b64Encode(b []byte) string { return base64.RawStdEncoding.EncodeToString(b) }
+
+func b64Decode(s string) ([]byte, error) { return base64.RawStdEncoding.DecodeString(s) }
+
+// --- bcrypt ---
+
+// BcryptHasher is the original algorithm AuthService shipped with; every
+// AuthService defaults to one at bcrypt.DefaultCost unless configured
+// otherwise via NewAuthServiceWithOptions.
+type BcryptHasher struct {
+	Cost int
+}
+
+// NewBcryptHasher builds a BcryptHasher at the given cost factor.
+func NewBcryptHasher(cost int) *BcryptHasher {
+	return &BcryptHasher{Cost: cost}
+}
+
+// Hash implements PasswordHasher.
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt hash: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Verify implements PasswordHasher.
+func (h *BcryptHasher) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return false, fmt.Errorf("bcrypt verify: %w", err)
+}
+
+// Owns implements PasswordHasher.
+func (h *BcryptHasher) Owns(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+// NeedsRehash implements PasswordHasher.
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+	return cost < h.Cost
+}
+
+// --- scrypt ---
+
+const scryptPrefix = "$scrypt$"
+
+// ScryptParams configures scrypt's cost and output sizes. N must be a
+// power of two, since it's encoded in PHC form as ln = log2(N).
+type ScryptParams struct {
+	N, R, P, SaltLen, KeyLen int
+}
+
+// DefaultScryptParams returns OWASP's current baseline scrypt parameters.
+func DefaultScryptParams() ScryptParams {
+	return ScryptParams{N: 1 << 15, R: 8, P: 1, SaltLen: 16, KeyLen: 32}
+}
+
+// ScryptHasher implements PasswordHasher using golang.org/x/crypto/scrypt.
+type ScryptHasher struct {
+	Params ScryptParams
+}
+
+// NewScryptHasher builds a ScryptHasher with the given parameters.
+func NewScryptHasher(params ScryptParams) *ScryptHasher {
+	return &ScryptHasher{Params: params}
+}
+
+// Hash implements PasswordHasher.
+func (h *ScryptHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.Params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("scrypt salt: %w", err)
+	}
+	dk, err := scrypt.Key([]byte(password), salt, h.Params.N, h.Params.R, h.Params.P, h.Params.KeyLen)
+	if err != nil {
+		return "", fmt.Errorf("scrypt hash: %w", err)
+	}
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s",
+		log2(h.Params.N), h.Params.R, h.Params.P, b64Encode(salt), b64Encode(dk)), nil
+}
+
+type scryptFields struct {
+	params     ScryptParams
+	salt, hash []byte
+}
+
+func parseScryptPHC(encoded string) (*scryptFields, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[1] != "scrypt" {
+		return nil, errors.New("auth: malformed scrypt hash")
+	}
+	var ln, r, p int
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &ln, &r, &p); err != nil {
+		return nil, fmt.Errorf("auth: malformed scrypt parameters: %w", err)
+	}
+	salt, err := b64Decode(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("auth: malformed scrypt salt: %w", err)
+	}
+	hash, err := b64Decode(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("auth: malformed scrypt hash: %w", err)
+	}
+	return &scryptFields{params: ScryptParams{N: 1 << ln, R: r, P: p}, salt: salt, hash: hash}, nil
+}
+
+// Verify implements PasswordHasher.
+func (h *ScryptHasher) Verify(password, encoded string) (bool, error) {
+	fields, err := parseScryptPHC(encoded)
+	if err != nil {
+		return false, err
+	}
+	dk, err := scrypt.Key([]byte(password), fields.salt, fields.params.N, fields.params.R, fields.params.P, len(fields.hash))
+	if err != nil {
+		return false, fmt.Errorf("scrypt verify: %w", err)
+	}
+	return subtle.ConstantTimeCompare(dk, fields.hash) == 1, nil
+}
+
+// Owns implements PasswordHasher.
+func (h *ScryptHasher) Owns(encoded string) bool {
+	return strings.HasPrefix(encoded, scryptPrefix)
+}
+
+// NeedsRehash implements PasswordHasher.
+func (h *ScryptHasher) NeedsRehash(encoded string) bool {
+	fields, err := parseScryptPHC(encoded)
+	if err != nil {
+		return true
+	}
+	return fields.params.N < h.Params.N || fields.params.R < h.Params.R || fields.params.P < h.Params.P
+}
+
+func log2(n int) int {
+	bits := 0
+	for n > 1 {
+		n >>= 1
+		bits++
+	}
+	return bits
+}
+
+// --- argon2id ---
+
+const argon2idPrefix = "$argon2id$"
+
+// Argon2idParams configures argon2.IDKey's cost and output sizes.
+type Argon2idParams struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+	SaltLen uint32
+	KeyLen  uint32
+}
+
+// DefaultArgon2idParams returns OWASP's current baseline Argon2id
+// parameters (64 MiB, 3 passes, 2 lanes).
+func DefaultArgon2idParams() Argon2idParams {
+	return Argon2idParams{Memory: 64 * 1024, Time: 3, Threads: 2, SaltLen: 16, KeyLen: 32}
+}
+
+// Argon2idHasher implements PasswordHasher using golang.org/x/crypto/argon2.
+type Argon2idHasher struct {
+	Params Argon2idParams
+}
+
+// NewArgon2idHasher builds an Argon2idHasher with the given parameters.
+func NewArgon2idHasher(params Argon2idParams) *Argon2idHasher {
+	return &Argon2idHasher{Params: params}
+}
+
+// Hash implements PasswordHasher.
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.Params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("argon2id salt: %w", err)
+	}
+	hash := argon2.IDKey([]byte(password), salt, h.Params.Time, h.Params.Memory, h.Params.Threads, h.Params.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Params.Memory, h.Params.Time, h.Params.Threads, b64Encode(salt), b64Encode(hash)), nil
+}
+
+type argon2idFields struct {
+	params     Argon2idParams
+	salt, hash []byte
+}
+
+func parseArgon2idPHC(encoded string) (*argon2idFields, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, errors.New("auth: malformed argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, fmt.Errorf("auth: malformed argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return nil, fmt.Errorf("auth: unsupported argon2 version %d", version)
+	}
+	var memory, timeCost uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &timeCost, &threads); err != nil {
+		return nil, fmt.Errorf("auth: malformed argon2id parameters: %w", err)
+	}
+	salt, err := b64Decode(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("auth: malformed argon2id salt: %w", err)
+	}
+	hash, err := b64Decode(parts[5])
+	if err != nil {
+		return nil, fmt.Errorf("auth: malformed argon2id hash: %w", err)
+	}
+	return &argon2idFields{params: Argon2idParams{Memory: memory, Time: timeCost, Threads: threads}, salt: salt, hash: hash}, nil
+}
+
+// Verify implements PasswordHasher.
+func (h *Argon2idHasher) Verify(password, encoded string) (bool, error) {
+	fields, err := parseArgon2idPHC(encoded)
+	if err != nil {
+		return false, err
+	}
+	computed := argon2.IDKey([]byte(password), fields.salt, fields.params.Time, fields.params.Memory, fields.params.Threads, uint32(len(fields.hash)))
+	return subtle.ConstantTimeCompare(computed, fields.hash) == 1, nil
+}
+
+// Owns implements PasswordHasher.
+func (h *Argon2idHasher) Owns(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+// NeedsRehash implements PasswordHasher.
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	fields, err := parseArgon2idPHC(encoded)
+	if err != nil {
+		return true
+	}
+	return fields.params.Memory < h.Params.Memory || fields.params.Time < h.Params.Time || fields.params.Threads < h.Params.Threads
+}
+
+// passwordVerifiers dispatches VerifyPassword to the right algorithm by PHC
+// prefix. Zero-valued instances are fine here since Verify/Owns parse every
+// parameter they need out of the encoded hash itself - only Hash (never
+// called on these) would need real Params.
+var passwordVerifiers = []PasswordHasher{
+	&Argon2idHasher{},
+	&ScryptHasher{},
+	&BcryptHasher{},
+}
+
+func verifyPasswordHash(password, encoded string) (bool, error) {
+	for _, h := range passwordVerifiers {
+		if h.Owns(encoded) {
+			return h.Verify(password, encoded)
+		}
+	}
+	return false, fmt.Errorf("auth: unrecognized password hash format")
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultRefreshTokenTTL is how long a refresh token stays valid before the
+// user must log in again from scratch.
+const DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+// RefreshClaims is the JWT payload for a refresh token. FamilyID is shared
+// across every token descended from the same login, so reuse of a revoked
+// member of the family can revoke the whole family at once.
+//
+// This embeds jwt.RegisteredClaims directly rather than pairing it with a
+// second, same-shaped carrier struct: encoding/json's ambiguous-field rule
+// silently drops colliding fields declared at the same struct depth, which
+// previously meant jti/sub/iss/aud/iat/exp never actually reached the
+// signed token.
+type RefreshClaims struct {
+	FamilyID string `json:"family_id"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair is the access+refresh pair returned from login and from a
+// successful refresh.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// Login authenticates email/password and returns a fresh access+refresh
+// pair, starting a new token family.
+func (s *AuthService) Login(email, password string) (TokenPair, error) {
+	user, err := s.repo.GetUserByEmail(email)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("user not found: %w", err)
+	}
+	if !s.VerifyPassword(password, user.Password) {
+		return TokenPair{}, errors.New("invalid credentials")
+	}
+
+	familyID := s.GenerateSecureToken()
+	return s.issuePair(user, familyID)
+}
+
+func (s *AuthService) issuePair(user *User, familyID string) (TokenPair, error) {
+	access, err := s.issueAccessToken(user)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := s.issueRefreshToken(user, familyID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{AccessToken: access, RefreshToken: refresh}, nil
+}
+
+func (s *AuthService) issueRefreshToken(user *User, familyID string) (string, error) {
+	now := s.now()
+	jti := s.GenerateSecureToken()
+	claims := &RefreshClaims{
+		FamilyID: familyID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   user.ID,
+			Issuer:    "insec",
+			Audience:  jwt.ClaimStrings{"insec-api"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(DefaultRefreshTokenTTL)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.jwtSecret))
+}
+
+func (s *AuthService) parseRefreshToken(tokenString string) (*RefreshClaims, error) {
+	claims := &RefreshClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(s.jwtSecret), nil
+	}, jwt.WithTimeFunc(s.now))
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh token: %w", err)
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid refresh token")
+	}
+	return claims, nil
+}
+
+// Refresh validates a refresh token, rotates it (the presented jti is
+// revoked and a new one is issued in the same family), and returns a fresh
+// access+refresh pair. If the presented token had already been revoked
+// (i.e. it's being replayed after a prior rotation), the entire family is
+// revoked and the caller must force the user to log in again — the standard
+// defense against a stolen refresh token being used after the legitimate
+// client already rotated past it.
+func (s *AuthService) Refresh(refreshToken string) (TokenPair, error) {
+	claims, err := s.parseRefreshToken(refreshToken)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	store := s.tokenStore()
+	jtiRevoked, err := store.IsJTIRevoked(claims.ID)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("check refresh token revocation: %w", err)
+	}
+	familyRevoked, err := store.IsFamilyRevoked(claims.FamilyID)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("check refresh family revocation: %w", err)
+	}
+	if jtiRevoked || familyRevoked {
+		if err := store.RevokeFamily(claims.FamilyID); err != nil {
+			return TokenPair{}, fmt.Errorf("revoke refresh family: %w", err)
+		}
+		return TokenPair{}, errors.New("refresh token reuse detected; session family revoked, please log in again")
+	}
+	userRevoked, err := store.IsUserRevokedAt(claims.Subject, claims.IssuedAt.Time)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("check user revocation: %w", err)
+	}
+	if userRevoked {
+		return TokenPair{}, errors.New("refresh token revoked; please log in again")
+	}
+
+	user, err := s.repo.GetUserByID(claims.Subject)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := store.RevokeJTI(claims.ID, claims.ExpiresAt.Time); err != nil {
+		return TokenPair{}, fmt.Errorf("rotate refresh token: %w", err)
+	}
+	return s.issuePair(user, claims.FamilyID)
+}
+
+// Logout revokes the entire family the given refresh token belongs to.
+func (s *AuthService) Logout(refreshToken string) error {
+	claims, err := s.parseRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+	if err := s.tokenStore().RevokeFamily(claims.FamilyID); err != nil {
+		return fmt.Errorf("logout: %w", err)
+	}
+	return nil
+}
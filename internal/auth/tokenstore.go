@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// maxJTIRevocationTTL upper-bounds how long RevokeToken denylists a jti
+// whose own expiry the caller doesn't supply: long enough to outlive any
+// token type AuthService issues (access or refresh), so the denylist entry
+// is never forgotten before the token it denies would have expired on its
+// own anyway.
+const maxJTIRevocationTTL = DefaultRefreshTokenTTL
+
+// TokenStore is the server-side backend for revocation and refresh-token
+// rotation: every jti AuthService denylists - directly via RevokeToken, or
+// as a side effect of refresh rotation and reuse detection - is recorded
+// here, alongside family-wide and user-wide revocation. MemoryTokenStore is
+// the single-process default; RedisTokenStore (tokenstore_redis.go) shares
+// this state across a multi-node deployment.
+type TokenStore interface {
+	// RevokeJTI denylists jti until expiresAt. ValidateToken and Refresh
+	// treat a denylisted jti as dead regardless of what its own exp claim
+	// says.
+	RevokeJTI(jti string, expiresAt time.Time) error
+	IsJTIRevoked(jti string) (bool, error)
+
+	// RevokeFamily kills every refresh token descended from the same
+	// login; used when a rotated-away refresh token is replayed.
+	RevokeFamily(familyID string) error
+	IsFamilyRevoked(familyID string) (bool, error)
+
+	// RevokeNonce and IsNonceRevoked back impersonate.go's per-token
+	// revocation.
+	RevokeNonce(nonce string) error
+	IsNonceRevoked(nonce string) (bool, error)
+
+	// RevokeUser denylists every token already issued to userID as of now;
+	// IsUserRevokedAt reports whether issuedAt predates that cutoff.
+	RevokeUser(userID string) error
+	IsUserRevokedAt(userID string, issuedAt time.Time) (bool, error)
+}
+
+// MemoryTokenStore is a single-process TokenStore, used as the dev fallback
+// when no Redis address is configured. Every AuthService defaults to one
+// lazily unless built with NewAuthServiceWithStore.
+type MemoryTokenStore struct {
+	mu         sync.Mutex
+	jti        map[string]time.Time // jti -> denylisted until
+	family     map[string]struct{}
+	nonce      map[string]struct{}
+	userCutoff map[string]time.Time
+}
+
+// NewMemoryTokenStore returns a ready-to-use MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		jti:        make(map[string]time.Time),
+		family:     make(map[string]struct{}),
+		nonce:      make(map[string]struct{}),
+		userCutoff: make(map[string]time.Time),
+	}
+}
+
+// RevokeJTI implements TokenStore.
+func (m *MemoryTokenStore) RevokeJTI(jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jti[jti] = expiresAt
+	return nil
+}
+
+// IsJTIRevoked implements TokenStore. A jti past its own denylist expiry is
+// forgotten and reports false, same as Trim would eventually do for it.
+func (m *MemoryTokenStore) IsJTIRevoked(jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	expiresAt, ok := m.jti[jti]
+	if !ok {
+		return false, nil
+	}
+	if !time.Now().Before(expiresAt) {
+		delete(m.jti, jti)
+		return false, nil
+	}
+	return true, nil
+}
+
+// RevokeFamily implements TokenStore.
+func (m *MemoryTokenStore) RevokeFamily(familyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.family[familyID] = struct{}{}
+	return nil
+}
+
+// IsFamilyRevoked implements TokenStore.
+func (m *MemoryTokenStore) IsFamilyRevoked(familyID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.family[familyID]
+	return ok, nil
+}
+
+// RevokeNonce implements TokenStore.
+func (m *MemoryTokenStore) RevokeNonce(nonce string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nonce[nonce] = struct{}{}
+	return nil
+}
+
+// IsNonceRevoked implements TokenStore.
+func (m *MemoryTokenStore) IsNonceRevoked(nonce string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.nonce[nonce]
+	return ok, nil
+}
+
+// RevokeUser implements TokenStore.
+func (m *MemoryTokenStore) RevokeUser(userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.userCutoff[userID] = time.Now()
+	return nil
+}
+
+// IsUserRevokedAt implements TokenStore.
+func (m *MemoryTokenStore) IsUserRevokedAt(userID string, issuedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cutoff, ok := m.userCutoff[userID]
+	if !ok {
+		return false, nil
+	}
+	return !issuedAt.After(cutoff), nil
+}
+
+// Trim drops every denylisted jti whose own TTL has already passed, so a
+// long-running process doesn't grow the denylist without bound for jtis
+// that are never looked up again after they expire. IsJTIRevoked already
+// self-trims lazily on lookup; Trim is for a caller (e.g. a periodic
+// goroutine) that wants to reclaim that memory proactively.
+func (m *MemoryTokenStore) Trim(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for jti, expiresAt := range m.jti {
+		if !now.Before(expiresAt) {
+			delete(m.jti, jti)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis key prefixes for each kind of revocation RedisTokenStore tracks.
+const (
+	redisJTIPrefix    = "auth:revoked:jti:"
+	redisFamilyPrefix = "auth:revoked:family:"
+	redisNoncePrefix  = "auth:revoked:nonce:"
+	redisUserPrefix   = "auth:revoked:user:"
+)
+
+// RedisTokenStore is a TokenStore backed by a shared Redis instance, so
+// revocation and refresh-family state is consistent across every server
+// replica in a multi-node deployment rather than per-process. Unlike
+// internal/ratelimit's RedisLimiter, nothing here needs an atomic
+// read-modify-write, so plain SET/EXISTS/GET is enough - no Lua script.
+type RedisTokenStore struct {
+	client *redis.Client
+}
+
+// NewRedisTokenStore wraps an existing Redis client.
+func NewRedisTokenStore(client *redis.Client) *RedisTokenStore {
+	return &RedisTokenStore{client: client}
+}
+
+// RevokeJTI implements TokenStore. A jti whose expiresAt has already
+// passed is not written at all, since IsJTIRevoked would treat a key with a
+// non-positive TTL as already expired anyway.
+func (r *RedisTokenStore) RevokeJTI(jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := r.client.Set(context.Background(), redisJTIPrefix+jti, "1", ttl).Err(); err != nil {
+		return fmt.Errorf("auth: revoke jti: %w", err)
+	}
+	return nil
+}
+
+// IsJTIRevoked implements TokenStore.
+func (r *RedisTokenStore) IsJTIRevoked(jti string) (bool, error) {
+	return r.exists(redisJTIPrefix + jti)
+}
+
+// RevokeFamily implements TokenStore.
+func (r *RedisTokenStore) RevokeFamily(familyID string) error {
+	if err := r.client.Set(context.Background(), redisFamilyPrefix+familyID, "1", DefaultRefreshTokenTTL).Err(); err != nil {
+		return fmt.Errorf("auth: revoke family: %w", err)
+	}
+	return nil
+}
+
+// IsFamilyRevoked implements TokenStore.
+func (r *RedisTokenStore) IsFamilyRevoked(familyID string) (bool, error) {
+	return r.exists(redisFamilyPrefix + familyID)
+}
+
+// RevokeNonce implements TokenStore.
+func (r *RedisTokenStore) RevokeNonce(nonce string) error {
+	if err := r.client.Set(context.Background(), redisNoncePrefix+nonce, "1", MaxImpersonationTTL).Err(); err != nil {
+		return fmt.Errorf("auth: revoke nonce: %w", err)
+	}
+	return nil
+}
+
+// IsNonceRevoked implements TokenStore.
+func (r *RedisTokenStore) IsNonceRevoked(nonce string) (bool, error) {
+	return r.exists(redisNoncePrefix + nonce)
+}
+
+// RevokeUser implements TokenStore, storing the cutoff as a Unix timestamp.
+func (r *RedisTokenStore) RevokeUser(userID string) error {
+	now := time.Now().Unix()
+	if err := r.client.Set(context.Background(), redisUserPrefix+userID, now, DefaultRefreshTokenTTL).Err(); err != nil {
+		return fmt.Errorf("auth: revoke user: %w", err)
+	}
+	return nil
+}
+
+// IsUserRevokedAt implements TokenStore.
+func (r *RedisTokenStore) IsUserRevokedAt(userID string, issuedAt time.Time) (bool, error) {
+	cutoffUnix, err := r.client.Get(context.Background(), redisUserPrefix+userID).Int64()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("auth: read user revocation cutoff: %w", err)
+	}
+	return !issuedAt.After(time.Unix(cutoffUnix, 0)), nil
+}
+
+func (r *RedisTokenStore) exists(key string) (bool, error) {
+	n, err := r.client.Exists(context.Background(), key).Result()
+	if err != nil {
+		return false, fmt.Errorf("auth: check %s: %w", key, err)
+	}
+	return n > 0, nil
+}
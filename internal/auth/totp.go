@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image/png"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// totpPeriodSeconds is the standard TOTP time-step width; both Generate
+// and ValidateCustom below assume it.
+const totpPeriodSeconds = 30
+
+// recoveryCodeCount is how many single-use recovery codes ConfirmTOTP
+// mints when a user completes enrollment.
+const recoveryCodeCount = 8
+
+// MFALoginChallengeTTL bounds how long the challenge token AuthenticateUser
+// returns for an MFA-enabled user stays redeemable at AuthenticateMFA.
+const MFALoginChallengeTTL = 5 * time.Minute
+
+// ErrMFARequired is defined in errors.go, alongside the rest of this
+// package's typed AuthError taxonomy.
+
+// issueMFAChallengeToken signs a short-lived token binding userID to this
+// login attempt, the same HMAC-over-pipe-delimited-payload construction as
+// IssueMFAAssertion in mfa.go, just for a different purpose (redeeming a
+// login, not stepping up an already-authenticated session).
+func (s *AuthService) issueMFAChallengeToken(userID string) string {
+	exp := s.now().Add(MFALoginChallengeTTL).Unix()
+	payload := fmt.Sprintf("mfa_login|%s|%d", userID, exp)
+	mac := hmac.New(sha256.New, []byte(s.jwtSecret))
+	mac.Write([]byte(payload))
+	sig := hex.EncodeToString(mac.Sum(nil))
+	return fmt.Sprintf("%s.%s", payload, sig)
+}
+
+func (s *AuthService) parseMFAChallengeToken(token string) (userID string, err error) {
+	parts := splitLast(token, '.')
+	if len(parts) != 2 {
+		return "", errors.New("malformed mfa challenge token")
+	}
+	payload, sig := parts[0], parts[1]
+
+	mac := hmac.New(sha256.New, []byte(s.jwtSecret))
+	mac.Write([]byte(payload))
+	want := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(sig), []byte(want)) {
+		return "", errors.New("mfa challenge token signature invalid")
+	}
+
+	fields := splitPipe(payload)
+	if len(fields) != 3 || fields[0] != "mfa_login" {
+		return "", errors.New("malformed mfa challenge token payload")
+	}
+	var exp int64
+	if _, err := fmt.Sscanf(fields[2], "%d", &exp); err != nil {
+		return "", errors.New("malformed mfa challenge token expiry")
+	}
+	if s.now().Unix() > exp {
+		return "", errors.New("mfa challenge token expired")
+	}
+	return fields[1], nil
+}
+
+// EnrollTOTP starts TOTP enrollment for userID: it generates a new secret
+// and a QR code PNG a client can scan into an authenticator app, and
+// persists the secret on the user record. MFA isn't enforced until
+// ConfirmTOTP proves the user can actually generate valid codes with it.
+func (s *AuthService) EnrollTOTP(userID string) (secret string, qrPNG []byte, err error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return "", nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      "insec",
+		AccountName: user.Email,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("generate totp key: %w", err)
+	}
+
+	img, err := key.Image(200, 200)
+	if err != nil {
+		return "", nil, fmt.Errorf("render totp qr code: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", nil, fmt.Errorf("encode totp qr code: %w", err)
+	}
+
+	user.MFASecret = key.Secret()
+	if err := s.repo.UpdateUser(user); err != nil {
+		return "", nil, fmt.Errorf("persist totp secret: %w", err)
+	}
+
+	return key.Secret(), buf.Bytes(), nil
+}
+
+// ConfirmTOTP completes enrollment: it validates code against the secret
+// EnrollTOTP stored, and on success enables MFA and mints a fresh batch of
+// single-use recovery codes. The codes are returned in cleartext exactly
+// once - only their hashes are persisted, the same way a password is never
+// stored in cleartext either - so the caller must surface them to the user
+// immediately.
+func (s *AuthService) ConfirmTOTP(userID, code string) ([]string, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if user.MFASecret == "" {
+		return nil, errors.New("totp enrollment has not been started")
+	}
+	if !s.validateTOTP(user, code) {
+		return nil, errors.New("invalid totp code")
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashed := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i] = s.GenerateSecureToken()[:10]
+		hashed[i] = hashRecoveryCode(codes[i])
+	}
+
+	user.MFAEnabled = true
+	user.MFARecoveryCodes = hashed
+	if err := s.repo.UpdateUser(user); err != nil {
+		return nil, fmt.Errorf("persist mfa enrollment: %w", err)
+	}
+	return codes, nil
+}
+
+// AuthenticateMFA redeems a challenge token from AuthenticateUser for a
+// real access token, given either the user's current TOTP code or one of
+// their recovery codes.
+func (s *AuthService) AuthenticateMFA(challengeToken, totpCode string) (string, error) {
+	userID, err := s.parseMFAChallengeToken(challengeToken)
+	if err != nil {
+		return "", err
+	}
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return "", fmt.Errorf("user not found: %w", err)
+	}
+	if !user.MFAEnabled {
+		return "", errors.New("mfa is not enabled for this user")
+	}
+
+	if s.validateTOTP(user, totpCode) {
+		return s.issueAccessToken(user)
+	}
+	if err := s.ConsumeRecoveryCode(user.ID, totpCode); err == nil {
+		return s.issueAccessToken(user)
+	}
+	return "", errors.New("invalid totp code")
+}
+
+// validateTOTP checks code against user's stored secret with a ±1-step
+// window (Skew: 1) to tolerate clock drift between client and server, and
+// guards against replay within one time-step: once a code is accepted,
+// every code is rejected until the server's clock reaches the next 30s
+// step, even one that's still mathematically valid. The guard keys on the
+// server's step, not the step the code was generated for, so a code
+// accepted late in one step can be accepted once more in the next step
+// while the skew window still covers it.
+func (s *AuthService) validateTOTP(user *User, code string) bool {
+	if user.MFASecret == "" || code == "" {
+		return false
+	}
+	now := s.now()
+	step := now.Unix() / totpPeriodSeconds
+	if user.MFALastUsedStep != 0 && user.MFALastUsedStep == step {
+		return false
+	}
+
+	ok, err := totp.ValidateCustom(code, user.MFASecret, now, totp.ValidateOpts{
+		Period:    totpPeriodSeconds,
+		Skew:      1,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	if err != nil || !ok {
+		return false
+	}
+
+	user.MFALastUsedStep = step
+	_ = s.repo.UpdateUser(user)
+	return true
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// ConsumeRecoveryCode redeems one of userID's single-use MFA recovery
+// codes, removing it so it can never be used again.
+func (s *AuthService) ConsumeRecoveryCode(userID, code string) error {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	hashed := hashRecoveryCode(code)
+	for i, candidate := range user.MFARecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(hashed)) == 1 {
+			user.MFARecoveryCodes = append(user.MFARecoveryCodes[:i], user.MFARecoveryCodes[i+1:]...)
+			if err := s.repo.UpdateUser(user); err != nil {
+				return fmt.Errorf("persist recovery code consumption: %w", err)
+			}
+			return nil
+		}
+	}
+	return errors.New("invalid recovery code")
+}
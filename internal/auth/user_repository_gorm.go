@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// userRow is this package's own gorm-mapped view of the users table
+// (created by internal/migrations' users migration), storing
+// MFARecoveryCodes as text[] rather than importing any live application
+// model - the same reason internal/ueba and internal/eventstore freeze
+// their own local struct copies instead of depending on server's
+// package-main models.
+type userRow struct {
+	ID               string    `gorm:"column:id;primaryKey"`
+	Email            string    `gorm:"column:email;uniqueIndex"`
+	Name             string    `gorm:"column:name"`
+	Password         string    `gorm:"column:password"`
+	Role             string    `gorm:"column:role"`
+	TenantID         string    `gorm:"column:tenant_id;index"`
+	MFASecret        string    `gorm:"column:mfa_secret"`
+	MFAEnabled       bool      `gorm:"column:mfa_enabled"`
+	MFARecoveryCodes []string  `gorm:"column:mfa_recovery_codes;type:text[]"`
+	MFALastUsedStep  int64     `gorm:"column:mfa_last_used_step"`
+	CreatedAt        time.Time `gorm:"column:created_at"`
+	UpdatedAt        time.Time `gorm:"column:updated_at"`
+}
+
+func (userRow) TableName() string { return "users" }
+
+func (r userRow) toUser() *User {
+	return &User{
+		ID:               r.ID,
+		Email:            r.Email,
+		Name:             r.Name,
+		Password:         r.Password,
+		Role:             r.Role,
+		TenantID:         r.TenantID,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+		MFASecret:        r.MFASecret,
+		MFAEnabled:       r.MFAEnabled,
+		MFARecoveryCodes: r.MFARecoveryCodes,
+		MFALastUsedStep:  r.MFALastUsedStep,
+	}
+}
+
+func userToRow(u *User) userRow {
+	return userRow{
+		ID:               u.ID,
+		Email:            u.Email,
+		Name:             u.Name,
+		Password:         u.Password,
+		Role:             u.Role,
+		TenantID:         u.TenantID,
+		CreatedAt:        u.CreatedAt,
+		UpdatedAt:        u.UpdatedAt,
+		MFASecret:        u.MFASecret,
+		MFAEnabled:       u.MFAEnabled,
+		MFARecoveryCodes: u.MFARecoveryCodes,
+		MFALastUsedStep:  u.MFALastUsedStep,
+	}
+}
+
+// GormUserRepository is the UserRepository backing production use,
+// persisting Users to the users table.
+type GormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewGormUserRepository wraps an existing *gorm.DB.
+func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
+	return &GormUserRepository{db: db}
+}
+
+// GetUserByID implements UserRepository.
+func (r *GormUserRepository) GetUserByID(id string) (*User, error) {
+	var row userRow
+	if err := r.db.Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("auth: user %s not found", id)
+		}
+		return nil, fmt.Errorf("auth: load user %s: %w", id, err)
+	}
+	return row.toUser(), nil
+}
+
+// GetUserByEmail implements UserRepository.
+func (r *GormUserRepository) GetUserByEmail(email string) (*User, error) {
+	var row userRow
+	if err := r.db.Where("email = ?", email).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("auth: user with email %s not found", email)
+		}
+		return nil, fmt.Errorf("auth: load user by email %s: %w", email, err)
+	}
+	return row.toUser(), nil
+}
+
+// CreateUser implements UserRepository.
+func (r *GormUserRepository) CreateUser(user *User) error {
+	row := userToRow(user)
+	if err := r.db.Create(&row).Error; err != nil {
+		return fmt.Errorf("auth: create user %s: %w", user.ID, err)
+	}
+	user.CreatedAt = row.CreatedAt
+	user.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+// UpdateUser implements UserRepository.
+func (r *GormUserRepository) UpdateUser(user *User) error {
+	row := userToRow(user)
+	if err := r.db.Model(&userRow{}).Where("id = ?", user.ID).Updates(&row).Error; err != nil {
+		return fmt.Errorf("auth: update user %s: %w", user.ID, err)
+	}
+	return nil
+}
+
+// DeleteUser implements UserRepository.
+func (r *GormUserRepository) DeleteUser(id string) error {
+	if err := r.db.Where("id = ?", id).Delete(&userRow{}).Error; err != nil {
+		return fmt.Errorf("auth: delete user %s: %w", id, err)
+	}
+	return nil
+}
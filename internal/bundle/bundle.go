@@ -0,0 +1,188 @@
+// Package bundle decodes the streaming, GS-framed manifest
+// POST /v1/events/bundle accepts: a single request body holding a
+// \x1DBEGIN-META\x1D/END-META JSON header, a \x1DBEGIN-EVENTS\x1D/END-EVENTS
+// NDJSON section, and an optional \x1DBEGIN-FILES\x1D/END-FILES section of
+// length-prefixed sample artifact blobs - borrowed from konveyor's analysis
+// upload format (section markers framed by the ASCII Group Separator byte,
+// 0x1D, instead of a multipart boundary) so an endpoint agent can batch
+// hours of offline telemetry over one gzip+TLS stream instead of one HTTP
+// request per event.
+//
+// Parse streams the body through a bufio.Scanner with a custom split
+// function (split.go) and invokes Handler's callbacks as it goes, so a
+// caller never has to hold the whole bundle - potentially hours of
+// events plus sample binaries - in memory at once.
+//
+// Each section's END marker must be preceded by its own section-appropriate
+// terminator (a newline for EVENTS, nothing extra for META and FILES, whose
+// framing is self-delimiting) - Parse does not attempt to recover a missing
+// one.
+package bundle
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// groupSeparator is the ASCII Group Separator (0x1D) every section marker
+// in this format is wrapped in.
+const groupSeparator = 0x1D
+
+const (
+	markerBeginMeta   = "BEGIN-META"
+	markerEndMeta     = "END-META"
+	markerBeginEvents = "BEGIN-EVENTS"
+	markerEndEvents   = "END-EVENTS"
+	markerBeginFiles  = "BEGIN-FILES"
+	markerEndFiles    = "END-FILES"
+)
+
+// maxTokenSize bounds the largest single scanner token Parse will accept -
+// one NDJSON event line, or one file blob frame including its header. 64MiB
+// comfortably covers both; a bundle needing larger sample artifacts should
+// reference them by hash and ship them out of band instead.
+const maxTokenSize = 64 << 20
+
+// Meta is the bundle's header, identifying which tenant/host/agent produced
+// it and the window of time it covers.
+type Meta struct {
+	TenantID    string    `json:"tenant_id"`
+	HostID      string    `json:"host_id"`
+	AgentVer    string    `json:"agent_ver"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+}
+
+// FileBlob is one sample artifact from the FILES section, keyed by the hash
+// FileInfo.Hash elsewhere in the bundle references it by.
+type FileBlob struct {
+	Hash string
+	Data []byte
+}
+
+// Handler receives Parse's callbacks. OnMeta fires once, OnEvent fires once
+// per NDJSON line in the EVENTS section, OnFile fires once per blob in the
+// optional FILES section. A nil callback is simply skipped; a non-nil one
+// returning an error aborts the parse with that error.
+type Handler struct {
+	OnMeta  func(Meta) error
+	OnEvent func(json.RawMessage) error
+	OnFile  func(FileBlob) error
+}
+
+// section is which of the manifest's GS-framed sections the scanner is
+// currently positioned inside, driving both the split function's framing
+// rules and Parse's own dispatch of each content token.
+type section int
+
+const (
+	sectionOutside section = iota
+	sectionMeta
+	sectionEvents
+	sectionFiles
+)
+
+// Parse reads a bundle from r, invoking h's callbacks as it streams through
+// each section. It returns the first error a callback returns, or a parse
+// error if the manifest framing itself is malformed or truncated.
+func Parse(r io.Reader, h Handler) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxTokenSize)
+	scanner.Split(newManifestSplit())
+
+	current := sectionOutside
+	for scanner.Scan() {
+		token := scanner.Bytes()
+		if len(token) > 0 && token[0] == groupSeparator {
+			next, err := transition(string(token[1:]))
+			if err != nil {
+				return err
+			}
+			current = next
+			continue
+		}
+
+		switch current {
+		case sectionMeta:
+			var meta Meta
+			if err := json.Unmarshal(token, &meta); err != nil {
+				return fmt.Errorf("bundle: decode meta: %w", err)
+			}
+			if h.OnMeta != nil {
+				if err := h.OnMeta(meta); err != nil {
+					return err
+				}
+			}
+		case sectionEvents:
+			if len(token) == 0 {
+				continue
+			}
+			if h.OnEvent != nil {
+				raw := append(json.RawMessage(nil), token...)
+				if err := h.OnEvent(raw); err != nil {
+					return err
+				}
+			}
+		case sectionFiles:
+			blob, err := decodeFileBlob(token)
+			if err != nil {
+				return err
+			}
+			if h.OnFile != nil {
+				if err := h.OnFile(blob); err != nil {
+					return err
+				}
+			}
+		case sectionOutside:
+			// Content outside any section (e.g. a stray blank line between
+			// markers) is unexpected but harmless - ignore rather than
+			// failing an otherwise well-formed bundle over it.
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	return nil
+}
+
+// transition maps a marker name to the section Parse and the split
+// function should consider themselves in afterward.
+func transition(marker string) (section, error) {
+	switch marker {
+	case markerBeginMeta:
+		return sectionMeta, nil
+	case markerEndMeta, markerEndEvents, markerEndFiles:
+		return sectionOutside, nil
+	case markerBeginEvents:
+		return sectionEvents, nil
+	case markerBeginFiles:
+		return sectionFiles, nil
+	default:
+		return sectionOutside, fmt.Errorf("bundle: unknown section marker %q", marker)
+	}
+}
+
+// decodeFileBlob unpacks one FILES-section token: a 4-byte big-endian hash
+// length, the hash itself, an 8-byte big-endian blob length, then the blob
+// bytes - fully length-prefixed so a sample artifact's bytes never need to
+// be scanned for a delimiter that could legitimately appear inside it.
+func decodeFileBlob(frame []byte) (FileBlob, error) {
+	if len(frame) < 4 {
+		return FileBlob{}, fmt.Errorf("bundle: truncated file blob header")
+	}
+	hashLen := int(binary.BigEndian.Uint32(frame[:4]))
+	if len(frame) < 4+hashLen+8 {
+		return FileBlob{}, fmt.Errorf("bundle: truncated file blob header")
+	}
+	hash := string(frame[4 : 4+hashLen])
+	dataLen := int(binary.BigEndian.Uint64(frame[4+hashLen : 4+hashLen+8]))
+	data := frame[4+hashLen+8:]
+	if len(data) != dataLen {
+		return FileBlob{}, fmt.Errorf("bundle: file blob length mismatch for hash %s", hash)
+	}
+	return FileBlob{Hash: hash, Data: append([]byte(nil), data...)}, nil
+}
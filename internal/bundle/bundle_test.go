@@ -0,0 +1,136 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func marker(name string) []byte {
+	return append(append([]byte{groupSeparator}, []byte(name)...), groupSeparator)
+}
+
+func fileFrame(hash string, data []byte) []byte {
+	var buf bytes.Buffer
+	var hashLen [4]byte
+	binary.BigEndian.PutUint32(hashLen[:], uint32(len(hash)))
+	buf.Write(hashLen[:])
+	buf.WriteString(hash)
+	var dataLen [8]byte
+	binary.BigEndian.PutUint64(dataLen[:], uint64(len(data)))
+	buf.Write(dataLen[:])
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func buildBundle(meta Meta, events []string, files []FileBlob) []byte {
+	var buf bytes.Buffer
+	buf.Write(marker(markerBeginMeta))
+	metaJSON, _ := json.Marshal(meta)
+	buf.Write(metaJSON)
+	buf.Write(marker(markerEndMeta))
+
+	buf.Write(marker(markerBeginEvents))
+	for _, e := range events {
+		buf.WriteString(e)
+		buf.WriteByte('\n')
+	}
+	buf.Write(marker(markerEndEvents))
+
+	if files != nil {
+		buf.Write(marker(markerBeginFiles))
+		for _, f := range files {
+			buf.Write(fileFrame(f.Hash, f.Data))
+		}
+		buf.Write(marker(markerEndFiles))
+	}
+	return buf.Bytes()
+}
+
+func TestParse_FullBundleRoundTrips(t *testing.T) {
+	meta := Meta{TenantID: "t1", HostID: "h1", AgentVer: "1.2.3", WindowStart: time.Unix(0, 0).UTC(), WindowEnd: time.Unix(100, 0).UTC()}
+	events := []string{`{"type":"exec"}`, `{"type":"connect"}`}
+	files := []FileBlob{{Hash: "abc123", Data: []byte("binary-sample-data")}}
+
+	raw := buildBundle(meta, events, files)
+
+	var gotMeta Meta
+	var gotEvents []json.RawMessage
+	var gotFiles []FileBlob
+	err := Parse(bytes.NewReader(raw), Handler{
+		OnMeta:  func(m Meta) error { gotMeta = m; return nil },
+		OnEvent: func(e json.RawMessage) error { gotEvents = append(gotEvents, e); return nil },
+		OnFile:  func(f FileBlob) error { gotFiles = append(gotFiles, f); return nil },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, meta.TenantID, gotMeta.TenantID)
+	assert.True(t, meta.WindowStart.Equal(gotMeta.WindowStart))
+	require.Len(t, gotEvents, 2)
+	assert.JSONEq(t, events[0], string(gotEvents[0]))
+	require.Len(t, gotFiles, 1)
+	assert.Equal(t, "abc123", gotFiles[0].Hash)
+	assert.Equal(t, []byte("binary-sample-data"), gotFiles[0].Data)
+}
+
+func TestParse_BundleWithoutFilesSection(t *testing.T) {
+	raw := buildBundle(Meta{TenantID: "t1"}, []string{`{"type":"exec"}`}, nil)
+
+	var gotEvents int
+	var fileCallbackFired bool
+	err := Parse(bytes.NewReader(raw), Handler{
+		OnEvent: func(e json.RawMessage) error { gotEvents++; return nil },
+		OnFile:  func(f FileBlob) error { fileCallbackFired = true; return nil },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, gotEvents)
+	assert.False(t, fileCallbackFired)
+}
+
+func TestParse_NilCallbacksAreSkipped(t *testing.T) {
+	raw := buildBundle(Meta{TenantID: "t1"}, []string{`{"type":"exec"}`}, nil)
+	err := Parse(bytes.NewReader(raw), Handler{})
+	assert.NoError(t, err)
+}
+
+func TestParse_PropagatesCallbackError(t *testing.T) {
+	raw := buildBundle(Meta{TenantID: "t1"}, []string{`{"type":"exec"}`}, nil)
+	wantErr := assert.AnError
+	err := Parse(bytes.NewReader(raw), Handler{OnEvent: func(e json.RawMessage) error { return wantErr }})
+	assert.ErrorIs(t, err, wantErr)
+}
+
+func TestParse_RejectsUnknownMarker(t *testing.T) {
+	raw := string(marker("BEGIN-BOGUS"))
+	err := Parse(strings.NewReader(raw), Handler{})
+	assert.Error(t, err)
+}
+
+func TestParse_RejectsUnterminatedMetaSection(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(marker(markerBeginMeta))
+	buf.WriteString(`{"tenant_id":"t1"}`)
+	err := Parse(&buf, Handler{})
+	assert.Error(t, err)
+}
+
+func TestParse_RejectsTruncatedFileBlobHeader(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(marker(markerBeginMeta))
+	buf.WriteString(`{}`)
+	buf.Write(marker(markerEndMeta))
+	buf.Write(marker(markerBeginEvents))
+	buf.Write(marker(markerEndEvents))
+	buf.Write(marker(markerBeginFiles))
+	buf.Write([]byte{0x00, 0x00}) // truncated hash-length header
+	buf.Write(marker(markerEndFiles))
+
+	err := Parse(&buf, Handler{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SampleStore persists the sample artifact blobs a bundle's optional FILES
+// section carries, content-addressed by the same hash FileInfo.Hash
+// references elsewhere, so an operator investigating a file event can later
+// pull the binary that produced it.
+type SampleStore interface {
+	Save(blob FileBlob) error
+}
+
+// DiskSampleStore is the default SampleStore, writing each blob under
+// baseDir/<first two hash characters>/<hash> - the usual content-addressed
+// fan-out, so no single directory ends up with one entry per sample ever
+// uploaded.
+type DiskSampleStore struct {
+	baseDir string
+}
+
+// NewDiskSampleStore builds a DiskSampleStore rooted at baseDir, creating
+// it if it doesn't already exist.
+func NewDiskSampleStore(baseDir string) (*DiskSampleStore, error) {
+	if err := os.MkdirAll(baseDir, 0o750); err != nil {
+		return nil, fmt.Errorf("bundle: create sample store dir: %w", err)
+	}
+	return &DiskSampleStore{baseDir: baseDir}, nil
+}
+
+// Save implements SampleStore. A hash too short to fan out by (shouldn't
+// happen for a real content hash, but Parse doesn't otherwise validate
+// hash shape) is stored directly under baseDir instead of erroring.
+func (s *DiskSampleStore) Save(blob FileBlob) error {
+	if blob.Hash == "" {
+		return fmt.Errorf("bundle: sample blob missing hash")
+	}
+	dir := s.baseDir
+	if len(blob.Hash) >= 2 {
+		dir = filepath.Join(s.baseDir, blob.Hash[:2])
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("bundle: create sample store shard: %w", err)
+	}
+	path := filepath.Join(dir, blob.Hash)
+	if err := os.WriteFile(path, blob.Data, 0o640); err != nil {
+		return fmt.Errorf("bundle: write sample %s: %w", blob.Hash, err)
+	}
+	return nil
+}
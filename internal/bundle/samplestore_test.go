@@ -0,0 +1,50 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskSampleStore_SaveFansOutByHashPrefix(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDiskSampleStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(FileBlob{Hash: "abcdef123", Data: []byte("payload")}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "ab", "abcdef123"))
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(data))
+}
+
+func TestDiskSampleStore_SaveRejectsEmptyHash(t *testing.T) {
+	store, err := NewDiskSampleStore(t.TempDir())
+	require.NoError(t, err)
+	assert.Error(t, store.Save(FileBlob{Data: []byte("x")}))
+}
+
+func TestDiskSampleStore_SaveStoresShortHashDirectlyUnderBaseDir(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDiskSampleStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(FileBlob{Hash: "a", Data: []byte("payload")}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "a"))
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(data))
+}
+
+func TestNewDiskSampleStore_CreatesBaseDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "samples")
+	_, err := NewDiskSampleStore(dir)
+	require.NoError(t, err)
+
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+}
@@ -0,0 +1,96 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// newManifestSplit builds the bufio.SplitFunc Parse scans a bundle with.
+// It tracks which section the stream is currently inside (state local to
+// this closure, mirrored by Parse's own section variable as it observes
+// the same marker tokens) because each section frames its content
+// differently: META is one blob up to the next marker, EVENTS is
+// newline-delimited NDJSON, and FILES is a sequence of length-prefixed
+// blob frames that must never be scanned for a delimiter byte, since a
+// binary artifact could legitimately contain any byte value.
+func newManifestSplit() func(data []byte, atEOF bool) (int, []byte, error) {
+	state := sectionOutside
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		if data[0] == groupSeparator {
+			rest := data[1:]
+			idx := bytes.IndexByte(rest, groupSeparator)
+			if idx < 0 {
+				if atEOF {
+					return 0, nil, fmt.Errorf("bundle: unterminated section marker")
+				}
+				return 0, nil, nil
+			}
+			name := rest[:idx]
+			next, transitionErr := transition(string(name))
+			if transitionErr != nil {
+				return 0, nil, transitionErr
+			}
+			state = next
+			return 1 + idx + 1, append([]byte{groupSeparator}, name...), nil
+		}
+
+		switch state {
+		case sectionMeta:
+			idx := bytes.IndexByte(data, groupSeparator)
+			if idx < 0 {
+				if atEOF {
+					return 0, nil, fmt.Errorf("bundle: unterminated META section")
+				}
+				return 0, nil, nil
+			}
+			return idx, data[:idx], nil
+
+		case sectionEvents:
+			idx := bytes.IndexByte(data, '\n')
+			if idx < 0 {
+				if atEOF && len(data) > 0 {
+					return len(data), data, nil
+				}
+				return 0, nil, nil
+			}
+			return idx + 1, data[:idx], nil
+
+		case sectionFiles:
+			const headerPrefix = 4 // hash length
+			if len(data) < headerPrefix {
+				if atEOF {
+					return 0, nil, fmt.Errorf("bundle: truncated file blob header")
+				}
+				return 0, nil, nil
+			}
+			hashLen := int(binary.BigEndian.Uint32(data[:headerPrefix]))
+			need := headerPrefix + hashLen + 8 // + blob length
+			if len(data) < need {
+				if atEOF {
+					return 0, nil, fmt.Errorf("bundle: truncated file blob header")
+				}
+				return 0, nil, nil
+			}
+			blobLen := int(binary.BigEndian.Uint64(data[headerPrefix+hashLen : need]))
+			total := need + blobLen
+			if len(data) < total {
+				if atEOF {
+					return 0, nil, fmt.Errorf("bundle: truncated file blob data")
+				}
+				return 0, nil, nil
+			}
+			return total, data[:total], nil
+
+		default: // sectionOutside
+			// Not positioned in any section and this isn't a marker -
+			// skip one byte at a time (e.g. whitespace between sections)
+			// rather than erroring.
+			return 1, nil, nil
+		}
+	}
+}
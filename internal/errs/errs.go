@@ -0,0 +1,89 @@
+// Package errs provides a structured, stable-error-code alternative to
+// handlers returning ad-hoc gin.H{"error": err.Error()} bodies, so clients
+// can branch on a machine-readable code instead of matching message text.
+package errs
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error is a typed API error carrying both an HTTP status and a stable,
+// machine-readable Code (e.g. "alert.title.too_long", "auth.token.expired").
+type Error struct {
+	Status  int
+	Code    string
+	Msg     string
+	Details map[string]any
+	wrapped error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.wrapped != nil {
+		return fmt.Sprintf("%s: %v", e.Msg, e.wrapped)
+	}
+	return e.Msg
+}
+
+// Unwrap exposes the wrapped internal error to errors.Is/As; the middleware
+// only ever renders e.Msg to the client, never Error()'s wrapped text.
+func (e *Error) Unwrap() error {
+	return e.wrapped
+}
+
+// StatusCode implements the StatusCoder interface.
+func (e *Error) StatusCode() int {
+	return e.Status
+}
+
+// StatusCoder is implemented by any error that knows its own HTTP status,
+// letting the recovery middleware render a response without a type switch
+// over every concrete error type in the codebase.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Wrap attaches an internal error to e for logging, without changing what
+// gets rendered to the client.
+func (e *Error) Wrap(err error) *Error {
+	e.wrapped = err
+	return e
+}
+
+// WithDetails attaches structured, client-safe detail fields to e.
+func (e *Error) WithDetails(details map[string]any) *Error {
+	e.Details = details
+	return e
+}
+
+func newError(status int, code, msg string) *Error {
+	return &Error{Status: status, Code: code, Msg: msg}
+}
+
+// BadRequest builds a 400 error with the given stable code.
+func BadRequest(code, msg string) *Error { return newError(http.StatusBadRequest, code, msg) }
+
+// Unauthorized builds a 401 error with the given stable code.
+func Unauthorized(code, msg string) *Error { return newError(http.StatusUnauthorized, code, msg) }
+
+// Forbidden builds a 403 error with the given stable code.
+func Forbidden(code, msg string) *Error { return newError(http.StatusForbidden, code, msg) }
+
+// NotFound builds a 404 error with the given stable code.
+func NotFound(code, msg string) *Error { return newError(http.StatusNotFound, code, msg) }
+
+// TooManyRequests builds a 429 error with the given stable code.
+func TooManyRequests(code, msg string) *Error {
+	return newError(http.StatusTooManyRequests, code, msg)
+}
+
+// Internal builds a 500 error with the given stable code. Callers should
+// always Wrap the underlying cause so it reaches the logs even though it
+// never reaches the client.
+func Internal(code, msg string) *Error { return newError(http.StatusInternalServerError, code, msg) }
+
+// BadGateway builds a 502 error with the given stable code, for when this
+// server's request depended on an upstream (e.g. a notification plugin)
+// that itself failed.
+func BadGateway(code, msg string) *Error { return newError(http.StatusBadGateway, code, msg) }
@@ -0,0 +1,73 @@
+package errs
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// responseBody is the uniform JSON shape every error renders as, so clients
+// never need to parse free-form message text.
+type responseBody struct {
+	Code      string         `json:"code"`
+	Message   string         `json:"message"`
+	RequestID string         `json:"request_id"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+// Middleware recovers panics and renders any error attached via c.Error(...)
+// as a uniform JSON body, logging the wrapped internal error (if any)
+// alongside the request ID for correlation.
+func Middleware(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID, _ := c.Get("request_id")
+		reqID, _ := requestID.(string)
+		if reqID == "" {
+			reqID = uuid.New().String()
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				logger.WithField("request_id", reqID).WithField("panic", r).Error("panic recovered in handler")
+				render(c, reqID, Internal("internal.panic", "internal server error"), logger)
+				c.Abort()
+			}
+		}()
+
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		apiErr, ok := err.(*Error)
+		if !ok {
+			apiErr = Internal("internal.unhandled", "internal server error").Wrap(err)
+		}
+		render(c, reqID, apiErr, logger)
+	}
+}
+
+func render(c *gin.Context, requestID string, apiErr *Error, logger *logrus.Logger) {
+	if apiErr.wrapped != nil {
+		logger.WithError(apiErr.wrapped).
+			WithField("request_id", requestID).
+			WithField("code", apiErr.Code).
+			Error("request failed")
+	}
+
+	status := apiErr.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	c.JSON(status, responseBody{
+		Code:      apiErr.Code,
+		Message:   apiErr.Msg,
+		RequestID: requestID,
+		Details:   apiErr.Details,
+	})
+}
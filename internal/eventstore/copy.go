@@ -0,0 +1,173 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// EventRow is this package's own flat, column-level view of the events
+// table - the same frozen-struct convention internal/migrations uses,
+// rather than importing server.Event (server is package main; nothing
+// outside it can import its types). Bundle ingest (server/bundle_handler.go)
+// builds these directly off the decoded NDJSON events it streams in, since
+// COPY FROM and chunked multi-row INSERT both need a flat row, not the
+// nested User/OS/Process/Network/File structs Event embeds.
+type EventRow struct {
+	Timestamp     time.Time
+	TenantID      string
+	HostID        string
+	UserID        string
+	UserEmail     string
+	UserDept      string
+	OSFamily      string
+	OSVersion     string
+	OSArch        string
+	EventType     string
+	EventID       string
+	EventCat      string
+	ProcName      *string
+	ProcPPID      *uint32
+	ProcPID       *uint32
+	ProcHash      *string
+	NetSrcIP      *string
+	NetDstIP      *string
+	NetDstPort    *uint16
+	NetProtocol   *string
+	NetDomain     *string
+	FilePath      *string
+	FileOperation *string
+	FileHash      *string
+	Labels        []string
+	RiskHints     []string
+	AgentVer      string
+	AgentMode     string
+	AgentHost     string
+	SessionID     string
+	CgroupID      uint64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// eventRowColumns is the column list CopyInsertEvents and its chunked-insert
+// fallback both build rows against, in struct-field order. Keeping this a
+// single slice (rather than repeating the column names at every call site)
+// means a future column addition to EventRow only has to update this slice
+// and values().
+var eventRowColumns = []string{
+	"timestamp", "tenant_id", "host_id",
+	"user_id", "user_email", "user_dept",
+	"os_family", "os_version", "os_arch",
+	"event_type", "event_id", "event_category",
+	"proc_name", "proc_ppid", "proc_pid", "proc_hash",
+	"net_src_ip", "net_dst_ip", "net_dst_port", "net_protocol", "net_domain",
+	"file_path", "file_operation", "file_hash",
+	"labels", "risk_hints",
+	"agent_ver", "agent_mode", "agent_hostname",
+	"session_id", "cgroup_id",
+	"created_at", "updated_at",
+}
+
+func (r EventRow) values() []interface{} {
+	return []interface{}{
+		r.Timestamp, r.TenantID, r.HostID,
+		r.UserID, r.UserEmail, r.UserDept,
+		r.OSFamily, r.OSVersion, r.OSArch,
+		r.EventType, r.EventID, r.EventCat,
+		r.ProcName, r.ProcPPID, r.ProcPID, r.ProcHash,
+		r.NetSrcIP, r.NetDstIP, r.NetDstPort, r.NetProtocol, r.NetDomain,
+		r.FilePath, r.FileOperation, r.FileHash,
+		r.Labels, r.RiskHints,
+		r.AgentVer, r.AgentMode, r.AgentHost,
+		r.SessionID, r.CgroupID,
+		r.CreatedAt, r.UpdatedAt,
+	}
+}
+
+// copyBatchSize bounds how many rows CopyInsertEvents' non-Postgres
+// fallback puts in a single multi-row INSERT, so a very large bundle still
+// produces bounded-size statements instead of one with thousands of value
+// tuples.
+const copyBatchSize = 200
+
+// CopyInsertEvents bulk-loads rows into the partitioned events table. On
+// Postgres it streams them through a server-side COPY FROM via pgx, the
+// reason server/bundle_handler.go exists: a multi-hour offline batch can be
+// loaded as it's decoded, without ever holding the whole bundle in memory.
+// SQLite - this project's test dialector, which CREATE TABLE already leaves
+// unpartitioned - has no COPY FROM, so it falls back to chunked multi-row
+// INSERTs of the same rows.
+func (s *EventStore) CopyInsertEvents(ctx context.Context, rows []EventRow) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if !s.partitioned() {
+		return s.insertEventRowsChunked(rows)
+	}
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return 0, fmt.Errorf("acquire sql.DB for copy: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("acquire connection for copy: %w", err)
+	}
+	defer conn.Close()
+
+	source := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		source[i] = row.values()
+	}
+
+	var copied int64
+	err = conn.Raw(func(driverConn interface{}) error {
+		pgConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("copy requires the pgx stdlib driver, got %T", driverConn)
+		}
+		n, err := pgConn.Conn().CopyFrom(ctx, pgx.Identifier{eventsParentTable}, eventRowColumns, pgx.CopyFromRows(source))
+		copied = n
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("copy events: %w", err)
+	}
+	return copied, nil
+}
+
+// insertEventRowsChunked is CopyInsertEvents' non-Postgres fallback,
+// batching rows into copyBatchSize-sized multi-row INSERT statements.
+func (s *EventStore) insertEventRowsChunked(rows []EventRow) (int64, error) {
+	var inserted int64
+	for start := 0; start < len(rows); start += copyBatchSize {
+		end := start + copyBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		chunk := rows[start:end]
+
+		placeholders := make([]string, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*len(eventRowColumns))
+		for i, row := range chunk {
+			values := row.values()
+			marks := make([]string, len(values))
+			for j := range values {
+				marks[j] = "?"
+			}
+			placeholders[i] = "(" + strings.Join(marks, ", ") + ")"
+			args = append(args, values...)
+		}
+
+		stmt := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", eventsParentTable, strings.Join(eventRowColumns, ", "), strings.Join(placeholders, ", "))
+		if err := s.db.Exec(stmt, args...).Error; err != nil {
+			return inserted, fmt.Errorf("insert event chunk: %w", err)
+		}
+		inserted += int64(len(chunk))
+	}
+	return inserted, nil
+}
@@ -0,0 +1,187 @@
+// Package eventstore manages the Event table's lifecycle on top of Postgres
+// native range partitioning: pre-creating the partitions upcoming writes
+// will land in, retiring (optionally archiving first) the ones a tenant's
+// retention policy has aged out, and reporting that policy so an admin can
+// inspect or override it. On a dialector with no native partitioning -
+// SQLite, this project's test database - every partition operation is a
+// no-op, since the initial schema migration already leaves SQLite with a
+// single unpartitioned events table that accepts every row regardless.
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// DefaultPartitionWindow is how wide each native range partition is when an
+// EventStore isn't given an explicit one - one partition per calendar day,
+// the granularity this project's expected ingestion volume needs.
+const DefaultPartitionWindow = 24 * time.Hour
+
+// DefaultRetentionDays is how long a tenant's events are kept when it has
+// no event_retention Policy of its own.
+const DefaultRetentionDays = 90
+
+// eventsParentTable is the partitioned parent table name - unchanged from
+// the plain table the Event model mapped to before this package existed,
+// so every existing query against "events" keeps working.
+const eventsParentTable = "events"
+
+// retentionPolicyName is the well-known Policy.Name this package reads its
+// per-tenant retention override from, keyed by Policy.TenantID.
+const retentionPolicyName = "event_retention"
+
+// postgresDialectName is gorm's Dialector.Name() for the Postgres driver;
+// any other name (sqlite in tests) gets the non-partitioned fallback.
+const postgresDialectName = "postgres"
+
+// Archiver exports a partition's rows somewhere durable (e.g. Parquet on
+// S3) before EventStore drops it, so retiring a partition doesn't have to
+// mean deleting the data for a deployment that needs to keep it around.
+// Nil means drop straight away with nothing exported.
+type Archiver interface {
+	Archive(ctx context.Context, partitionTable string, windowStart, windowEnd time.Time) error
+}
+
+// EventStore manages partition lifecycle for the Event table.
+type EventStore struct {
+	db       *gorm.DB
+	logger   *logrus.Logger
+	window   time.Duration
+	archiver Archiver
+}
+
+// NewEventStore builds an EventStore with the default one-day partition
+// window and no archiver.
+func NewEventStore(db *gorm.DB, logger *logrus.Logger) *EventStore {
+	return NewEventStoreWithWindow(db, logger, DefaultPartitionWindow)
+}
+
+// NewEventStoreWithWindow builds an EventStore with a custom partition
+// window, e.g. hourly for a tenant ingesting at a rate daily partitions
+// would make too large to prune efficiently.
+func NewEventStoreWithWindow(db *gorm.DB, logger *logrus.Logger, window time.Duration) *EventStore {
+	return &EventStore{db: db, logger: logger, window: window}
+}
+
+// NewEventStoreWithArchiver builds on NewEventStoreWithWindow with one more
+// override: archiver, which PruneExpiredPartitions runs against a
+// partition's data right before dropping it. Nil behaves exactly like
+// NewEventStoreWithWindow.
+func NewEventStoreWithArchiver(db *gorm.DB, logger *logrus.Logger, window time.Duration, archiver Archiver) *EventStore {
+	return &EventStore{db: db, logger: logger, window: window, archiver: archiver}
+}
+
+// partitioned reports whether this store's database supports native range
+// partitioning (Postgres) or needs the single-table fallback (SQLite).
+func (s *EventStore) partitioned() bool {
+	return s.db.Name() == postgresDialectName
+}
+
+// policyRow is this package's own minimal view of the policies table,
+// covering only the columns Retention/SetRetention need - mirroring
+// server.Policy's shape rather than importing it, since server is package
+// main and nothing outside it can import its types (the same reason
+// internal/migrations freezes its own local struct copies).
+type policyRow struct {
+	ID       string `gorm:"column:id;primaryKey"`
+	TenantID string `gorm:"column:tenant_id"`
+	Name     string `gorm:"column:name"`
+	Config   []byte `gorm:"column:config"`
+}
+
+func (policyRow) TableName() string { return "policies" }
+
+type retentionConfig struct {
+	RetentionDays float64 `json:"retention_days"`
+}
+
+// Retention reports how long tenantID's events should be kept before
+// they're eligible for pruning: the retention_days key of its
+// "event_retention" Policy, or DefaultRetentionDays if it has none.
+func (s *EventStore) Retention(tenantID string) (time.Duration, error) {
+	var row policyRow
+	err := s.db.Where("tenant_id = ? AND name = ?", tenantID, retentionPolicyName).First(&row).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return DefaultRetentionDays * 24 * time.Hour, nil
+		}
+		return 0, fmt.Errorf("load retention policy for tenant %s: %w", tenantID, err)
+	}
+	var cfg retentionConfig
+	if err := json.Unmarshal(row.Config, &cfg); err != nil || cfg.RetentionDays <= 0 {
+		return DefaultRetentionDays * 24 * time.Hour, nil
+	}
+	return time.Duration(cfg.RetentionDays * float64(24*time.Hour)), nil
+}
+
+// SetRetention overrides tenantID's event retention, creating its
+// event_retention Policy if it doesn't already have one.
+func (s *EventStore) SetRetention(tenantID string, days float64) error {
+	if days <= 0 {
+		return fmt.Errorf("retention days must be positive, got %v", days)
+	}
+	cfg, err := json.Marshal(retentionConfig{RetentionDays: days})
+	if err != nil {
+		return fmt.Errorf("encode retention policy: %w", err)
+	}
+	row := policyRow{ID: uuid.New().String(), TenantID: tenantID, Name: retentionPolicyName, Config: cfg}
+	err = s.db.Where(policyRow{TenantID: tenantID, Name: retentionPolicyName}).
+		Assign(policyRow{Config: cfg}).
+		FirstOrCreate(&row).Error
+	if err != nil {
+		return fmt.Errorf("set retention policy for tenant %s: %w", tenantID, err)
+	}
+	return nil
+}
+
+// runMaintenanceOnce pre-creates upcoming partitions, drops (or archives
+// then drops) ones every tenant's retention has aged past, and then prunes,
+// row by row, any tenant whose own retention is shorter than that floor -
+// see PruneExpiredPartitions for why those two are different operations.
+// Only tenants with an event_retention Policy are pruned row by row; a
+// tenant without one keeps its events until their partition is dropped.
+func (s *EventStore) runMaintenanceOnce() {
+	now := time.Now()
+	if err := s.EnsurePartitions(now); err != nil {
+		s.logError("ensure partitions", err)
+		return
+	}
+
+	ctx := context.Background()
+	if err := s.PruneExpiredPartitions(ctx, now); err != nil {
+		s.logError("prune expired partitions", err)
+	}
+
+	tenants, err := s.tenantsWithRetentionPolicy()
+	if err != nil {
+		s.logError("list tenant retention policies", err)
+		return
+	}
+	for _, tenantID := range tenants {
+		if err := s.PruneTenantEvents(tenantID, now); err != nil {
+			s.logError("prune tenant events", err)
+		}
+	}
+}
+
+func (s *EventStore) tenantsWithRetentionPolicy() ([]string, error) {
+	var ids []string
+	err := s.db.Model(&policyRow{}).Where("name = ?", retentionPolicyName).Pluck("tenant_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("list tenants with retention policy: %w", err)
+	}
+	return ids, nil
+}
+
+func (s *EventStore) logError(step string, err error) {
+	if s.logger != nil {
+		s.logger.WithError(err).Errorf("Partition maintenance: %s failed", step)
+	}
+}
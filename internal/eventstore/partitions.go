@@ -0,0 +1,208 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// partitionTableFormat is how a partition's window start is encoded in its
+// table name, chosen so the name alone - with no catalog lookup - is enough
+// to recover the window it covers (see parsePartitionWindowStart).
+const partitionTableFormat = "20060102_150405"
+
+func partitionTableName(windowStart time.Time) string {
+	return fmt.Sprintf("%s_p%s", eventsParentTable, windowStart.UTC().Format(partitionTableFormat))
+}
+
+// parsePartitionWindowStart recovers the window start partitionTableName
+// encoded into name, or ok=false if name isn't one of this store's
+// partitions (e.g. it's the parent table itself).
+func parsePartitionWindowStart(name string) (t time.Time, ok bool) {
+	prefix := eventsParentTable + "_p"
+	if !strings.HasPrefix(name, prefix) {
+		return time.Time{}, false
+	}
+	parsed, err := time.Parse(partitionTableFormat, strings.TrimPrefix(name, prefix))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed.UTC(), true
+}
+
+// windowStart floors t to the start of the partition window it falls in.
+func (s *EventStore) windowStart(t time.Time) time.Time {
+	return t.UTC().Truncate(s.window)
+}
+
+// EnsurePartitions makes sure a partition exists for the window containing
+// now and the window immediately after it, so a write never lands in a
+// window whose partition hasn't been created yet. On the SQLite fallback
+// this is a no-op - the single unpartitioned table already accepts every
+// row.
+func (s *EventStore) EnsurePartitions(now time.Time) error {
+	if !s.partitioned() {
+		return nil
+	}
+	current := s.windowStart(now)
+	next := current.Add(s.window)
+	if err := s.createPartition(current); err != nil {
+		return err
+	}
+	return s.createPartition(next)
+}
+
+func (s *EventStore) createPartition(windowStart time.Time) error {
+	name := partitionTableName(windowStart)
+	windowEnd := windowStart.Add(s.window)
+	stmt := fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %q PARTITION OF %q FOR VALUES FROM (%s) TO (%s)`,
+		name, eventsParentTable, sqlTimeLiteral(windowStart), sqlTimeLiteral(windowEnd),
+	)
+	if err := s.db.Exec(stmt).Error; err != nil {
+		return fmt.Errorf("create partition %s: %w", name, err)
+	}
+	return nil
+}
+
+func sqlTimeLiteral(t time.Time) string {
+	return "'" + t.UTC().Format(time.RFC3339) + "'"
+}
+
+// listPartitionWindows returns the window start of every partition
+// currently attached to the events parent table, via pg_inherits rather
+// than trying to regenerate every possible window name and probe for it.
+func (s *EventStore) listPartitionWindows() ([]time.Time, error) {
+	rows, err := s.db.Raw(`
+		SELECT child.relname
+		FROM pg_inherits
+		JOIN pg_class parent ON pg_inherits.inhparent = parent.oid
+		JOIN pg_class child ON pg_inherits.inhrelid = child.oid
+		WHERE parent.relname = ?
+	`, eventsParentTable).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("list event partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var starts []time.Time
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("list event partitions: %w", err)
+		}
+		if start, ok := parsePartitionWindowStart(name); ok {
+			starts = append(starts, start)
+		}
+	}
+	return starts, rows.Err()
+}
+
+// longestRetention is the maximum of DefaultRetentionDays and every
+// tenant's event_retention Policy - the age past which no tenant keeps its
+// events any longer, and therefore the point before which a partition
+// (which may hold more than one tenant's rows) is safe to drop wholesale.
+func (s *EventStore) longestRetention() (time.Duration, error) {
+	var rows []policyRow
+	if err := s.db.Where("name = ?", retentionPolicyName).Find(&rows).Error; err != nil {
+		return 0, fmt.Errorf("load retention policies: %w", err)
+	}
+	longest := time.Duration(DefaultRetentionDays) * 24 * time.Hour
+	for _, row := range rows {
+		var cfg retentionConfig
+		if err := json.Unmarshal(row.Config, &cfg); err != nil || cfg.RetentionDays <= 0 {
+			continue
+		}
+		d := time.Duration(cfg.RetentionDays * float64(24*time.Hour))
+		if d > longest {
+			longest = d
+		}
+	}
+	return longest, nil
+}
+
+// PruneExpiredPartitions drops (archiving first, if this store has an
+// Archiver) every partition entirely older than longestRetention - the
+// floor every tenant's retention policy agrees the data can be gone by. A
+// tenant whose own retention is shorter than that floor isn't served by
+// this alone, since the partition still holds other tenants' live rows;
+// PruneTenantEvents handles that case with a row-level delete instead. On
+// the SQLite fallback this is a no-op.
+func (s *EventStore) PruneExpiredPartitions(ctx context.Context, now time.Time) error {
+	if !s.partitioned() {
+		return nil
+	}
+	floor, err := s.longestRetention()
+	if err != nil {
+		return err
+	}
+	cutoff := now.Add(-floor)
+
+	starts, err := s.listPartitionWindows()
+	if err != nil {
+		return err
+	}
+	for _, start := range starts {
+		end := start.Add(s.window)
+		if !end.Before(cutoff) {
+			continue
+		}
+		name := partitionTableName(start)
+		if s.archiver != nil {
+			if err := s.archiver.Archive(ctx, name, start, end); err != nil {
+				return fmt.Errorf("archive partition %s: %w", name, err)
+			}
+		}
+		if err := s.dropPartition(name); err != nil {
+			return err
+		}
+		if s.logger != nil {
+			s.logger.WithField("partition", name).Info("Partition maintenance: dropped expired event partition")
+		}
+	}
+	return nil
+}
+
+func (s *EventStore) dropPartition(name string) error {
+	detach := fmt.Sprintf(`ALTER TABLE %q DETACH PARTITION %q`, eventsParentTable, name)
+	if err := s.db.Exec(detach).Error; err != nil {
+		return fmt.Errorf("detach partition %s: %w", name, err)
+	}
+	drop := fmt.Sprintf(`DROP TABLE IF EXISTS %q`, name)
+	if err := s.db.Exec(drop).Error; err != nil {
+		return fmt.Errorf("drop partition %s: %w", name, err)
+	}
+	return nil
+}
+
+// eventRow is this package's own minimal view of the events table, covering
+// only the columns PruneTenantEvents deletes by - the same reason
+// policyRow doesn't import server's Event struct.
+type eventRow struct {
+	TenantID  string    `gorm:"column:tenant_id"`
+	Timestamp time.Time `gorm:"column:timestamp"`
+}
+
+func (eventRow) TableName() string { return eventsParentTable }
+
+// PruneTenantEvents deletes tenantID's events older than its own Retention,
+// for the part of its retention policy a partition-wide
+// PruneExpiredPartitions can't serve (see that method's doc comment).
+func (s *EventStore) PruneTenantEvents(tenantID string, now time.Time) error {
+	retention, err := s.Retention(tenantID)
+	if err != nil {
+		return err
+	}
+	cutoff := now.Add(-retention)
+	result := s.db.Where("tenant_id = ? AND timestamp < ?", tenantID, cutoff).Delete(&eventRow{})
+	if result.Error != nil {
+		return fmt.Errorf("prune events for tenant %s: %w", tenantID, result.Error)
+	}
+	if result.RowsAffected > 0 && s.logger != nil {
+		s.logger.WithField("tenant_id", tenantID).WithField("count", result.RowsAffected).
+			Info("Partition maintenance: pruned tenant events past retention")
+	}
+	return nil
+}
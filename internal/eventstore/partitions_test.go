@@ -0,0 +1,36 @@
+package eventstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionTableName_RoundTripsThroughParse(t *testing.T) {
+	windowStart := time.Date(2026, 3, 5, 14, 0, 0, 0, time.UTC)
+
+	name := partitionTableName(windowStart)
+	assert.Equal(t, "events_p20260305_140000", name)
+
+	parsed, ok := parsePartitionWindowStart(name)
+	assert.True(t, ok)
+	assert.True(t, windowStart.Equal(parsed))
+}
+
+func TestParsePartitionWindowStart_RejectsNonPartitionNames(t *testing.T) {
+	_, ok := parsePartitionWindowStart("events")
+	assert.False(t, ok, "the parent table itself is not one of its own partitions")
+
+	_, ok = parsePartitionWindowStart("something_else_p20260305_140000")
+	assert.False(t, ok)
+
+	_, ok = parsePartitionWindowStart("events_p-not-a-timestamp")
+	assert.False(t, ok)
+}
+
+func TestEventStore_WindowStartFloorsToWindowBoundary(t *testing.T) {
+	s := &EventStore{window: 24 * time.Hour}
+	got := s.windowStart(time.Date(2026, 3, 5, 17, 30, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), got)
+}
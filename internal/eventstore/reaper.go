@@ -0,0 +1,28 @@
+package eventstore
+
+import "time"
+
+// DefaultMaintenanceInterval is how often RunPartitionMaintenance wakes up
+// to pre-create upcoming partitions and prune ones a tenant's retention
+// policy has aged out of - frequent enough that the next partition window
+// is always created well before any write needs it.
+const DefaultMaintenanceInterval = time.Hour
+
+// RunPartitionMaintenance runs one maintenance pass immediately, then blocks
+// running another every interval until stop is closed. Meant to be run in
+// its own goroutine from main - the event-partitioning equivalent of
+// server.RunDecisionReaper.
+func RunPartitionMaintenance(store *EventStore, interval time.Duration, stop <-chan struct{}) {
+	store.runMaintenanceOnce()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			store.runMaintenanceOnce()
+		}
+	}
+}
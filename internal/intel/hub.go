@@ -0,0 +1,80 @@
+package intel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"insec/internal/models"
+)
+
+// HubClient talks to the upstream community hub: it pushes this tenant's
+// signed, anonymized signals and pulls back the consolidated Decisions the
+// hub has derived across every contributing tenant.
+type HubClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Signer     *Signer
+}
+
+// NewHubClient builds a HubClient against baseURL, signing outbound
+// signals with signer.
+func NewHubClient(baseURL string, signer *Signer) *HubClient {
+	return &HubClient{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Signer:     signer,
+	}
+}
+
+// Push signs and uploads signals to the hub. SourceTenantID is stripped
+// before signing, so the signature - and anything the hub or a downstream
+// tenant later sees - never ties an indicator back to the tenant that
+// reported it.
+func (h *HubClient) Push(signals []models.Signal) error {
+	signed := make([]SignedSignal, 0, len(signals))
+	for _, sig := range signals {
+		anonymized := sig
+		anonymized.SourceTenantID = ""
+		ss, err := h.Signer.Sign(anonymized)
+		if err != nil {
+			return fmt.Errorf("intel: sign signal: %w", err)
+		}
+		signed = append(signed, ss)
+	}
+
+	body, err := json.Marshal(signed)
+	if err != nil {
+		return fmt.Errorf("intel: marshal signed signals: %w", err)
+	}
+
+	resp, err := h.HTTPClient.Post(h.BaseURL+"/signals/push", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("intel: push to hub: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("intel: hub rejected push: %s", resp.Status)
+	}
+	return nil
+}
+
+// Pull fetches the hub's latest consolidated Decisions.
+func (h *HubClient) Pull() ([]models.Decision, error) {
+	resp, err := h.HTTPClient.Get(h.BaseURL + "/signals/pull")
+	if err != nil {
+		return nil, fmt.Errorf("intel: pull from hub: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("intel: hub rejected pull: %s", resp.Status)
+	}
+
+	var decisions []models.Decision
+	if err := json.NewDecoder(resp.Body).Decode(&decisions); err != nil {
+		return nil, fmt.Errorf("intel: decode decisions: %w", err)
+	}
+	return decisions, nil
+}
@@ -0,0 +1,104 @@
+// Package intel implements the cross-tenant threat-intel signal-sharing
+// subsystem: tenants derive models.Signal indicators from their own
+// resolved high-risk alerts, sign.go signs them for transport, hub.go
+// pushes/pulls them to/from an upstream community hub, and store.go holds
+// the consolidated models.Decision records the risk-scoring pipeline
+// consults for incoming telemetry.
+package intel
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"insec/internal/models"
+)
+
+// SignedSignal is the wire format pushed to the upstream hub: a Signal
+// plus an Ed25519 signature over its canonical JSON encoding, so the hub
+// (and anything that later relays it) can detect tampering in transit.
+type SignedSignal struct {
+	Signal    models.Signal `json:"signal"`
+	PublicKey string        `json:"public_key"` // hex-encoded ed25519.PublicKey
+	Signature string        `json:"signature"`  // hex-encoded
+}
+
+// Signer holds the Ed25519 keypair a tenant signs outbound signals with.
+type Signer struct {
+	public  ed25519.PublicKey
+	private ed25519.PrivateKey
+}
+
+// NewSigner wraps an existing Ed25519 keypair.
+func NewSigner(public ed25519.PublicKey, private ed25519.PrivateKey) *Signer {
+	return &Signer{public: public, private: private}
+}
+
+// GenerateSigner creates a fresh Ed25519 keypair, for a tenant enrolling
+// into signal sharing for the first time.
+func GenerateSigner() (*Signer, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("intel: generate signer keypair: %w", err)
+	}
+	return NewSigner(pub, priv), nil
+}
+
+// PrivateKeyBytes returns the raw Ed25519 private key, for callers (like
+// cmd/insec-cli) that need to persist it between runs.
+func (s *Signer) PrivateKeyBytes() []byte {
+	return []byte(s.private)
+}
+
+// SignerFromHex reconstructs a Signer from a hex-encoded Ed25519 private
+// key, the inverse of hex-encoding Signer.PrivateKeyBytes().
+func SignerFromHex(hexKey string) (*Signer, error) {
+	raw, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("intel: decode private key: %w", err)
+	}
+	priv := ed25519.PrivateKey(raw)
+	pub, ok := priv.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("intel: malformed private key")
+	}
+	return NewSigner(pub, priv), nil
+}
+
+// Sign signs sig and returns the wire-format SignedSignal.
+func (s *Signer) Sign(sig models.Signal) (SignedSignal, error) {
+	payload, err := json.Marshal(sig)
+	if err != nil {
+		return SignedSignal{}, fmt.Errorf("intel: marshal signal: %w", err)
+	}
+	signature := ed25519.Sign(s.private, payload)
+	return SignedSignal{
+		Signal:    sig,
+		PublicKey: hex.EncodeToString(s.public),
+		Signature: hex.EncodeToString(signature),
+	}, nil
+}
+
+// Verify checks that ss.Signature is a valid Ed25519 signature over
+// ss.Signal by the key embedded in ss.PublicKey.
+func Verify(ss SignedSignal) error {
+	payload, err := json.Marshal(ss.Signal)
+	if err != nil {
+		return fmt.Errorf("intel: marshal signal: %w", err)
+	}
+	pub, err := hex.DecodeString(ss.PublicKey)
+	if err != nil {
+		return fmt.Errorf("intel: decode public key: %w", err)
+	}
+	sig, err := hex.DecodeString(ss.Signature)
+	if err != nil {
+		return fmt.Errorf("intel: decode signature: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), payload, sig) {
+		return errors.New("intel: signature invalid")
+	}
+	return nil
+}
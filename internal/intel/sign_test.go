@@ -0,0 +1,79 @@
+package intel
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"insec/internal/models"
+)
+
+func testSignal() models.Signal {
+	now := time.Now().UTC().Truncate(time.Second)
+	return models.Signal{
+		ID:             "sig-1",
+		IndicatorType:  models.IndicatorIP,
+		Value:          "203.0.113.1",
+		Confidence:     80,
+		SourceTenantID: "tenant-1",
+		FirstSeen:      now,
+		LastSeen:       now,
+		TTL:            24 * time.Hour,
+	}
+}
+
+func TestSigner_SignAndVerify_RoundTrips(t *testing.T) {
+	signer, err := GenerateSigner()
+	require.NoError(t, err)
+
+	ss, err := signer.Sign(testSignal())
+	require.NoError(t, err)
+	assert.NoError(t, Verify(ss))
+}
+
+func TestVerify_RejectsTamperedSignal(t *testing.T) {
+	signer, err := GenerateSigner()
+	require.NoError(t, err)
+
+	ss, err := signer.Sign(testSignal())
+	require.NoError(t, err)
+
+	ss.Signal.Confidence = 100
+	assert.Error(t, Verify(ss), "mutating the signal after signing must invalidate the signature")
+}
+
+func TestVerify_RejectsWrongKey(t *testing.T) {
+	signer, err := GenerateSigner()
+	require.NoError(t, err)
+	ss, err := signer.Sign(testSignal())
+	require.NoError(t, err)
+
+	other, err := GenerateSigner()
+	require.NoError(t, err)
+	otherSS, err := other.Sign(testSignal())
+	require.NoError(t, err)
+
+	ss.PublicKey = otherSS.PublicKey
+	assert.Error(t, Verify(ss), "verifying against a different signer's public key must fail")
+}
+
+func TestSignerFromHex_ReconstructsSameSigner(t *testing.T) {
+	signer, err := GenerateSigner()
+	require.NoError(t, err)
+
+	hexKey := hex.EncodeToString(signer.PrivateKeyBytes())
+	restored, err := SignerFromHex(hexKey)
+	require.NoError(t, err)
+
+	ss, err := restored.Sign(testSignal())
+	require.NoError(t, err)
+	assert.NoError(t, Verify(ss))
+}
+
+func TestSignerFromHex_RejectsMalformedHex(t *testing.T) {
+	_, err := SignerFromHex("not-hex")
+	assert.Error(t, err)
+}
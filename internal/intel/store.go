@@ -0,0 +1,65 @@
+package intel
+
+import (
+	"sync"
+	"time"
+
+	"insec/internal/models"
+)
+
+// DecisionStore holds the Decisions a tenant has pulled from the hub, so
+// the risk-scoring hook in server/handlers.go can consult them on every
+// ingested event without a hub round trip. Match is strictly per-tenant:
+// it only ever consults decisions stored under the given tenantID, so a
+// signal one tenant opted into never boosts risk for a tenant that didn't.
+type DecisionStore interface {
+	Upsert(tenantID string, decisions []models.Decision)
+	Match(tenantID, scope, value string) (models.Decision, bool)
+}
+
+// MemDecisionStore is the in-memory DecisionStore used until chunk3-1's
+// token-store pattern is extended with a shared backend for this too.
+type MemDecisionStore struct {
+	mu       sync.RWMutex
+	byTenant map[string]map[string]models.Decision // tenantID -> "scope|value" -> Decision
+}
+
+// NewMemDecisionStore returns a ready-to-use MemDecisionStore.
+func NewMemDecisionStore() *MemDecisionStore {
+	return &MemDecisionStore{byTenant: make(map[string]map[string]models.Decision)}
+}
+
+func decisionKey(scope, value string) string {
+	return scope + "|" + value
+}
+
+// Upsert implements DecisionStore.
+func (m *MemDecisionStore) Upsert(tenantID string, decisions []models.Decision) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bucket, ok := m.byTenant[tenantID]
+	if !ok {
+		bucket = make(map[string]models.Decision)
+		m.byTenant[tenantID] = bucket
+	}
+	for _, d := range decisions {
+		bucket[decisionKey(d.Scope, d.Value)] = d
+	}
+}
+
+// Match implements DecisionStore.
+func (m *MemDecisionStore) Match(tenantID, scope, value string) (models.Decision, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bucket, ok := m.byTenant[tenantID]
+	if !ok {
+		return models.Decision{}, false
+	}
+	d, ok := bucket[decisionKey(scope, value)]
+	if !ok || !d.Active(time.Now()) {
+		return models.Decision{}, false
+	}
+	return d, true
+}
@@ -0,0 +1,61 @@
+package intel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"insec/internal/models"
+)
+
+func TestMemDecisionStore_UpsertAndMatch(t *testing.T) {
+	store := NewMemDecisionStore()
+	store.Upsert("tenant-1", []models.Decision{
+		{Scope: models.ScopeIP, Value: "203.0.113.1", ExpiresAt: time.Now().Add(time.Hour)},
+	})
+
+	got, ok := store.Match("tenant-1", models.ScopeIP, "203.0.113.1")
+	assert.True(t, ok)
+	assert.Equal(t, "203.0.113.1", got.Value)
+}
+
+func TestMemDecisionStore_MatchIsScopedPerTenant(t *testing.T) {
+	store := NewMemDecisionStore()
+	store.Upsert("tenant-1", []models.Decision{
+		{Scope: models.ScopeIP, Value: "203.0.113.1", ExpiresAt: time.Now().Add(time.Hour)},
+	})
+
+	_, ok := store.Match("tenant-2", models.ScopeIP, "203.0.113.1")
+	assert.False(t, ok, "a decision stored under tenant-1 must never be visible to tenant-2")
+}
+
+func TestMemDecisionStore_MatchIgnoresExpiredDecision(t *testing.T) {
+	store := NewMemDecisionStore()
+	store.Upsert("tenant-1", []models.Decision{
+		{Scope: models.ScopeIP, Value: "203.0.113.1", ExpiresAt: time.Now().Add(-time.Minute)},
+	})
+
+	_, ok := store.Match("tenant-1", models.ScopeIP, "203.0.113.1")
+	assert.False(t, ok, "an expired decision must not match")
+}
+
+func TestMemDecisionStore_UpsertOverwritesSameScopeAndValue(t *testing.T) {
+	store := NewMemDecisionStore()
+	store.Upsert("tenant-1", []models.Decision{
+		{Scope: models.ScopeIP, Value: "203.0.113.1", Confidence: 50, ExpiresAt: time.Now().Add(time.Hour)},
+	})
+	store.Upsert("tenant-1", []models.Decision{
+		{Scope: models.ScopeIP, Value: "203.0.113.1", Confidence: 90, ExpiresAt: time.Now().Add(time.Hour)},
+	})
+
+	got, ok := store.Match("tenant-1", models.ScopeIP, "203.0.113.1")
+	assert.True(t, ok)
+	assert.Equal(t, 90, got.Confidence)
+}
+
+func TestMemDecisionStore_MatchUnknownTenant(t *testing.T) {
+	store := NewMemDecisionStore()
+	_, ok := store.Match("no-such-tenant", models.ScopeIP, "203.0.113.1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,57 @@
+package intel
+
+import (
+	"context"
+	"time"
+
+	"insec/internal/models"
+)
+
+// Syncer periodically pushes a tenant's outstanding signals to the hub and
+// pulls the latest consolidated Decisions back into a DecisionStore. It's
+// optional - a server with no upstream hub configured never constructs one.
+type Syncer struct {
+	hub       *HubClient
+	decisions DecisionStore
+	tenantID  string
+	interval  time.Duration
+	pending   func() []models.Signal
+}
+
+// NewSyncer builds a Syncer. pending is called on every tick to collect the
+// signals derived since the last sync (typically from recently-resolved
+// high-risk alerts).
+func NewSyncer(hub *HubClient, decisions DecisionStore, tenantID string, interval time.Duration, pending func() []models.Signal) *Syncer {
+	return &Syncer{hub: hub, decisions: decisions, tenantID: tenantID, interval: interval, pending: pending}
+}
+
+// Run blocks, syncing every interval until ctx is canceled.
+func (s *Syncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.syncOnce()
+		}
+	}
+}
+
+// syncOnce runs one push+pull cycle. Errors are swallowed: a hub outage
+// must not block local ingestion/scoring, it just means this tenant misses
+// an update until the next tick.
+func (s *Syncer) syncOnce() {
+	if signals := s.pending(); len(signals) > 0 {
+		_ = s.hub.Push(signals)
+	}
+	decisions, err := s.hub.Pull()
+	if err != nil {
+		return
+	}
+	for i := range decisions {
+		decisions[i].TenantID = s.tenantID
+	}
+	s.decisions.Upsert(s.tenantID, decisions)
+}
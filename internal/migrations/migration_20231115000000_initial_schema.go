@@ -0,0 +1,169 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+
+	"insec/internal/models"
+)
+
+// The structs below are frozen snapshots of the tables server.AutoMigrate
+// used to create - field-for-field, as of the last commit before this
+// migrations package existed. Do not edit them to track later changes to
+// the live application models; add a new migration instead.
+
+type im20231115Event struct {
+	ID        uint      `gorm:"primaryKey"`
+	Timestamp time.Time `gorm:"index"`
+	TenantID  string    `gorm:"index"`
+	HostID    string    `gorm:"index"`
+	UserID       string `gorm:"column:user_id"`
+	UserEmail    string `gorm:"column:user_email"`
+	UserDept     string `gorm:"column:user_dept"`
+	OSFamily     string `gorm:"column:os_family"`
+	OSVersion    string `gorm:"column:os_version"`
+	OSArch       string `gorm:"column:os_arch"`
+	EventType     string `gorm:"column:event_type"`
+	EventID       string `gorm:"column:event_id"`
+	EventCategory string `gorm:"column:event_category"`
+	Labels    []string `gorm:"type:text[]"`
+	RiskHints []string `gorm:"type:text[]"`
+	AgentVer     string `gorm:"column:agent_ver"`
+	AgentMode    string `gorm:"column:agent_mode"`
+	SessionID string `gorm:"index"`
+	CgroupID  uint64 `gorm:"index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (im20231115Event) TableName() string { return "events" }
+
+type im20231115Alert struct {
+	ID          uint      `gorm:"primaryKey"`
+	CreatedAt   time.Time
+	Severity    string
+	Title       string
+	Description string
+	TenantID    string                 `gorm:"index"`
+	RuleID      string
+	RuleVersion string
+	UEBAScore   int
+	Entities    map[string]interface{} `gorm:"type:jsonb"`
+	Evidence    []string               `gorm:"type:text[]"`
+	Status      string                 `gorm:"default:'open'"`
+	Assignee    *string
+	ResolvedAt  *time.Time
+}
+
+func (im20231115Alert) TableName() string { return "alerts" }
+
+type im20231115Rule struct {
+	ID          string `gorm:"primaryKey"`
+	Name        string
+	Description string
+	Version     string
+	Enabled     bool                   `gorm:"default:true"`
+	Severity    string
+	Conditions  map[string]interface{} `gorm:"type:jsonb"`
+	Actions     []string               `gorm:"type:text[]"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (im20231115Rule) TableName() string { return "rules" }
+
+type im20231115Policy struct {
+	ID          string `gorm:"primaryKey"`
+	Name        string
+	Description string
+	TenantID    string                 `gorm:"index"`
+	Enabled     bool                   `gorm:"default:true"`
+	Config      map[string]interface{} `gorm:"type:jsonb"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (im20231115Policy) TableName() string { return "policies" }
+
+type im20231115NotificationDelivery struct {
+	ID            string `gorm:"primaryKey"`
+	Plugin        string `gorm:"index"`
+	AlertIDs      []string `gorm:"type:text[]"`
+	Payload       []byte   `gorm:"type:bytea"`
+	Attempt       int
+	Status        string `gorm:"index"`
+	LastError     string
+	NextAttemptAt time.Time `gorm:"index"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (im20231115NotificationDelivery) TableName() string { return "notification_deliveries" }
+
+type im20231115PendingAgent struct {
+	ID             string `gorm:"primaryKey"`
+	TenantID       string `gorm:"index"`
+	Hostname       string
+	OS             string
+	Version        string
+	Capabilities   []string `gorm:"type:text[]"`
+	SourceIP       string
+	Status         string `gorm:"index"`
+	Nonce          string `gorm:"uniqueIndex"`
+	NonceExpiresAt time.Time
+	ValidatedAt    *time.Time
+	ValidatedBy    string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (im20231115PendingAgent) TableName() string { return "pending_agents" }
+
+type im20231115Session struct {
+	ID        string `gorm:"primaryKey"`
+	TenantID  string `gorm:"index"`
+	HostID    string `gorm:"index"`
+	UserID    string
+	CgroupID  uint64 `gorm:"index"`
+	StartedAt time.Time
+	ClosedAt  *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (im20231115Session) TableName() string { return "sessions" }
+
+// migration20231115000000InitialSchema is the first migration: it creates
+// every table server.AutoMigrate used to create in one reviewable,
+// reversible step. models.Decision is imported directly rather than frozen
+// locally - it's a real importable package (unlike server's package-main
+// models), so this migration can just track it as-is going forward.
+var migration20231115000000InitialSchema = &gormigrate.Migration{
+	ID: "20231115000000_initial_schema",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(
+			&im20231115Event{},
+			&im20231115Alert{},
+			&im20231115Rule{},
+			&im20231115Policy{},
+			&models.Decision{},
+			&im20231115NotificationDelivery{},
+			&im20231115PendingAgent{},
+			&im20231115Session{},
+		)
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(
+			&im20231115Session{},
+			&im20231115PendingAgent{},
+			&im20231115NotificationDelivery{},
+			&models.Decision{},
+			&im20231115Policy{},
+			&im20231115Rule{},
+			&im20231115Alert{},
+			&im20231115Event{},
+		)
+	},
+}
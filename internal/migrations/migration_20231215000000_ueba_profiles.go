@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// im20231215UebaProfile is a frozen snapshot of internal/ueba's
+// ueba_profiles table shape, not an import of that package's own row type
+// - the same reason the initial schema migration freezes its own table
+// structs rather than importing server's live models.
+type im20231215UebaProfile struct {
+	TenantID     string `gorm:"column:tenant_id;primaryKey"`
+	SubjectKind  string `gorm:"column:subject_kind;primaryKey"`
+	SubjectID    string `gorm:"column:subject_id;primaryKey"`
+	PeerGroup    string `gorm:"column:peer_group"`
+	ProcessNames []byte `gorm:"column:process_names;type:jsonb"`
+	DstPorts     []byte `gorm:"column:dst_ports;type:jsonb"`
+	Domains      []byte `gorm:"column:domains;type:jsonb"`
+	FilePaths    []byte `gorm:"column:file_paths;type:jsonb"`
+	LoginHours   []byte `gorm:"column:login_hours;type:jsonb"`
+	RiskScore    float64
+	LastSeen     time.Time
+	UpdatedAt    time.Time
+}
+
+func (im20231215UebaProfile) TableName() string { return "ueba_profiles" }
+
+// migration20231215000000UebaProfiles creates the table internal/ueba
+// persists per-user, per-host, and per-dept behavioral baselines in.
+var migration20231215000000UebaProfiles = &gormigrate.Migration{
+	ID: "20231215000000_ueba_profiles",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&im20231215UebaProfile{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&im20231215UebaProfile{})
+	},
+}
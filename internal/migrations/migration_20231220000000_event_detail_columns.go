@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// im20231220EventColumns declares only the columns this migration adds to
+// the events table - AutoMigrate adds whatever columns a struct has that
+// the table is still missing, so this must not repeat im20231115Event's
+// columns, only the new ones. The live server.Event gained Process/Network/
+// File detail (and Agent.Hostname) after the initial schema migration was
+// frozen, without a migration ever following it to add the matching
+// columns; internal/eventstore's COPY FROM needs them to actually exist, so
+// this closes that gap instead of letting CopyInsertEvents be the first
+// thing to notice it at runtime.
+type im20231220EventColumns struct {
+	ProcName      *string `gorm:"column:proc_name"`
+	ProcPPID      *uint32 `gorm:"column:proc_ppid"`
+	ProcPID       *uint32 `gorm:"column:proc_pid"`
+	ProcHash      *string `gorm:"column:proc_hash"`
+	NetSrcIP      *string `gorm:"column:net_src_ip"`
+	NetDstIP      *string `gorm:"column:net_dst_ip"`
+	NetDstPort    *uint16 `gorm:"column:net_dst_port"`
+	NetProtocol   *string `gorm:"column:net_protocol"`
+	NetDomain     *string `gorm:"column:net_domain"`
+	FilePath      *string `gorm:"column:file_path"`
+	FileOperation *string `gorm:"column:file_operation"`
+	FileHash      *string `gorm:"column:file_hash"`
+	AgentHostname string  `gorm:"column:agent_hostname"`
+}
+
+func (im20231220EventColumns) TableName() string { return "events" }
+
+// eventDetailColumns is every column this migration adds, so Rollback can
+// drop exactly them without touching the columns im20231115Event created.
+var eventDetailColumns = []string{
+	"proc_name", "proc_ppid", "proc_pid", "proc_hash",
+	"net_src_ip", "net_dst_ip", "net_dst_port", "net_protocol", "net_domain",
+	"file_path", "file_operation", "file_hash",
+	"agent_hostname",
+}
+
+var migration20231220000000EventDetailColumns = &gormigrate.Migration{
+	ID: "20231220000000_event_detail_columns",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&im20231220EventColumns{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		for _, col := range eventDetailColumns {
+			if !m.HasColumn(&im20231220EventColumns{}, col) {
+				continue
+			}
+			if err := m.DropColumn(&im20231220EventColumns{}, col); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
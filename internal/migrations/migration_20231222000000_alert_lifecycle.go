@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// im20231222AlertTransition is a frozen snapshot of internal/alertlifecycle's
+// alert_transitions table: the append-only audit log of who moved an alert
+// from which status to which, and why.
+type im20231222AlertTransition struct {
+	ID         string    `gorm:"column:id;primaryKey"`
+	AlertID    string    `gorm:"column:alert_id;index"`
+	FromStatus string    `gorm:"column:from_status"`
+	ToStatus   string    `gorm:"column:to_status"`
+	Actor      string    `gorm:"column:actor"`
+	Note       string    `gorm:"column:note"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (im20231222AlertTransition) TableName() string { return "alert_transitions" }
+
+// im20231222AlertSLA is a frozen snapshot of internal/alertlifecycle's
+// alert_sla table: each tenant's per-severity time-to-resolution target.
+type im20231222AlertSLA struct {
+	TenantID      string    `gorm:"column:tenant_id;primaryKey"`
+	Severity      string    `gorm:"column:severity;primaryKey"`
+	TargetSeconds float64   `gorm:"column:target_seconds"`
+	UpdatedAt     time.Time `gorm:"column:updated_at"`
+}
+
+func (im20231222AlertSLA) TableName() string { return "alert_sla" }
+
+// migration20231222000000AlertLifecycle creates the two tables
+// internal/alertlifecycle's transition audit log and SLA monitor persist
+// to.
+var migration20231222000000AlertLifecycle = &gormigrate.Migration{
+	ID: "20231222000000_alert_lifecycle",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(
+			&im20231222AlertTransition{},
+			&im20231222AlertSLA{},
+		)
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(
+			&im20231222AlertSLA{},
+			&im20231222AlertTransition{},
+		)
+	},
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// im20240105User is a frozen snapshot of internal/auth's users table - see
+// the package doc comment above for why migrations keep their own copies
+// instead of importing internal/auth.User directly.
+type im20240105User struct {
+	ID               string `gorm:"column:id;primaryKey"`
+	Email            string `gorm:"column:email;uniqueIndex"`
+	Name             string
+	Password         string
+	Role             string
+	TenantID         string `gorm:"column:tenant_id;index"`
+	MFASecret        string `gorm:"column:mfa_secret"`
+	MFAEnabled       bool   `gorm:"column:mfa_enabled"`
+	MFARecoveryCodes []string `gorm:"column:mfa_recovery_codes;type:text[]"`
+	MFALastUsedStep  int64  `gorm:"column:mfa_last_used_step"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (im20240105User) TableName() string { return "users" }
+
+// migration20240105000000Users creates the users table internal/auth's
+// GormUserRepository persists to. It didn't exist until now because every
+// prior request exercised AuthService against an in-memory or test-double
+// UserRepository; server/main.go wiring a real *auth.AuthService is what
+// first requires a real table to back it.
+var migration20240105000000Users = &gormigrate.Migration{
+	ID: "20240105000000_users",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&im20240105User{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&im20240105User{})
+	},
+}
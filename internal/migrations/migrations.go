@@ -0,0 +1,47 @@
+// Package migrations replaces server.AutoMigrate's single blind
+// db.AutoMigrate(...) call with versioned, reversible schema migrations in
+// the gormigrate style: each migration is a dated, ID'd unit with its own
+// Migrate and Rollback step, and gormigrate records which IDs have been
+// applied in a schema_migrations table instead of re-diffing every model on
+// every boot.
+//
+// Migration files deliberately define their own frozen copies of the table
+// shapes they create or alter, rather than importing the live structs from
+// server/ or internal/models - a migration must keep doing exactly what it
+// always did even after those application models evolve, and server's
+// models live in package main besides, which nothing outside it can import.
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// All is every migration this package knows about, oldest first. Registering
+// a new one means appending to this slice, never editing an already-applied
+// entry in place.
+var All = []*gormigrate.Migration{
+	migration20231115000000InitialSchema,
+	migration20231201000000PartitionEvents,
+	migration20231215000000UebaProfiles,
+	migration20231220000000EventDetailColumns,
+	migration20231222000000AlertLifecycle,
+	migration20240105000000Users,
+}
+
+// options configures gormigrate's bookkeeping table: "schema_migrations"
+// rather than gormigrate's own default ("migrations"), so it reads clearly
+// next to this project's other *_migrations naming, and UseTransaction so a
+// migration that fails partway leaves the schema exactly as it was.
+var options = &gormigrate.Options{
+	TableName:      "schema_migrations",
+	IDColumnName:   "id",
+	IDColumnSize:   255,
+	UseTransaction: true,
+}
+
+// New builds a gormigrate runner over db with this package's full
+// registry, for insec-migrate's up/down/to commands to drive.
+func New(db *gorm.DB) *gormigrate.Gormigrate {
+	return gormigrate.New(db, options, All)
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAll_IDsAreUniqueAndOldestFirst(t *testing.T) {
+	seen := make(map[string]bool, len(All))
+	ids := make([]string, 0, len(All))
+	for _, m := range All {
+		assert.False(t, seen[m.ID], "migration ID %q is registered more than once", m.ID)
+		seen[m.ID] = true
+		ids = append(ids, m.ID)
+	}
+
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	assert.Equal(t, sorted, ids, "All must list migrations oldest-first, since that's the order they're applied in")
+}
+
+func TestAll_EveryMigrationHasMigrateAndRollback(t *testing.T) {
+	for _, m := range All {
+		assert.NotNil(t, m.Migrate, "migration %q must define an Up step", m.ID)
+		assert.NotNil(t, m.Rollback, "migration %q must define a Down step", m.ID)
+	}
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// StatusEntry reports whether one registered migration has been applied to
+// a given database.
+type StatusEntry struct {
+	ID      string
+	Applied bool
+}
+
+// Status reports every migration in All alongside whether it's been applied
+// to db, in registration order - so an operator can see what a pending Up
+// would do before running it. It tolerates schema_migrations not existing
+// yet (a brand new database where nothing has ever been applied).
+func Status(db *gorm.DB) ([]StatusEntry, error) {
+	applied := make(map[string]bool)
+	if db.Migrator().HasTable(options.TableName) {
+		var ids []string
+		if err := db.Table(options.TableName).Pluck(options.IDColumnName, &ids).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			applied[id] = true
+		}
+	}
+
+	entries := make([]StatusEntry, 0, len(All))
+	for _, m := range All {
+		entries = append(entries, StatusEntry{ID: m.ID, Applied: applied[m.ID]})
+	}
+	return entries, nil
+}
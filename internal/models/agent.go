@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// AgentRegistration is submitted by an agent bootstrapping itself against
+// the server.
+type AgentRegistration struct {
+	AgentID      string   `json:"agent_id"`
+	TenantID     string   `json:"tenant_id"`
+	Hostname     string   `json:"hostname"`
+	OS           string   `json:"os"`
+	Version      string   `json:"version"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// Well-known AgentRegistration.Capabilities values for the agent's optional
+// BPF-backed telemetry probes (internal/agent/bpf). An agent that can't load
+// BPF (non-Linux, missing BTF, insufficient privilege) simply omits these.
+const (
+	CapabilityBPFExec = "bpf_exec"
+	CapabilityBPFOpen = "bpf_open"
+	CapabilityBPFTCP  = "bpf_tcp"
+)
+
+// AgentRegistrationResponse is returned to a newly registered agent.
+type AgentRegistrationResponse struct {
+	AgentID string `json:"agent_id"`
+	Token   string `json:"token"`
+}
+
+// Agent enrollment states for the two-step registration flow: a freshly
+// registered agent starts Pending and must be Validated (by an operator or
+// an auto-approve policy) before it can exchange its nonce for credentials.
+const (
+	AgentStatusPending   = "pending"
+	AgentStatusValidated = "validated"
+)
+
+// AgentRegisterResponse is returned from POST /v1/agents/register. Unlike
+// AgentRegistrationResponse, it never carries a long-lived token: a fleet
+// of thousands of self-registering agents must not be handed credentials
+// before an operator (or an auto-approve policy) has validated them. Nonce
+// is only redeemable once Status is "validated".
+type AgentRegisterResponse struct {
+	AgentID        string    `json:"agent_id"`
+	Status         string    `json:"status"`
+	Nonce          string    `json:"nonce"`
+	NonceExpiresAt time.Time `json:"nonce_expires_at"`
+}
+
+// AgentTokenRequest exchanges a validated agent's enrollment nonce for a
+// one-time agentauth.EnrollmentToken via POST /v1/agents/token.
+type AgentTokenRequest struct {
+	AgentID string `json:"agent_id" binding:"required"`
+	Nonce   string `json:"nonce" binding:"required"`
+}
+
+// AgentHeartbeat is submitted periodically by a running agent.
+type AgentHeartbeat struct {
+	AgentID         string    `json:"agent_id"`
+	Timestamp       time.Time `json:"timestamp"`
+	Status          string    `json:"status"`
+	Version         string    `json:"version"`
+	UptimeSeconds   int64     `json:"uptime_seconds"`
+	MemoryUsageMB   float64   `json:"memory_usage_mb"`
+	CPUUsagePercent float64   `json:"cpu_usage_percent"`
+}
+
+// ProbeConfig is the server-pushed, per-tenant toggle for individual BPF
+// probes, honored by internal/agent/bpf.Manager so an operator can disable a
+// noisy or unsupported probe fleet-wide without an agent redeploy.
+type ProbeConfig struct {
+	TenantID   string          `json:"tenant_id"`
+	Enabled    map[string]bool `json:"enabled"` // keyed by Capability* constant
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
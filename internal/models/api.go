@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// The types below mirror the JSON wire contracts server/'s handlers
+// exchange over HTTP. server/'s own request/response structs (e.g.
+// loginRequest, tokenPairResponse) are unexported and live in package main,
+// which - being a program rather than a library - nothing outside server/
+// can import; these give tests/e2e, tests/security, and tests/performance
+// something importable to decode responses into.
+
+// LoginRequest is the POST /api/v1/auth/login payload.
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginResponse is a successful login's response body.
+type LoginResponse struct {
+	Token string `json:"token"`
+}
+
+// Alert is the public view of an alert returned by the /api/v1/alerts and
+// /v1/alerts endpoints.
+type Alert struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Severity    string    `json:"severity"`
+	Status      string    `json:"status"`
+	Resolution  string    `json:"resolution,omitempty"`
+	RiskScore   float64   `json:"risk_score,omitempty"`
+	TenantID    string    `json:"tenant_id,omitempty"`
+	AssignedTo  string    `json:"assigned_to,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+}
+
+// AlertUpdateRequest is the PUT /api/v1/alerts/:id payload for
+// acknowledging, assigning, or resolving an alert.
+type AlertUpdateRequest struct {
+	Status     string `json:"status,omitempty"`
+	Resolution string `json:"resolution,omitempty"`
+	AssignedTo string `json:"assigned_to,omitempty"`
+	Comments   string `json:"comments,omitempty"`
+}
+
+// Event is the public view of an ingested event.
+type Event struct {
+	EventType string                 `json:"event_type"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// EventRequest is the POST /api/v1/events payload.
+type EventRequest struct {
+	EventType   string                 `json:"event_type"`
+	Description string                 `json:"description,omitempty"`
+	Severity    string                 `json:"severity,omitempty"`
+	Source      string                 `json:"source,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+}
+
+// AuditLog is one entry of the hash-chained audit trail (internal/audit.
+// Entry), as returned to an API caller rather than as the sink's own
+// persisted shape.
+type AuditLog struct {
+	UserID    string    `json:"user_id"`
+	Operation string    `json:"operation"`
+	Resource  string    `json:"resource"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SystemMetrics is a point-in-time snapshot of server load, as reported by
+// a monitoring/metrics endpoint.
+type SystemMetrics struct {
+	AverageResponseTime float64 `json:"average_response_time_ms"`
+	Throughput          float64 `json:"throughput"`
+	ErrorRate           float64 `json:"error_rate"`
+	CPUUsage            float64 `json:"cpu_usage"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Signal is a single indicator of compromise a tenant has derived from a
+// resolved high-risk alert and, if it opts in, shares to the community hub
+// (internal/intel). It never carries the raw alert context that produced
+// it - just the indicator itself and enough metadata to score and expire it.
+type Signal struct {
+	ID             string    `json:"id" gorm:"primaryKey"`
+	IndicatorType  string    `json:"indicator_type"` // ip, domain, hash
+	Value          string    `json:"value" gorm:"index"`
+	Confidence     int       `json:"confidence"` // 0-100
+	SourceTenantID string    `json:"source_tenant_id" gorm:"index"`
+	FirstSeen      time.Time `json:"first_seen"`
+	LastSeen       time.Time `json:"last_seen"`
+	TTL            time.Duration `json:"ttl"`
+}
+
+// Well-known Signal.IndicatorType values.
+const (
+	IndicatorIP     = "ip"
+	IndicatorDomain = "domain"
+	IndicatorHash   = "hash"
+)
+
+// Decision is an enforceable verdict against some scope of activity - either
+// a local enforcement action (ban, throttle, isolate, revoke_token) raised
+// off an alert or rule, or a consolidated indicator a tenant pulled back
+// from the community hub for a Signal it opted in to receive. The two never
+// need to be distinguished by the risk-scoring pipeline or by an agent
+// enforcing locally; only Origin records which one a given Decision is, the
+// same way CrowdSec's own Decision model folds local and CAPI-sourced bans
+// into one shape.
+type Decision struct {
+	ID         string    `json:"id" gorm:"primaryKey"`
+	Type       string    `json:"type"` // ban, throttle, isolate, revoke_token
+	Scope      string    `json:"scope"`
+	Value      string    `json:"value" gorm:"index"`
+	Origin     string    `json:"origin"` // rule ID, alert ID, "manual", or a hub source
+	Reason     string    `json:"reason,omitempty"`
+	Confidence int       `json:"confidence"`
+	TenantID   string    `json:"tenant_id" gorm:"index"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Well-known Decision.Scope values.
+const (
+	ScopeIP      = "ip"
+	ScopeRange   = "range"
+	ScopeUser    = "user"
+	ScopeHost    = "host"
+	ScopeSession = "session"
+	ScopeDomain  = "domain"
+	ScopeHash    = "hash"
+)
+
+// Well-known Decision.Type values.
+const (
+	DecisionBan         = "ban"
+	DecisionThrottle    = "throttle"
+	DecisionIsolate     = "isolate"
+	DecisionRevokeToken = "revoke_token"
+)
+
+// Active reports whether d is still in force as of now.
+func (d Decision) Active(now time.Time) bool {
+	return now.Before(d.ExpiresAt)
+}
@@ -0,0 +1,312 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// severityLevels ranks Alert.Severity so a plugin's MinSeverity filter can
+// be compared instead of string-matched, the same pattern roleRank uses in
+// internal/auth/impersonate.go.
+var severityLevels = map[string]int{
+	"low":      0,
+	"medium":   1,
+	"high":     2,
+	"critical": 3,
+}
+
+// PluginConfig is the dispatcher-side filtering and batching policy for one
+// registered plugin - distinct from the plugin's own Configure(cfg []byte)
+// blob, which is opaque to the dispatcher.
+type PluginConfig struct {
+	// MinSeverity, if set, drops any alert ranked below it.
+	MinSeverity string
+	// TenantFilter, if non-empty, only delivers alerts for these tenants.
+	TenantFilter []string
+	// TagFilter, if non-empty, only delivers alerts carrying at least one
+	// of these tags.
+	TagFilter []string
+	// GroupWindow batches every alert that arrives within this window into
+	// one Notify call. Zero means dispatch immediately, one alert at a time.
+	GroupWindow time.Duration
+	// Template, if set, is executed per-alert via RenderBody before
+	// delivery; plugins that want raw Alert structs instead can leave it
+	// empty - the rendered body is informational to the plugin, not
+	// required to use it.
+	Template string
+}
+
+func matchesFilter(cfg PluginConfig, alert Alert) bool {
+	if cfg.MinSeverity != "" && severityLevels[alert.Severity] < severityLevels[cfg.MinSeverity] {
+		return false
+	}
+	if len(cfg.TenantFilter) > 0 && !contains(cfg.TenantFilter, alert.TenantID) {
+		return false
+	}
+	if len(cfg.TagFilter) > 0 {
+		matched := false
+		for _, tag := range alert.Tags {
+			if contains(cfg.TagFilter, tag) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(set []string, value string) bool {
+	for _, v := range set {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one attempted (or pending) batch delivery to a plugin,
+// persisted via DeliveryStore so a failed delivery can be retried with
+// backoff across process restarts.
+type Delivery struct {
+	ID            string
+	Plugin        string
+	AlertIDs      []string
+	Payload       []byte // json-encoded []Alert, so retry doesn't need the batch held in memory
+	Attempt       int
+	Status        string // pending, delivered, failed
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Delivery.Status values.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// DeliveryStore is the persistence boundary Dispatcher depends on, so it
+// can run against a real table (server/notification_store.go) or an
+// in-memory stand-in in tests.
+type DeliveryStore interface {
+	Save(d Delivery) error
+	Due(now time.Time) ([]Delivery, error)
+}
+
+type registeredPlugin struct {
+	notifier Notifier
+	cfg      PluginConfig
+
+	mu      sync.Mutex
+	pending []Alert
+	timer   *time.Timer
+}
+
+// Dispatcher routes Alerts to every registered plugin whose PluginConfig
+// matches, grouping them into batches per plugin and recording every
+// delivery attempt to store (which may be nil, in which case deliveries
+// simply aren't retried on failure - matching this repo's convention of
+// nil-safe optional dependencies, e.g. EventHandler.decisions).
+type Dispatcher struct {
+	mu      sync.RWMutex
+	plugins map[string]*registeredPlugin
+	store   DeliveryStore
+	logger  *logrus.Logger
+}
+
+// NewDispatcher builds a Dispatcher. store may be nil.
+func NewDispatcher(store DeliveryStore, logger *logrus.Logger) *Dispatcher {
+	return &Dispatcher{
+		plugins: make(map[string]*registeredPlugin),
+		store:   store,
+		logger:  logger,
+	}
+}
+
+// Register adds or replaces the plugin named name.
+func (d *Dispatcher) Register(name string, notifier Notifier, cfg PluginConfig) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.plugins[name] = &registeredPlugin{notifier: notifier, cfg: cfg}
+}
+
+// Dispatch routes alert to every registered plugin it passes the filter
+// for, batching it into that plugin's grouping window.
+func (d *Dispatcher) Dispatch(ctx context.Context, alert Alert) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for name, rp := range d.plugins {
+		if !matchesFilter(rp.cfg, alert) {
+			continue
+		}
+		d.enqueue(ctx, name, rp, alert)
+	}
+}
+
+// DispatchTo routes alert only to the named plugin, bypassing its filter -
+// used by the /v1/notifications/test endpoint to validate a specific
+// plugin's wiring with a synthetic alert.
+func (d *Dispatcher) DispatchTo(ctx context.Context, name string, alert Alert) error {
+	d.mu.RLock()
+	rp, ok := d.plugins[name]
+	d.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("notifications: no plugin registered as %q", name)
+	}
+	return d.flush(ctx, name, rp, []Alert{alert})
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, name string, rp *registeredPlugin, alert Alert) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	rp.pending = append(rp.pending, alert)
+	if rp.cfg.GroupWindow <= 0 {
+		batch := rp.pending
+		rp.pending = nil
+		go d.flushLogged(ctx, name, rp, batch)
+		return
+	}
+	if rp.timer == nil {
+		rp.timer = time.AfterFunc(rp.cfg.GroupWindow, func() {
+			rp.mu.Lock()
+			batch := rp.pending
+			rp.pending = nil
+			rp.timer = nil
+			rp.mu.Unlock()
+			d.flushLogged(ctx, name, rp, batch)
+		})
+	}
+}
+
+func (d *Dispatcher) flushLogged(ctx context.Context, name string, rp *registeredPlugin, batch []Alert) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := d.flush(ctx, name, rp, batch); err != nil && d.logger != nil {
+		d.logger.WithError(err).WithField("plugin", name).Error("Failed to deliver alert batch")
+	}
+}
+
+func (d *Dispatcher) flush(ctx context.Context, name string, rp *registeredPlugin, batch []Alert) error {
+	if rp.cfg.Template != "" {
+		for i, alert := range batch {
+			rendered, err := RenderBody(rp.cfg.Template, alert)
+			if err != nil {
+				if d.logger != nil {
+					d.logger.WithError(err).WithField("plugin", name).Warn("Failed to render notification template")
+				}
+				continue
+			}
+			batch[i].RenderedBody = rendered
+		}
+	}
+
+	notifyErr := rp.notifier.Notify(ctx, batch)
+	d.recordDelivery(name, batch, notifyErr)
+	return notifyErr
+}
+
+func (d *Dispatcher) recordDelivery(name string, batch []Alert, notifyErr error) {
+	if d.store == nil {
+		return
+	}
+	ids := make([]string, len(batch))
+	for i, a := range batch {
+		ids[i] = a.ID
+	}
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		payload = nil
+	}
+
+	now := time.Now()
+	delivery := Delivery{
+		Plugin:    name,
+		AlertIDs:  ids,
+		Payload:   payload,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if notifyErr != nil {
+		delivery.Status = StatusFailed
+		delivery.LastError = notifyErr.Error()
+		delivery.NextAttemptAt = now.Add(NextBackoff(delivery.Attempt))
+	} else {
+		delivery.Status = StatusDelivered
+	}
+
+	if err := d.store.Save(delivery); err != nil && d.logger != nil {
+		d.logger.WithError(err).Error("Failed to persist notification delivery")
+	}
+}
+
+// RetryDueDeliveries re-attempts every failed delivery whose NextAttemptAt
+// has elapsed, redelivering from its persisted Payload so the original
+// Alert batch doesn't need to still be in memory. Meant to be run
+// periodically (see RunRetryLoop).
+func (d *Dispatcher) RetryDueDeliveries(ctx context.Context) {
+	if d.store == nil {
+		return
+	}
+	due, err := d.store.Due(time.Now())
+	if err != nil {
+		if d.logger != nil {
+			d.logger.WithError(err).Error("Failed to query due notification deliveries")
+		}
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, delivery := range due {
+		rp, ok := d.plugins[delivery.Plugin]
+		if !ok {
+			continue
+		}
+		var batch []Alert
+		if err := json.Unmarshal(delivery.Payload, &batch); err != nil {
+			continue
+		}
+		delivery.Attempt++
+		notifyErr := rp.notifier.Notify(ctx, batch)
+		if notifyErr != nil {
+			delivery.Status = StatusFailed
+			delivery.LastError = notifyErr.Error()
+			delivery.NextAttemptAt = time.Now().Add(NextBackoff(delivery.Attempt))
+		} else {
+			delivery.Status = StatusDelivered
+		}
+		delivery.UpdatedAt = time.Now()
+		if err := d.store.Save(delivery); err != nil && d.logger != nil {
+			d.logger.WithError(err).Error("Failed to persist retried notification delivery")
+		}
+	}
+}
+
+// RunRetryLoop blocks retrying due deliveries every interval until stop is
+// closed, meant to be run in its own goroutine from main - the notification
+// equivalent of RunDecisionReaper.
+func RunRetryLoop(ctx context.Context, d *Dispatcher, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			d.RetryDueDeliveries(ctx)
+		}
+	}
+}
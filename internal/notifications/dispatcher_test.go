@@ -0,0 +1,154 @@
+package notifications
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotifier records every batch it's asked to deliver, optionally failing
+// every attempt, so Dispatcher can be exercised with no real delivery
+// channel (Slack/email/etc).
+type fakeNotifier struct {
+	mu      sync.Mutex
+	batches [][]Alert
+	fail    bool
+}
+
+func (f *fakeNotifier) Configure(cfg []byte) error { return nil }
+
+func (f *fakeNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, alerts)
+	if f.fail {
+		return assert.AnError
+	}
+	return nil
+}
+
+func (f *fakeNotifier) received() [][]Alert {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]Alert(nil), f.batches...)
+}
+
+// memDeliveryStore is an in-memory DeliveryStore, standing in for the
+// gorm-backed store production uses.
+type memDeliveryStore struct {
+	mu         sync.Mutex
+	deliveries []Delivery
+}
+
+func (m *memDeliveryStore) Save(d Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, d)
+	return nil
+}
+
+func (m *memDeliveryStore) Due(now time.Time) ([]Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []Delivery
+	for _, d := range m.deliveries {
+		if d.Status == StatusFailed && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func TestMatchesFilter_MinSeverity(t *testing.T) {
+	cfg := PluginConfig{MinSeverity: "high"}
+	assert.True(t, matchesFilter(cfg, Alert{Severity: "critical"}))
+	assert.True(t, matchesFilter(cfg, Alert{Severity: "high"}))
+	assert.False(t, matchesFilter(cfg, Alert{Severity: "medium"}))
+}
+
+func TestMatchesFilter_TenantAndTagFilters(t *testing.T) {
+	cfg := PluginConfig{TenantFilter: []string{"tenant-1"}, TagFilter: []string{"malware"}}
+	assert.True(t, matchesFilter(cfg, Alert{TenantID: "tenant-1", Tags: []string{"malware", "other"}}))
+	assert.False(t, matchesFilter(cfg, Alert{TenantID: "tenant-2", Tags: []string{"malware"}}))
+	assert.False(t, matchesFilter(cfg, Alert{TenantID: "tenant-1", Tags: []string{"phishing"}}))
+}
+
+func TestDispatcher_DispatchSkipsFilteredPlugins(t *testing.T) {
+	d := NewDispatcher(nil, nil)
+	matching := &fakeNotifier{}
+	filtered := &fakeNotifier{}
+	d.Register("matching", matching, PluginConfig{MinSeverity: "low"})
+	d.Register("filtered", filtered, PluginConfig{MinSeverity: "critical"})
+
+	d.Dispatch(context.Background(), Alert{ID: "a1", Severity: "medium"})
+
+	require.Eventually(t, func() bool { return len(matching.received()) == 1 }, time.Second, time.Millisecond)
+	assert.Empty(t, filtered.received(), "a plugin whose MinSeverity isn't met must never be notified")
+}
+
+func TestDispatcher_DispatchToBypassesFilterAndRendersTemplate(t *testing.T) {
+	d := NewDispatcher(nil, nil)
+	notifier := &fakeNotifier{}
+	d.Register("webhook", notifier, PluginConfig{MinSeverity: "critical", Template: "{{.Severity}}: {{.Title}}"})
+
+	err := d.DispatchTo(context.Background(), "webhook", Alert{ID: "a1", Severity: "low", Title: "test alert"})
+	require.NoError(t, err)
+
+	batches := notifier.received()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, "low: test alert", batches[0][0].RenderedBody)
+}
+
+func TestDispatcher_DispatchToUnknownPluginErrors(t *testing.T) {
+	d := NewDispatcher(nil, nil)
+	err := d.DispatchTo(context.Background(), "missing", Alert{})
+	assert.Error(t, err)
+}
+
+func TestDispatcher_RecordsDeliveryOutcome(t *testing.T) {
+	store := &memDeliveryStore{}
+	d := NewDispatcher(store, nil)
+	notifier := &fakeNotifier{fail: true}
+	d.Register("flaky", notifier, PluginConfig{})
+
+	err := d.DispatchTo(context.Background(), "flaky", Alert{ID: "a1"})
+	assert.Error(t, err)
+
+	store.mu.Lock()
+	require.Len(t, store.deliveries, 1)
+	delivery := store.deliveries[0]
+	store.mu.Unlock()
+	assert.Equal(t, StatusFailed, delivery.Status)
+	assert.NotEmpty(t, delivery.LastError)
+}
+
+func TestDispatcher_RetryDueDeliveriesRedeliversFromPayload(t *testing.T) {
+	store := &memDeliveryStore{}
+	d := NewDispatcher(store, nil)
+	notifier := &fakeNotifier{}
+	d.Register("webhook", notifier, PluginConfig{})
+
+	store.deliveries = append(store.deliveries, Delivery{
+		Plugin:        "webhook",
+		Payload:       []byte(`[{"ID":"a1","Title":"replayed"}]`),
+		Status:        StatusFailed,
+		NextAttemptAt: time.Now().Add(-time.Minute),
+	})
+
+	d.RetryDueDeliveries(context.Background())
+
+	batches := notifier.received()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, "replayed", batches[0][0].Title)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	require.Len(t, store.deliveries, 2, "Save persists the retried delivery as a new record rather than updating in place")
+	assert.Equal(t, StatusDelivered, store.deliveries[1].Status)
+}
@@ -0,0 +1,39 @@
+// Package notifications routes generated Alerts to pluggable output
+// destinations (Slack, email, Splunk HEC, PagerDuty, webhooks, ...) without
+// the server needing to be recompiled for each one. Plugins are separate
+// executables loaded via hashicorp/go-plugin (see plugin.go); Dispatcher
+// (dispatcher.go) owns filtering, grouping, templating, and delivery
+// bookkeeping in front of them.
+package notifications
+
+import (
+	"context"
+	"time"
+)
+
+// Alert is the notification-facing view of an alert: just what a plugin
+// needs to decide whether and how to deliver it, decoupled from the
+// server's gorm-backed Alert record the same way internal/intel.Decision
+// is decoupled from it.
+type Alert struct {
+	ID          string
+	Title       string
+	Description string
+	Severity    string
+	TenantID    string
+	Tags        []string
+	CreatedAt   time.Time
+	// RenderedBody is PluginConfig.Template executed against this Alert by
+	// Dispatcher, if the destination plugin was configured with one. Empty
+	// otherwise - plugins that only care about the raw fields can ignore it.
+	RenderedBody string
+}
+
+// Notifier is implemented by every notification plugin, in-process or
+// loaded over gRPC. Configure is called once after load with the plugin's
+// own config blob (opaque to the dispatcher); Notify delivers a batch -
+// a single alert is just a batch of one.
+type Notifier interface {
+	Configure(cfg []byte) error
+	Notify(ctx context.Context, alerts []Alert) error
+}
@@ -0,0 +1,181 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"insec/internal/notifications/proto"
+)
+
+// Handshake is shared by the server (host) and every plugin binary; the
+// magic cookie just guards against accidentally launching a process that
+// isn't an insec notification plugin as one.
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "INSEC_NOTIFICATION_PLUGIN",
+	MagicCookieValue: "a52e5c7e-insec-notifications",
+}
+
+// pluginMap is the set of plugin kinds this host knows how to dispense;
+// notification plugins only ever expose one, named "notifier".
+var pluginMap = map[string]goplugin.Plugin{
+	"notifier": &GRPCNotifierPlugin{},
+}
+
+// GRPCNotifierPlugin adapts a notifications.Notifier to go-plugin's gRPC
+// transport. Impl is only set on the plugin-binary side; the host side
+// only ever calls GRPCClient. It embeds NetRPCUnsupportedPlugin (not the
+// goplugin.GRPCPlugin interface, which has no Server/Client methods of its
+// own to satisfy goplugin.Plugin) since this plugin only ever speaks gRPC.
+type GRPCNotifierPlugin struct {
+	goplugin.NetRPCUnsupportedPlugin
+	Impl Notifier
+}
+
+// GRPCServer implements plugin.GRPCPlugin for the plugin-binary side.
+func (p *GRPCNotifierPlugin) GRPCServer(broker *goplugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterNotifierServer(s, &grpcServer{impl: p.Impl})
+	return nil
+}
+
+// GRPCClient implements plugin.GRPCPlugin for the host side, returning a
+// Notifier backed by the gRPC connection to the plugin process.
+func (p *GRPCNotifierPlugin) GRPCClient(ctx context.Context, broker *goplugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	return &grpcClient{client: proto.NewNotifierClient(conn)}, nil
+}
+
+// grpcClient is the host-side Notifier backed by a loaded plugin process.
+type grpcClient struct {
+	client proto.NotifierClient
+}
+
+func (c *grpcClient) Configure(cfg []byte) error {
+	_, err := c.client.Configure(context.Background(), &proto.ConfigureRequest{Config: cfg})
+	return err
+}
+
+func (c *grpcClient) Notify(ctx context.Context, alerts []Alert) error {
+	req := &proto.NotifyRequest{Alerts: make([]*proto.Alert, len(alerts))}
+	for i, a := range alerts {
+		req.Alerts[i] = &proto.Alert{
+			Id:            a.ID,
+			Title:         a.Title,
+			Description:   a.Description,
+			Severity:      a.Severity,
+			TenantId:      a.TenantID,
+			Tags:          a.Tags,
+			CreatedAtUnix: a.CreatedAt.Unix(),
+			RenderedBody:  a.RenderedBody,
+		}
+	}
+	_, err := c.client.Notify(ctx, req)
+	return err
+}
+
+// grpcServer adapts a Notifier implementation to proto.NotifierServer; only
+// used inside a plugin binary, never by the host.
+type grpcServer struct {
+	proto.UnimplementedNotifierServer
+	impl Notifier
+}
+
+func (s *grpcServer) Configure(ctx context.Context, req *proto.ConfigureRequest) (*proto.ConfigureResponse, error) {
+	if err := s.impl.Configure(req.Config); err != nil {
+		return nil, err
+	}
+	return &proto.ConfigureResponse{}, nil
+}
+
+func (s *grpcServer) Notify(ctx context.Context, req *proto.NotifyRequest) (*proto.NotifyResponse, error) {
+	alerts := make([]Alert, len(req.Alerts))
+	for i, a := range req.Alerts {
+		alerts[i] = Alert{
+			ID:           a.Id,
+			Title:        a.Title,
+			Description:  a.Description,
+			Severity:     a.Severity,
+			TenantID:     a.TenantId,
+			RenderedBody: a.RenderedBody,
+		}
+	}
+	if err := s.impl.Notify(ctx, alerts); err != nil {
+		return nil, err
+	}
+	return &proto.NotifyResponse{}, nil
+}
+
+// LoadedPlugin is a plugin process the host has launched and dispensed a
+// Notifier from.
+type LoadedPlugin struct {
+	Name     string
+	Notifier Notifier
+	client   *goplugin.Client
+}
+
+// Kill terminates the plugin subprocess.
+func (lp *LoadedPlugin) Kill() {
+	lp.client.Kill()
+}
+
+// LoadPlugins launches every executable file directly under dir as a
+// notification plugin and dispenses its Notifier over gRPC. A plugin that
+// fails to launch, handshake or dispense a Notifier is killed and skipped
+// rather than aborting the whole load - one misbehaving plugin shouldn't
+// take every other notification channel down with it. Skipped plugins are
+// not logged or reported; the returned error only covers reading dir.
+func LoadPlugins(dir string) ([]*LoadedPlugin, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("notifications: read plugin dir: %w", err)
+	}
+
+	var loaded []*LoadedPlugin
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Mode()&0o111 == 0 {
+			continue // not executable
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		client := goplugin.NewClient(&goplugin.ClientConfig{
+			HandshakeConfig: Handshake,
+			Plugins:         pluginMap,
+			Cmd:             exec.Command(path),
+			AllowedProtocols: []goplugin.Protocol{
+				goplugin.ProtocolGRPC,
+			},
+		})
+
+		rpcClient, err := client.Client()
+		if err != nil {
+			client.Kill()
+			continue
+		}
+		raw, err := rpcClient.Dispense("notifier")
+		if err != nil {
+			client.Kill()
+			continue
+		}
+		notifier, ok := raw.(Notifier)
+		if !ok {
+			client.Kill()
+			continue
+		}
+
+		loaded = append(loaded, &LoadedPlugin{
+			Name:     entry.Name(),
+			Notifier: notifier,
+			client:   client,
+		})
+	}
+	return loaded, nil
+}
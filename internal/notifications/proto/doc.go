@@ -0,0 +1,14 @@
+// Package proto holds the generated gRPC client/server for
+// notifications.proto (NotifierClient, NotifierServer, NewNotifierClient,
+// RegisterNotifierServer, and the Alert/Configure*/Notify* message types).
+//
+// Generate it with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	       --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//	       internal/notifications/proto/notifications.proto
+//
+// The generated notifications.pb.go / notifications_grpc.pb.go are not
+// hand-written and are not checked in by this change; plugin.go is written
+// against the types they define.
+package proto
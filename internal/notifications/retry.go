@@ -0,0 +1,18 @@
+package notifications
+
+import "time"
+
+// maxBackoff ceilings NextBackoff so a persistently failing plugin is
+// retried at most this infrequently rather than effectively giving up.
+const maxBackoff = 30 * time.Minute
+
+// NextBackoff returns how long to wait before retrying a delivery that has
+// already failed attempt times (0 on the first failure), doubling from one
+// second and capped at maxBackoff.
+func NextBackoff(attempt int) time.Duration {
+	backoff := time.Second << uint(attempt)
+	if backoff <= 0 || backoff > maxBackoff { // overflow or past the ceiling
+		return maxBackoff
+	}
+	return backoff
+}
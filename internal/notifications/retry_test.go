@@ -0,0 +1,19 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextBackoff_DoublesUntilCeiling(t *testing.T) {
+	assert.Equal(t, time.Second, NextBackoff(0))
+	assert.Equal(t, 2*time.Second, NextBackoff(1))
+	assert.Equal(t, 4*time.Second, NextBackoff(2))
+	assert.Equal(t, maxBackoff, NextBackoff(30), "a persistently failing plugin must be capped at maxBackoff")
+}
+
+func TestNextBackoff_DoesNotOverflowNegative(t *testing.T) {
+	assert.Equal(t, maxBackoff, NextBackoff(100), "a large attempt count must clamp to maxBackoff instead of overflowing into a negative duration")
+}
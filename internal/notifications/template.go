@@ -0,0 +1,22 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// RenderBody executes tmplText (a Go text/template body referencing Alert's
+// exported fields, e.g. "{{.Severity}}: {{.Title}}") against alert and
+// returns the rendered string.
+func RenderBody(tmplText string, alert Alert) (string, error) {
+	tmpl, err := template.New("notification").Parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("notifications: parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, alert); err != nil {
+		return "", fmt.Errorf("notifications: render template: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,26 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderBody_ExecutesAgainstAlertFields(t *testing.T) {
+	alert := Alert{Severity: "high", Title: "Brute force detected"}
+
+	body, err := RenderBody("{{.Severity}}: {{.Title}}", alert)
+	require.NoError(t, err)
+	assert.Equal(t, "high: Brute force detected", body)
+}
+
+func TestRenderBody_RejectsInvalidTemplate(t *testing.T) {
+	_, err := RenderBody("{{.Severity", Alert{})
+	assert.Error(t, err)
+}
+
+func TestRenderBody_RejectsUnknownField(t *testing.T) {
+	_, err := RenderBody("{{.NotAField}}", Alert{})
+	assert.Error(t, err)
+}
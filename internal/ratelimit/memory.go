@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryLimiter is a single-process token-bucket Limiter, used as the dev
+// fallback when no Redis address is configured. It implements the exact
+// refill/decrement algorithm RedisLimiter runs atomically in Lua, so
+// behavior is identical between the two - just not shared across replicas.
+type MemoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucketState
+}
+
+type memoryBucketState struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewMemoryLimiter returns a ready-to-use MemoryLimiter.
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{buckets: make(map[string]*memoryBucketState)}
+}
+
+// Allow implements Limiter.
+func (m *MemoryLimiter) Allow(ctx context.Context, key string, bucket Bucket, cost float64) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	state, ok := m.buckets[key]
+	if !ok {
+		state = &memoryBucketState{tokens: bucket.Capacity, lastRefill: now}
+		m.buckets[key] = state
+	}
+
+	elapsed := now.Sub(state.lastRefill).Seconds()
+	state.tokens = minFloat(bucket.Capacity, state.tokens+elapsed*bucket.RefillPerSec)
+	state.lastRefill = now
+
+	resetAt := now.Add(time.Duration(float64(time.Second) * (bucket.Capacity - state.tokens) / bucket.RefillPerSec))
+
+	if state.tokens < cost {
+		deficit := cost - state.tokens
+		retryAfter := time.Duration(float64(time.Second) * deficit / bucket.RefillPerSec)
+		return Result{Allowed: false, Remaining: state.tokens, ResetAt: resetAt, RetryAfter: retryAfter}, nil
+	}
+
+	state.tokens -= cost
+	resetAt = now.Add(time.Duration(float64(time.Second) * (bucket.Capacity - state.tokens) / bucket.RefillPerSec))
+	return Result{Allowed: true, Remaining: state.tokens, ResetAt: resetAt}, nil
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
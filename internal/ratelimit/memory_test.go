@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter_AllowsUpToCapacityThenBlocks(t *testing.T) {
+	m := NewMemoryLimiter()
+	bucket := Bucket{Capacity: 3, RefillPerSec: 1}
+
+	for i := 0; i < 3; i++ {
+		result, err := m.Allow(context.Background(), "k", bucket, 1)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed, "request %d should be allowed within capacity", i)
+	}
+
+	result, err := m.Allow(context.Background(), "k", bucket, 1)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed, "request beyond capacity should be denied")
+	assert.Greater(t, result.RetryAfter, time.Duration(0))
+}
+
+func TestMemoryLimiter_RefillsOverTime(t *testing.T) {
+	m := NewMemoryLimiter()
+	bucket := Bucket{Capacity: 1, RefillPerSec: 1}
+
+	result, err := m.Allow(context.Background(), "k", bucket, 1)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	result, err = m.Allow(context.Background(), "k", bucket, 1)
+	require.NoError(t, err)
+	require.False(t, result.Allowed, "bucket should be empty immediately after being drained")
+
+	// Manually age the bucket's last refill instead of sleeping, so the
+	// test is fast and deterministic.
+	m.mu.Lock()
+	m.buckets["k"].lastRefill = time.Now().Add(-2 * time.Second)
+	m.mu.Unlock()
+
+	result, err = m.Allow(context.Background(), "k", bucket, 1)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "bucket should have refilled after 2s at 1 token/sec")
+}
+
+func TestMemoryLimiter_KeysAreIndependent(t *testing.T) {
+	m := NewMemoryLimiter()
+	bucket := Bucket{Capacity: 1, RefillPerSec: 1}
+
+	result, err := m.Allow(context.Background(), "a", bucket, 1)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = m.Allow(context.Background(), "b", bucket, 1)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "a separate key should have its own untouched bucket")
+}
+
+func TestKey_ScopesByTenantAndIdentity(t *testing.T) {
+	assert.Equal(t, "ratelimit:auth_endpoint:tenant-1:1.2.3.4", Key(AuthEndpoint, "tenant-1", "1.2.3.4"))
+	assert.NotEqual(t, Key(AlertMutation, "tenant-1", "user-1"), Key(AlertMutation, "tenant-2", "user-1"))
+}
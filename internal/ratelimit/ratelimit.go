@@ -0,0 +1,31 @@
+// Package ratelimit implements per-identity token-bucket rate limiting,
+// shared across server instances via Redis (redis.go) with an in-memory
+// fallback for single-node dev (memory.go). See routeclass.go for the
+// per-route-class bucket configuration RateLimitMiddleware uses.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Bucket describes a token bucket's shape: it holds at most Capacity
+// tokens and refills at RefillPerSec tokens/second.
+type Bucket struct {
+	Capacity     float64
+	RefillPerSec float64
+}
+
+// Result is the outcome of one Allow call.
+type Result struct {
+	Allowed    bool
+	Remaining  float64
+	ResetAt    time.Time
+	RetryAfter time.Duration
+}
+
+// Limiter charges cost tokens against key's bucket, refilling it for
+// elapsed time first. Implementations must be safe for concurrent use.
+type Limiter interface {
+	Allow(ctx context.Context, key string, bucket Bucket, cost float64) (Result, error)
+}
@@ -0,0 +1,34 @@
+package ratelimit
+
+import "fmt"
+
+// RouteClass names a family of endpoints that share a rate-limit bucket
+// shape and identity dimension.
+type RouteClass string
+
+const (
+	// TelemetryIngest buckets per-agent, allowing the bursty event volume a
+	// single agent can legitimately produce.
+	TelemetryIngest RouteClass = "telemetry_ingest"
+	// AuthEndpoint buckets per source IP, since the identity a login
+	// attempt claims can't be trusted until it succeeds.
+	AuthEndpoint RouteClass = "auth_endpoint"
+	// AlertMutation buckets per authenticated user.
+	AlertMutation RouteClass = "alert_mutation"
+)
+
+// Buckets holds the default bucket shape for each RouteClass, expressed as
+// the request budget the request calls out: capacity == the per-window
+// budget, refilling continuously over that same window.
+var Buckets = map[RouteClass]Bucket{
+	TelemetryIngest: {Capacity: 10000, RefillPerSec: 10000.0 / 60},
+	AuthEndpoint:    {Capacity: 10, RefillPerSec: 10.0 / 60},
+	AlertMutation:   {Capacity: 60, RefillPerSec: 60.0 / 60},
+}
+
+// Key builds the Redis/in-memory key a RouteClass bucket is stored under,
+// scoped by tenant so one tenant's abusive traffic can never drain another
+// tenant's budget.
+func Key(class RouteClass, tenantID, identity string) string {
+	return fmt.Sprintf("ratelimit:%s:%s:%s", class, tenantID, identity)
+}
@@ -0,0 +1,44 @@
+package ruleengine
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheKey struct {
+	id      string
+	version string
+}
+
+type cacheEntry struct {
+	updatedAt time.Time
+	compiled  CompiledRule
+}
+
+// compileCache memoizes a Rule's CompiledRule by ID+Version, recompiling
+// whenever UpdatedAt changes even if ID and Version didn't - shared by
+// CELEngine and SigmaEngine so neither reimplements the same memoization.
+type compileCache struct {
+	mu      sync.RWMutex
+	entries map[cacheKey]cacheEntry
+}
+
+func newCompileCache() *compileCache {
+	return &compileCache{entries: make(map[cacheKey]cacheEntry)}
+}
+
+func (c *compileCache) get(rule Rule) (CompiledRule, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[cacheKey{id: rule.ID, version: rule.Version}]
+	if !ok || !entry.updatedAt.Equal(rule.UpdatedAt) {
+		return nil, false
+	}
+	return entry.compiled, true
+}
+
+func (c *compileCache) put(rule Rule, compiled CompiledRule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[cacheKey{id: rule.ID, version: rule.Version}] = cacheEntry{updatedAt: rule.UpdatedAt, compiled: compiled}
+}
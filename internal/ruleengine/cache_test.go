@@ -0,0 +1,47 @@
+package ruleengine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCompiledRule struct{}
+
+func (fakeCompiledRule) Evaluate(event map[string]interface{}) (bool, []string, error) {
+	return false, nil, nil
+}
+
+func TestCompileCache_MissesUntilPut(t *testing.T) {
+	c := newCompileCache()
+	rule := Rule{ID: "r1", Version: "v1", UpdatedAt: time.Unix(0, 0)}
+
+	_, ok := c.get(rule)
+	assert.False(t, ok)
+
+	c.put(rule, fakeCompiledRule{})
+	compiled, ok := c.get(rule)
+	assert.True(t, ok)
+	assert.Equal(t, fakeCompiledRule{}, compiled)
+}
+
+func TestCompileCache_InvalidatesOnUpdatedAtChange(t *testing.T) {
+	c := newCompileCache()
+	rule := Rule{ID: "r1", Version: "v1", UpdatedAt: time.Unix(0, 0)}
+	c.put(rule, fakeCompiledRule{})
+
+	rule.UpdatedAt = time.Unix(1, 0)
+	_, ok := c.get(rule)
+	assert.False(t, ok, "a rule whose UpdatedAt moved must recompile even with the same ID and Version")
+}
+
+func TestCompileCache_KeysByIDAndVersion(t *testing.T) {
+	c := newCompileCache()
+	rule1 := Rule{ID: "r1", Version: "v1"}
+	rule2 := Rule{ID: "r1", Version: "v2"}
+	c.put(rule1, fakeCompiledRule{})
+
+	_, ok := c.get(rule2)
+	assert.False(t, ok, "a different Version must be treated as a different cache entry")
+}
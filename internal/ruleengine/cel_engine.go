@@ -0,0 +1,144 @@
+package ruleengine
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+// celEnv declares the single "event" variable every CEL expression runs
+// against - the same json-flattened map shape internal/scenario's expr
+// filters use (see server.eventToScenarioEnv), so a rule author reuses one
+// mental model across both engines, e.g. event.proc.name == "ncat" &&
+// event.net.dst_port == 4444.
+var celEnv, celEnvErr = cel.NewEnv(cel.Variable("event", cel.DynType))
+
+// celFieldReference matches a dotted "event.foo.bar" reference inside a
+// CEL expression's source text, used to approximate Alert.Evidence without
+// walking cel-go's AST for attribute trails.
+var celFieldReference = regexp.MustCompile(`event(?:\.[A-Za-z_][A-Za-z0-9_]*)+`)
+
+// CELEngine evaluates rules whose Conditions carry a "cel" key: a
+// google/cel-go boolean expression.
+type CELEngine struct {
+	cache *compileCache
+
+	mu    sync.RWMutex
+	rules []*celCompiledRule
+}
+
+// NewCELEngine returns a CELEngine with no rules loaded yet - call Load
+// before Evaluate.
+func NewCELEngine() *CELEngine {
+	return &CELEngine{cache: newCompileCache()}
+}
+
+type celCompiledRule struct {
+	rule     Rule
+	program  cel.Program
+	evidence []string
+}
+
+// Compile implements RuleEngine.
+func (e *CELEngine) Compile(rule Rule) (CompiledRule, error) {
+	if compiled, ok := e.cache.get(rule); ok {
+		return compiled, nil
+	}
+	if celEnvErr != nil {
+		return nil, fmt.Errorf("ruleengine: build cel environment: %w", celEnvErr)
+	}
+
+	source, ok := rule.Conditions["cel"].(string)
+	if !ok || source == "" {
+		return nil, fmt.Errorf("rule %q: conditions.cel must be a non-empty string", rule.ID)
+	}
+
+	ast, issues := celEnv.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("rule %q: compile cel expression: %w", rule.ID, issues.Err())
+	}
+	program, err := celEnv.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: build cel program: %w", rule.ID, err)
+	}
+
+	compiled := &celCompiledRule{rule: rule, program: program, evidence: dedupFieldReferences(celFieldReference.FindAllString(source, -1))}
+	e.cache.put(rule, compiled)
+	return compiled, nil
+}
+
+// Evaluate implements CompiledRule.
+func (c *celCompiledRule) Evaluate(event map[string]interface{}) (bool, []string, error) {
+	out, _, err := c.program.Eval(map[string]interface{}{"event": event})
+	if err != nil {
+		// A CEL expression referencing a field absent from this event
+		// (e.g. event.net.dst_port on a file event) errors rather than
+		// evaluating falsy - that's just a non-match, not a rule bug.
+		return false, nil, nil
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, nil, fmt.Errorf("rule %q: cel expression did not evaluate to a bool", c.rule.ID)
+	}
+	if !matched {
+		return false, nil, nil
+	}
+	return true, c.evidence, nil
+}
+
+// Load replaces the set of rules Evaluate runs, compiling (or reusing from
+// cache) each rule carrying a "cel" Conditions key; every other rule is
+// skipped, not an error - it belongs to a different engine.
+func (e *CELEngine) Load(rules []Rule) error {
+	compiled := make([]*celCompiledRule, 0, len(rules))
+	for _, rule := range rules {
+		if _, ok := rule.Conditions["cel"]; !ok {
+			continue
+		}
+		c, err := e.Compile(rule)
+		if err != nil {
+			return err
+		}
+		compiled = append(compiled, c.(*celCompiledRule))
+	}
+
+	e.mu.Lock()
+	e.rules = compiled
+	e.mu.Unlock()
+	return nil
+}
+
+// Evaluate implements RuleEngine.
+func (e *CELEngine) Evaluate(ctx context.Context, event map[string]interface{}) ([]Match, error) {
+	e.mu.RLock()
+	rules := e.rules
+	e.mu.RUnlock()
+
+	var matches []Match
+	for _, c := range rules {
+		matched, evidence, err := c.Evaluate(event)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matches = append(matches, Match{Rule: c.rule, Evidence: evidence})
+		}
+	}
+	return matches, nil
+}
+
+func dedupFieldReferences(refs []string) []string {
+	seen := make(map[string]bool, len(refs))
+	out := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		out = append(out, ref)
+	}
+	return out
+}
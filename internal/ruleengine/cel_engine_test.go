@@ -0,0 +1,71 @@
+package ruleengine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCELEngine_CompileRejectsMissingSource(t *testing.T) {
+	e := NewCELEngine()
+	_, err := e.Compile(Rule{ID: "r1", Conditions: map[string]interface{}{}})
+	assert.Error(t, err)
+}
+
+func TestCELEngine_EvaluateMatchesAndCollectsEvidence(t *testing.T) {
+	e := NewCELEngine()
+	rule := Rule{
+		ID:         "r1",
+		Conditions: map[string]interface{}{"cel": `event.proc.name == "ncat" && event.net.dst_port == 4444`},
+	}
+	require.NoError(t, e.Load([]Rule{rule}))
+
+	matching := map[string]interface{}{"proc": map[string]interface{}{"name": "ncat"}, "net": map[string]interface{}{"dst_port": 4444}}
+	matches, err := e.Evaluate(context.Background(), matching)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "r1", matches[0].Rule.ID)
+	assert.ElementsMatch(t, []string{"event.proc.name", "event.net.dst_port"}, matches[0].Evidence)
+}
+
+func TestCELEngine_EvaluateSkipsNonMatchingEvent(t *testing.T) {
+	e := NewCELEngine()
+	rule := Rule{ID: "r1", Conditions: map[string]interface{}{"cel": `event.proc.name == "ncat"`}}
+	require.NoError(t, e.Load([]Rule{rule}))
+
+	matches, err := e.Evaluate(context.Background(), map[string]interface{}{"proc": map[string]interface{}{"name": "bash"}})
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestCELEngine_EvaluateTreatsMissingFieldAsNonMatch(t *testing.T) {
+	e := NewCELEngine()
+	rule := Rule{ID: "r1", Conditions: map[string]interface{}{"cel": `event.net.dst_port == 4444`}}
+	require.NoError(t, e.Load([]Rule{rule}))
+
+	matches, err := e.Evaluate(context.Background(), map[string]interface{}{"proc": map[string]interface{}{"name": "ncat"}})
+	require.NoError(t, err, "a rule referencing a field absent from this event type should be a non-match, not an error")
+	assert.Empty(t, matches)
+}
+
+func TestCELEngine_LoadSkipsRulesWithoutCELKey(t *testing.T) {
+	e := NewCELEngine()
+	require.NoError(t, e.Load([]Rule{{ID: "r1", Conditions: map[string]interface{}{"sigma": map[string]interface{}{}}}}))
+
+	matches, err := e.Evaluate(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestCELEngine_CompileReusesCacheWhenRuleUnchanged(t *testing.T) {
+	e := NewCELEngine()
+	rule := Rule{ID: "r1", Version: "v1", Conditions: map[string]interface{}{"cel": `true`}}
+
+	first, err := e.Compile(rule)
+	require.NoError(t, err)
+	second, err := e.Compile(rule)
+	require.NoError(t, err)
+	assert.Same(t, first, second, "compiling the same unchanged rule twice should hit the cache")
+}
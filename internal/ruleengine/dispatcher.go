@@ -0,0 +1,57 @@
+package ruleengine
+
+import (
+	"context"
+	"fmt"
+)
+
+// Dispatcher routes each Rule to whichever engine understands its
+// Conditions shape (a "cel" key vs. a "sigma" key) and aggregates both
+// engines' Evaluate results, so a caller deals with one RuleEngine
+// regardless of how an individual rule is authored.
+type Dispatcher struct {
+	cel   *CELEngine
+	sigma *SigmaEngine
+}
+
+// NewDispatcher wires a Dispatcher over a fresh CELEngine and SigmaEngine.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{cel: NewCELEngine(), sigma: NewSigmaEngine()}
+}
+
+// Compile implements RuleEngine, preferring the cel engine if a rule
+// somehow declares both keys.
+func (d *Dispatcher) Compile(rule Rule) (CompiledRule, error) {
+	if _, ok := rule.Conditions["cel"]; ok {
+		return d.cel.Compile(rule)
+	}
+	if _, ok := rule.Conditions["sigma"]; ok {
+		return d.sigma.Compile(rule)
+	}
+	return nil, fmt.Errorf("rule %q: conditions has neither a \"cel\" nor a \"sigma\" key", rule.ID)
+}
+
+// Load compiles and caches every rule against whichever engine understands
+// it, so a later Evaluate call runs both engines' rules together. A rule
+// carrying neither a "cel" nor a "sigma" key (e.g. a scenario-shaped rule
+// internal/scenario already handles) is skipped, not an error.
+func (d *Dispatcher) Load(rules []Rule) error {
+	if err := d.cel.Load(rules); err != nil {
+		return err
+	}
+	return d.sigma.Load(rules)
+}
+
+// Evaluate implements RuleEngine, running every loaded CEL and Sigma rule
+// against event.
+func (d *Dispatcher) Evaluate(ctx context.Context, event map[string]interface{}) ([]Match, error) {
+	celMatches, err := d.cel.Evaluate(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	sigmaMatches, err := d.sigma.Evaluate(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	return append(celMatches, sigmaMatches...), nil
+}
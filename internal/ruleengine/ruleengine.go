@@ -0,0 +1,58 @@
+// Package ruleengine compiles and evaluates a Rule's Conditions beyond the
+// leaky-bucket correlation internal/scenario already runs for rules shaped
+// like a Scenario (a "capacity" key): a Conditions["cel"] expression
+// (google/cel-go) or a Conditions["sigma"] detection block, each firing
+// immediately on a single matching event rather than needing a bucket to
+// overflow. See cel_engine.go and sigma_engine.go for the two
+// implementations, and dispatcher.go for the RuleEngine that routes a rule
+// to whichever one understands its Conditions shape.
+package ruleengine
+
+import (
+	"context"
+	"time"
+)
+
+// Rule is this package's own view of the fields its engines need from a
+// Rule row, mirroring server.Rule rather than importing it - server is
+// package main, which nothing outside it can import (the same reason
+// internal/migrations and internal/eventstore freeze their own local
+// struct copies).
+type Rule struct {
+	ID         string
+	Version    string
+	Severity   string
+	Title      string
+	Conditions map[string]interface{}
+	UpdatedAt  time.Time
+}
+
+// Match is one Rule whose CompiledRule fired against an event, returned
+// from Evaluate so the caller (server.EventHandler) can turn it into an
+// Alert the same way it already does for internal/scenario's Overflow.
+type Match struct {
+	Rule     Rule
+	Evidence []string
+}
+
+// CompiledRule is a Rule's Conditions, parsed once by Compile and ready to
+// test against any number of events.
+type CompiledRule interface {
+	// Evaluate reports whether event matches, and the field paths that
+	// contributed to the match ("event.<path>" for CEL, the selection's own
+	// "<path>" keys for Sigma) - recorded as Alert.Evidence.
+	Evaluate(event map[string]interface{}) (matched bool, evidence []string, err error)
+}
+
+// RuleEngine compiles Rules into CompiledRules, caching by (ID, Version)
+// and invalidating whenever UpdatedAt moves, and evaluates every rule it
+// has been given (via Load on the concrete engines) against incoming
+// events.
+type RuleEngine interface {
+	// Compile parses rule's Conditions into a CompiledRule, serving a
+	// cached copy if rule hasn't changed since the last call.
+	Compile(rule Rule) (CompiledRule, error)
+	// Evaluate runs every loaded rule's CompiledRule against event,
+	// returning a Match for each one that fires.
+	Evaluate(ctx context.Context, event map[string]interface{}) ([]Match, error)
+}
@@ -0,0 +1,317 @@
+package ruleengine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// sigmaDetection is the shape of a Rule's Conditions["sigma"] value: named
+// selections, each an AND of "<path>": value pairs, the path taken inside
+// the event map (e.g. "proc.name") (a list value
+// matches if the event's field equals any entry in it, the same OR-within-
+// a-list convention real Sigma selections use), combined by Condition - a
+// boolean expression over selection names.
+type sigmaDetection struct {
+	selections map[string]map[string]interface{}
+	condition  string
+}
+
+// SigmaEngine evaluates rules whose Conditions carry a "sigma" key: a
+// detection map with one or more named selections plus a condition
+// expression, e.g.:
+//
+//	sigma:
+//	  selection:
+//	    proc.name: ncat
+//	    net.dst_port: [4444, 1337]
+//	  condition: selection
+//
+// Condition supports and/or/not and parentheses over selection names -
+// real Sigma's full aggregation functions (count(), near, timeframe) are
+// out of scope; a Conditions block needing those isn't expressible here.
+type SigmaEngine struct {
+	cache *compileCache
+
+	mu    sync.RWMutex
+	rules []*sigmaCompiledRule
+}
+
+// NewSigmaEngine returns a SigmaEngine with no rules loaded yet - call
+// Load before Evaluate.
+func NewSigmaEngine() *SigmaEngine {
+	return &SigmaEngine{cache: newCompileCache()}
+}
+
+type sigmaCompiledRule struct {
+	rule      Rule
+	detection *sigmaDetection
+}
+
+// Compile implements RuleEngine.
+func (e *SigmaEngine) Compile(rule Rule) (CompiledRule, error) {
+	if compiled, ok := e.cache.get(rule); ok {
+		return compiled, nil
+	}
+
+	raw, ok := rule.Conditions["sigma"]
+	if !ok {
+		return nil, fmt.Errorf("rule %q: conditions.sigma is required", rule.ID)
+	}
+	detection, err := parseSigmaDetection(raw)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", rule.ID, err)
+	}
+
+	compiled := &sigmaCompiledRule{rule: rule, detection: detection}
+	e.cache.put(rule, compiled)
+	return compiled, nil
+}
+
+func parseSigmaDetection(raw interface{}) (*sigmaDetection, error) {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("conditions.sigma must be a map")
+	}
+	condition, ok := m["condition"].(string)
+	if !ok || condition == "" {
+		return nil, fmt.Errorf("conditions.sigma.condition is required")
+	}
+
+	detection := &sigmaDetection{selections: make(map[string]map[string]interface{}), condition: condition}
+	for name, val := range m {
+		if name == "condition" {
+			continue
+		}
+		selection, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("conditions.sigma.%s must be a map of field to value", name)
+		}
+		detection.selections[name] = selection
+	}
+	if len(detection.selections) == 0 {
+		return nil, fmt.Errorf("conditions.sigma must declare at least one selection")
+	}
+	return detection, nil
+}
+
+// Evaluate implements CompiledRule.
+func (c *sigmaCompiledRule) Evaluate(event map[string]interface{}) (bool, []string, error) {
+	matchedSelections := make(map[string]bool, len(c.detection.selections))
+	var evidence []string
+	for name, selection := range c.detection.selections {
+		ok, fields := matchSelection(event, selection)
+		matchedSelections[name] = ok
+		if ok {
+			evidence = append(evidence, fields...)
+		}
+	}
+
+	matched, err := evalSigmaCondition(c.detection.condition, matchedSelections)
+	if err != nil {
+		return false, nil, fmt.Errorf("rule %q: %w", c.rule.ID, err)
+	}
+	if !matched {
+		return false, nil, nil
+	}
+	return true, evidence, nil
+}
+
+// matchSelection reports whether event satisfies every field:value pair in
+// selection, and the field paths that matched, for Alert.Evidence.
+func matchSelection(event map[string]interface{}, selection map[string]interface{}) (bool, []string) {
+	fields := make([]string, 0, len(selection))
+	for field := range selection {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var evidence []string
+	for _, field := range fields {
+		actual, ok := lookupEventField(event, field)
+		if !ok || !matchFieldValue(actual, selection[field]) {
+			return false, nil
+		}
+		evidence = append(evidence, field)
+	}
+	return true, evidence
+}
+
+func matchFieldValue(actual, expected interface{}) bool {
+	if list, ok := expected.([]interface{}); ok {
+		for _, v := range list {
+			if fmt.Sprint(actual) == fmt.Sprint(v) {
+				return true
+			}
+		}
+		return false
+	}
+	return fmt.Sprint(actual) == fmt.Sprint(expected)
+}
+
+// lookupEventField resolves a dotted path like "proc.name" against event,
+// the flattened event map internal/scenario's filters also see as their
+// "event" variable (see server.eventToScenarioEnv).
+func lookupEventField(event map[string]interface{}, path string) (interface{}, bool) {
+	var cur interface{} = event
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// evalSigmaCondition evaluates a condition expression (and/or/not/parens
+// over selection names) against which selections matched.
+func evalSigmaCondition(condition string, matched map[string]bool) (bool, error) {
+	tokens := tokenizeSigmaCondition(condition)
+	p := &sigmaConditionParser{tokens: tokens, matched: matched}
+	result, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if p.pos != len(p.tokens) {
+		return false, fmt.Errorf("unexpected token %q in condition %q", p.tokens[p.pos], condition)
+	}
+	return result, nil
+}
+
+func tokenizeSigmaCondition(condition string) []string {
+	condition = strings.ReplaceAll(condition, "(", " ( ")
+	condition = strings.ReplaceAll(condition, ")", " ) ")
+	return strings.Fields(condition)
+}
+
+// sigmaConditionParser is a small recursive-descent parser for Sigma's
+// and/or/not/parens condition grammar, precedence low-to-high: or, and,
+// not, primary.
+type sigmaConditionParser struct {
+	tokens  []string
+	pos     int
+	matched map[string]bool
+}
+
+func (p *sigmaConditionParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *sigmaConditionParser) parseOr() (bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		left = left || right
+	}
+	return left, nil
+}
+
+func (p *sigmaConditionParser) parseAnd() (bool, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return false, err
+	}
+	for strings.EqualFold(p.peek(), "and") {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return false, err
+		}
+		left = left && right
+	}
+	return left, nil
+}
+
+func (p *sigmaConditionParser) parseNot() (bool, error) {
+	if strings.EqualFold(p.peek(), "not") {
+		p.pos++
+		val, err := p.parseNot()
+		if err != nil {
+			return false, err
+		}
+		return !val, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *sigmaConditionParser) parsePrimary() (bool, error) {
+	tok := p.peek()
+	if tok == "" {
+		return false, fmt.Errorf("unexpected end of condition")
+	}
+	if tok == "(" {
+		p.pos++
+		val, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if p.peek() != ")" {
+			return false, fmt.Errorf("expected closing paren")
+		}
+		p.pos++
+		return val, nil
+	}
+	p.pos++
+	matched, ok := p.matched[tok]
+	if !ok {
+		return false, fmt.Errorf("unknown selection %q", tok)
+	}
+	return matched, nil
+}
+
+// Load replaces the set of rules Evaluate runs, compiling (or reusing from
+// cache) each rule carrying a "sigma" Conditions key; every other rule is
+// skipped, not an error - it belongs to a different engine.
+func (e *SigmaEngine) Load(rules []Rule) error {
+	compiled := make([]*sigmaCompiledRule, 0, len(rules))
+	for _, rule := range rules {
+		if _, ok := rule.Conditions["sigma"]; !ok {
+			continue
+		}
+		c, err := e.Compile(rule)
+		if err != nil {
+			return err
+		}
+		compiled = append(compiled, c.(*sigmaCompiledRule))
+	}
+
+	e.mu.Lock()
+	e.rules = compiled
+	e.mu.Unlock()
+	return nil
+}
+
+// Evaluate implements RuleEngine.
+func (e *SigmaEngine) Evaluate(ctx context.Context, event map[string]interface{}) ([]Match, error) {
+	e.mu.RLock()
+	rules := e.rules
+	e.mu.RUnlock()
+
+	var matches []Match
+	for _, c := range rules {
+		matched, evidence, err := c.Evaluate(event)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matches = append(matches, Match{Rule: c.rule, Evidence: evidence})
+		}
+	}
+	return matches, nil
+}
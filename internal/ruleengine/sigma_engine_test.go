@@ -0,0 +1,103 @@
+package ruleengine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sigmaRule(id string, detection map[string]interface{}) Rule {
+	return Rule{ID: id, Conditions: map[string]interface{}{"sigma": detection}}
+}
+
+func TestSigmaEngine_EvaluateMatchesSingleSelection(t *testing.T) {
+	e := NewSigmaEngine()
+	rule := sigmaRule("r1", map[string]interface{}{
+		"condition": "selection",
+		"selection": map[string]interface{}{
+			"proc.name":    "ncat",
+			"net.dst_port": []interface{}{4444, 1337},
+		},
+	})
+	require.NoError(t, e.Load([]Rule{rule}))
+
+	event := map[string]interface{}{"proc": map[string]interface{}{"name": "ncat"}, "net": map[string]interface{}{"dst_port": 4444}}
+	matches, err := e.Evaluate(context.Background(), event)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.ElementsMatch(t, []string{"proc.name", "net.dst_port"}, matches[0].Evidence)
+}
+
+func TestSigmaEngine_EvaluateHonorsAndOrNotCondition(t *testing.T) {
+	e := NewSigmaEngine()
+	rule := sigmaRule("r1", map[string]interface{}{
+		"condition": "a and not b",
+		"a":         map[string]interface{}{"proc.name": "ncat"},
+		"b":         map[string]interface{}{"net.dst_port": 22},
+	})
+	require.NoError(t, e.Load([]Rule{rule}))
+
+	nonSSH := map[string]interface{}{"proc": map[string]interface{}{"name": "ncat"}, "net": map[string]interface{}{"dst_port": 4444}}
+	matches, err := e.Evaluate(context.Background(), nonSSH)
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+
+	ssh := map[string]interface{}{"proc": map[string]interface{}{"name": "ncat"}, "net": map[string]interface{}{"dst_port": 22}}
+	matches, err = e.Evaluate(context.Background(), ssh)
+	require.NoError(t, err)
+	assert.Empty(t, matches, "condition 'a and not b' must reject an event where b also matched")
+}
+
+func TestSigmaEngine_CompileRejectsMissingCondition(t *testing.T) {
+	e := NewSigmaEngine()
+	rule := sigmaRule("r1", map[string]interface{}{
+		"selection": map[string]interface{}{"proc.name": "ncat"},
+	})
+	_, err := e.Compile(rule)
+	assert.Error(t, err)
+}
+
+func TestSigmaEngine_EvaluateRejectsUnknownSelectionInCondition(t *testing.T) {
+	e := NewSigmaEngine()
+	rule := sigmaRule("r1", map[string]interface{}{
+		"condition": "missing_selection",
+		"selection": map[string]interface{}{"proc.name": "ncat"},
+	})
+	require.NoError(t, e.Load([]Rule{rule}))
+
+	_, err := e.Evaluate(context.Background(), map[string]interface{}{"proc": map[string]interface{}{"name": "ncat"}})
+	assert.Error(t, err)
+}
+
+func TestSigmaEngine_LoadSkipsRulesWithoutSigmaKey(t *testing.T) {
+	e := NewSigmaEngine()
+	require.NoError(t, e.Load([]Rule{{ID: "r1", Conditions: map[string]interface{}{"cel": "true"}}}))
+
+	matches, err := e.Evaluate(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestDispatcher_RoutesByConditionsShape(t *testing.T) {
+	d := NewDispatcher()
+	celRule := Rule{ID: "cel-rule", Conditions: map[string]interface{}{"cel": `event.x == true`}}
+	sigmaRule := sigmaRule("sigma-rule", map[string]interface{}{
+		"condition": "selection",
+		"selection": map[string]interface{}{"y": true},
+	})
+	require.NoError(t, d.Load([]Rule{celRule, sigmaRule}))
+
+	matches, err := d.Evaluate(context.Background(), map[string]interface{}{"x": true, "y": true})
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	ids := []string{matches[0].Rule.ID, matches[1].Rule.ID}
+	assert.ElementsMatch(t, []string{"cel-rule", "sigma-rule"}, ids)
+}
+
+func TestDispatcher_CompileRejectsRuleWithNeitherKey(t *testing.T) {
+	d := NewDispatcher()
+	_, err := d.Compile(Rule{ID: "r1", Conditions: map[string]interface{}{}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,192 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// BucketSnapshot is one tracked bucket's current level, returned by
+// Store.Snapshot for the GET /v1/scenarios/buckets debug endpoint.
+type BucketSnapshot struct {
+	Key   string  `json:"key"`
+	Level float64 `json:"level"`
+}
+
+// Store persists leaky bucket state per scenario+groupby key, so a
+// restart doesn't lose a partial correlation (see internal/ratelimit's
+// MemoryLimiter and RedisLimiter docs for the same shared-vs-single-node
+// tradeoff this package mirrors).
+type Store interface {
+	// Add leaks key's bucket for the time elapsed since its last update,
+	// then adds one token. If the resulting level reaches capacity, the
+	// implementation resets the bucket to 0 before returning and reports
+	// overflowed=true.
+	Add(ctx context.Context, key string, capacity, leakPerSec float64) (level float64, overflowed bool, err error)
+	// Snapshot lists every bucket currently tracked.
+	Snapshot(ctx context.Context) ([]BucketSnapshot, error)
+}
+
+// MemoryStore is a single-process Store, used as the dev fallback when no
+// Redis address is configured.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucketState
+}
+
+type memoryBucketState struct {
+	level    float64
+	lastLeak time.Time
+}
+
+// NewMemoryStore returns a ready-to-use MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memoryBucketState)}
+}
+
+// Add implements Store.
+func (m *MemoryStore) Add(ctx context.Context, key string, capacity, leakPerSec float64) (float64, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	state, ok := m.buckets[key]
+	if !ok {
+		state = &memoryBucketState{lastLeak: now}
+		m.buckets[key] = state
+	}
+
+	elapsed := now.Sub(state.lastLeak).Seconds()
+	state.level = maxFloat(0, state.level-elapsed*leakPerSec)
+	state.lastLeak = now
+	state.level++
+
+	overflowed := state.level >= capacity
+	if overflowed {
+		state.level = 0
+	}
+	return state.level, overflowed, nil
+}
+
+// Snapshot implements Store.
+func (m *MemoryStore) Snapshot(ctx context.Context) ([]BucketSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshots := make([]BucketSnapshot, 0, len(m.buckets))
+	for key, state := range m.buckets {
+		snapshots = append(snapshots, BucketSnapshot{Key: key, Level: state.level})
+	}
+	return snapshots, nil
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// leakyBucketScript atomically leaks and adds one token to a bucket stored
+// as a Redis hash. KEYS[1] is the bucket key; ARGV is
+// (capacity, leak_per_sec, now_ms). It returns {overflowed (0/1),
+// level*1000}, with level scaled by 1000 for the same reason
+// internal/ratelimit's tokenBucketScript scales tokens.
+const leakyBucketScript = `
+local key = KEYS[1]
+local capacity = tonumber(ARGV[1])
+local leak_per_sec = tonumber(ARGV[2])
+local now_ms = tonumber(ARGV[3])
+
+local stored = redis.call("HMGET", key, "level", "last_leak_ms")
+local level = tonumber(stored[1])
+local last_leak_ms = tonumber(stored[2])
+if level == nil then
+  level = 0
+  last_leak_ms = now_ms
+end
+
+local elapsed_sec = math.max(0, now_ms - last_leak_ms) / 1000
+level = math.max(0, level - elapsed_sec * leak_per_sec)
+level = level + 1
+
+local overflowed = 0
+if level >= capacity then
+  overflowed = 1
+  level = 0
+end
+
+local ttl_sec = math.ceil(capacity / leak_per_sec) + 60
+redis.call("HMSET", key, "level", level, "last_leak_ms", now_ms)
+redis.call("EXPIRE", key, ttl_sec)
+
+return {overflowed, math.floor(level * 1000)}
+`
+
+// redisKeyPrefix namespaces this package's hashes in a shared Redis
+// instance.
+const redisKeyPrefix = "scenario:bucket:"
+
+// RedisStore is a Store backed by a shared Redis instance, so partial
+// correlations survive a server restart and stay consistent across
+// replicas.
+type RedisStore struct {
+	client *redis.Client
+	script *redis.Script
+}
+
+// NewRedisStore wraps an existing Redis client.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client, script: redis.NewScript(leakyBucketScript)}
+}
+
+// Add implements Store.
+func (r *RedisStore) Add(ctx context.Context, key string, capacity, leakPerSec float64) (float64, bool, error) {
+	nowMs := time.Now().UnixMilli()
+	res, err := r.script.Run(ctx, r.client, []string{redisKeyPrefix + key}, capacity, leakPerSec, nowMs).Result()
+	if err != nil {
+		return 0, false, fmt.Errorf("scenario: run leaky bucket script: %w", err)
+	}
+
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 2 {
+		return 0, false, fmt.Errorf("scenario: unexpected script result shape: %v", res)
+	}
+	overflowed := toInt64(vals[0]) == 1
+	level := float64(toInt64(vals[1])) / 1000
+	return level, overflowed, nil
+}
+
+// Snapshot implements Store. It uses KEYS rather than SCAN for simplicity,
+// which is fine for the debug endpoint this backs but would be an O(N)
+// blocking call in a large shared Redis instance under heavier use.
+func (r *RedisStore) Snapshot(ctx context.Context) ([]BucketSnapshot, error) {
+	keys, err := r.client.Keys(ctx, redisKeyPrefix+"*").Result()
+	if err != nil {
+		return nil, fmt.Errorf("scenario: list bucket keys: %w", err)
+	}
+
+	snapshots := make([]BucketSnapshot, 0, len(keys))
+	for _, key := range keys {
+		level, err := r.client.HGet(ctx, key, "level").Float64()
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, BucketSnapshot{Key: key[len(redisKeyPrefix):], Level: level})
+	}
+	return snapshots, nil
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,36 @@
+package scenario
+
+import (
+	"embed"
+	"fmt"
+)
+
+//go:embed builtin/*.yaml
+var builtinFS embed.FS
+
+// BuiltinScenarios parses the scenarios shipped under builtin/, replacing
+// EventHandler.processEventsForAlerts' old hardcoded checks: brute force
+// auth, data exfiltration via large file reads, and lateral movement via
+// SSH. The lateral movement bucket counts SSH connection events rather than
+// distinct destination hosts, an approximation of "N hosts in M minutes"
+// that's good enough without a per-bucket set data structure.
+func BuiltinScenarios() ([]Scenario, error) {
+	entries, err := builtinFS.ReadDir("builtin")
+	if err != nil {
+		return nil, fmt.Errorf("scenario: read builtin directory: %w", err)
+	}
+
+	scenarios := make([]Scenario, 0, len(entries))
+	for _, entry := range entries {
+		data, err := builtinFS.ReadFile("builtin/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("scenario: read %s: %w", entry.Name(), err)
+		}
+		s, err := ParseYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("scenario: parse %s: %w", entry.Name(), err)
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
@@ -0,0 +1,124 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/sirupsen/logrus"
+)
+
+// compiledScenario pairs a Scenario with its compiled filter/groupby expr
+// programs, so Evaluate never re-parses expr source per event.
+type compiledScenario struct {
+	Scenario
+	filterProgram  *vm.Program
+	groupByProgram *vm.Program
+}
+
+// compile parses s's Filter and GroupBy expr-lang expressions, type-checked
+// against env (a sample of the map shape events are flattened into before
+// being passed to Evaluate).
+func compile(s Scenario, env map[string]interface{}) (*compiledScenario, error) {
+	filterProgram, err := expr.Compile(s.Filter, expr.Env(env), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("scenario %q: compile filter: %w", s.ID, err)
+	}
+	groupByProgram, err := expr.Compile(s.GroupBy, expr.Env(env))
+	if err != nil {
+		return nil, fmt.Errorf("scenario %q: compile groupby: %w", s.ID, err)
+	}
+	return &compiledScenario{Scenario: s, filterProgram: filterProgram, groupByProgram: groupByProgram}, nil
+}
+
+// Overflow describes one bucket reaching capacity, returned from Evaluate
+// so the caller (server.EventHandler) can turn it into an Alert the same
+// way it already does for every other detection path.
+type Overflow struct {
+	Scenario Scenario
+	GroupKey string
+	Event    map[string]interface{}
+}
+
+// Engine evaluates every loaded Scenario against each incoming event,
+// replacing the hardcoded substring checks
+// EventHandler.processEventsForAlerts used to run inline.
+type Engine struct {
+	mu        sync.RWMutex
+	scenarios []*compiledScenario
+	buckets   Store
+	logger    *logrus.Logger
+}
+
+// NewEngine wires a bucket Store into an Engine with no scenarios loaded
+// yet - call LoadScenarios before Evaluate.
+func NewEngine(buckets Store, logger *logrus.Logger) *Engine {
+	return &Engine{buckets: buckets, logger: logger}
+}
+
+// LoadScenarios compiles scenarios against env and swaps them in atomically,
+// so a reload (e.g. after an operator edits a Rule) never evaluates a
+// half-updated scenario set.
+func (e *Engine) LoadScenarios(scenarios []Scenario, env map[string]interface{}) error {
+	compiled := make([]*compiledScenario, 0, len(scenarios))
+	for _, s := range scenarios {
+		c, err := compile(s, env)
+		if err != nil {
+			return err
+		}
+		compiled = append(compiled, c)
+	}
+
+	e.mu.Lock()
+	e.scenarios = compiled
+	e.mu.Unlock()
+	return nil
+}
+
+// Evaluate runs every loaded scenario's filter against event, a map-shaped
+// view of one telemetry event. A matching event adds a token to its
+// scenario+groupby bucket; every bucket that reaches capacity is returned
+// as an Overflow and reset.
+func (e *Engine) Evaluate(ctx context.Context, event map[string]interface{}) []Overflow {
+	e.mu.RLock()
+	scenarios := e.scenarios
+	e.mu.RUnlock()
+
+	var overflows []Overflow
+	for _, s := range scenarios {
+		matched, err := expr.Run(s.filterProgram, event)
+		if err != nil {
+			e.logger.WithError(err).WithField("scenario", s.ID).Warn("Scenario filter evaluation failed")
+			continue
+		}
+		if ok, _ := matched.(bool); !ok {
+			continue
+		}
+
+		groupVal, err := expr.Run(s.groupByProgram, event)
+		if err != nil {
+			e.logger.WithError(err).WithField("scenario", s.ID).Warn("Scenario groupby evaluation failed")
+			continue
+		}
+		groupKey := fmt.Sprintf("%s:%v", s.ID, groupVal)
+
+		leakPerSec := 1 / s.Leakspeed.Seconds()
+		_, overflowed, err := e.buckets.Add(ctx, groupKey, s.Capacity, leakPerSec)
+		if err != nil {
+			e.logger.WithError(err).WithField("scenario", s.ID).Warn("Failed to update scenario bucket")
+			continue
+		}
+		if overflowed {
+			overflows = append(overflows, Overflow{Scenario: s.Scenario, GroupKey: groupKey, Event: event})
+		}
+	}
+	return overflows
+}
+
+// Buckets exposes the Engine's Store for the GET /v1/scenarios/buckets
+// debug endpoint.
+func (e *Engine) Buckets() Store {
+	return e.buckets
+}
@@ -0,0 +1,121 @@
+// Package scenario implements a YAML-configured, leaky-bucket correlation
+// engine that replaces one-off substring checks like
+// EventHandler.processEventsForAlerts' old "does this command contain
+// netcat" test with declarative, time-series-aware detections: a Scenario
+// declares a boolean Filter over an event, a GroupBy key partitioning
+// matching events (e.g. per user+host), and a leaky bucket (Capacity,
+// Leakspeed) that overflows - producing an Alert - once enough matching
+// events land in the same group within the leak window. See engine.go for
+// the evaluator and bucket.go for the persisted bucket state.
+package scenario
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is the richer detection schema this package evaluates, stored
+// in a Rule row's jsonb Conditions field (see server.CreateRule/GetRules)
+// or loaded straight from a YAML file via ParseYAML for the built-in set.
+type Scenario struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+
+	// Filter is an expr-lang boolean expression evaluated against one
+	// event, e.g. `event.proc.name == "wget"`.
+	Filter string `json:"filter"`
+	// GroupBy is an expr-lang expression whose result (stringified)
+	// partitions matching events into independent buckets, e.g.
+	// `event.user.id + event.host_id`.
+	GroupBy string `json:"groupby"`
+
+	Capacity  float64       `json:"capacity"`
+	Leakspeed time.Duration `json:"leakspeed"`
+
+	// OverflowTitle is rendered as the resulting Alert's title.
+	OverflowTitle string `json:"overflow_title"`
+}
+
+// Validate reports whether s has everything compile needs.
+func (s Scenario) Validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("scenario: id is required")
+	}
+	if s.Filter == "" {
+		return fmt.Errorf("scenario %q: filter is required", s.ID)
+	}
+	if s.GroupBy == "" {
+		return fmt.Errorf("scenario %q: groupby is required", s.ID)
+	}
+	if s.Capacity <= 0 {
+		return fmt.Errorf("scenario %q: capacity must be positive", s.ID)
+	}
+	if s.Leakspeed <= 0 {
+		return fmt.Errorf("scenario %q: leakspeed must be positive", s.ID)
+	}
+	return nil
+}
+
+// yamlScenario mirrors Scenario but reads Leakspeed as the human-readable
+// duration string ("30s", "5m") scenario YAML is authored with - yaml.v3
+// would otherwise decode straight into time.Duration's underlying int64 as
+// a raw nanosecond count, which isn't what an operator writing "5m" means.
+type yamlScenario struct {
+	ID            string  `yaml:"id"`
+	Name          string  `yaml:"name"`
+	Severity      string  `yaml:"severity"`
+	Filter        string  `yaml:"filter"`
+	GroupBy       string  `yaml:"groupby"`
+	Capacity      float64 `yaml:"capacity"`
+	Leakspeed     string  `yaml:"leakspeed"`
+	OverflowTitle string  `yaml:"overflow_title"`
+}
+
+// ParseYAML loads a single Scenario from a YAML document, the format
+// ship's built-in scenarios (see builtin.go) are authored in.
+func ParseYAML(data []byte) (Scenario, error) {
+	var y yamlScenario
+	if err := yaml.Unmarshal(data, &y); err != nil {
+		return Scenario{}, fmt.Errorf("scenario: parse yaml: %w", err)
+	}
+
+	leakspeed, err := time.ParseDuration(y.Leakspeed)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("scenario %q: invalid leakspeed %q: %w", y.ID, y.Leakspeed, err)
+	}
+
+	s := Scenario{
+		ID:            y.ID,
+		Name:          y.Name,
+		Severity:      y.Severity,
+		Filter:        y.Filter,
+		GroupBy:       y.GroupBy,
+		Capacity:      y.Capacity,
+		Leakspeed:     leakspeed,
+		OverflowTitle: y.OverflowTitle,
+	}
+	if err := s.Validate(); err != nil {
+		return Scenario{}, err
+	}
+	return s, nil
+}
+
+// FromRuleConditions converts a Rule's jsonb Conditions map (decoded by
+// gorm from the same shape yaml.Unmarshal produces) into a Scenario, so an
+// operator can manage scenarios through the existing GET/POST /v1/rules
+// endpoints instead of a dedicated one.
+func FromRuleConditions(id, name, severity string, conditions map[string]interface{}) (Scenario, error) {
+	encoded, err := yaml.Marshal(conditions)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("scenario: re-encode rule conditions: %w", err)
+	}
+	s, err := ParseYAML(encoded)
+	if err != nil {
+		return Scenario{}, err
+	}
+	s.ID, s.Name, s.Severity = id, name, severity
+	return s, nil
+}
@@ -0,0 +1,85 @@
+package scenario
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenario_Validate(t *testing.T) {
+	valid := Scenario{ID: "s1", Filter: "true", GroupBy: "1", Capacity: 1, Leakspeed: time.Second}
+	assert.NoError(t, valid.Validate())
+
+	cases := []struct {
+		name string
+		s    Scenario
+	}{
+		{"missing id", Scenario{Filter: "true", GroupBy: "1", Capacity: 1, Leakspeed: time.Second}},
+		{"missing filter", Scenario{ID: "s1", GroupBy: "1", Capacity: 1, Leakspeed: time.Second}},
+		{"missing groupby", Scenario{ID: "s1", Filter: "true", Capacity: 1, Leakspeed: time.Second}},
+		{"zero capacity", Scenario{ID: "s1", Filter: "true", GroupBy: "1", Leakspeed: time.Second}},
+		{"zero leakspeed", Scenario{ID: "s1", Filter: "true", GroupBy: "1", Capacity: 1}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Error(t, c.s.Validate())
+		})
+	}
+}
+
+func TestParseYAML_ParsesHumanReadableLeakspeed(t *testing.T) {
+	doc := []byte(`
+id: brute-force
+name: Brute force login
+severity: high
+filter: 'event.type == "login_failed"'
+groupby: event.user_id
+capacity: 5
+leakspeed: 5m
+overflow_title: Possible brute force
+`)
+	s, err := ParseYAML(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "brute-force", s.ID)
+	assert.Equal(t, 5*time.Minute, s.Leakspeed)
+	assert.Equal(t, float64(5), s.Capacity)
+}
+
+func TestParseYAML_RejectsInvalidLeakspeed(t *testing.T) {
+	doc := []byte(`
+id: bad
+filter: 'true'
+groupby: '1'
+capacity: 1
+leakspeed: not-a-duration
+`)
+	_, err := ParseYAML(doc)
+	assert.Error(t, err)
+}
+
+func TestParseYAML_RejectsIncompleteScenario(t *testing.T) {
+	doc := []byte(`
+id: bad
+leakspeed: 1m
+`)
+	_, err := ParseYAML(doc)
+	assert.Error(t, err, "a scenario missing filter/groupby/capacity must fail Validate")
+}
+
+func TestFromRuleConditions_RoundTripsThroughYAML(t *testing.T) {
+	conditions := map[string]interface{}{
+		"id":        "rule-1",
+		"filter":    `event.type == "login_failed"`,
+		"groupby":   "event.user_id",
+		"capacity":  5.0,
+		"leakspeed": "1m",
+	}
+	s, err := FromRuleConditions("rule-1", "My Rule", "medium", conditions)
+	require.NoError(t, err)
+	assert.Equal(t, "rule-1", s.ID)
+	assert.Equal(t, "My Rule", s.Name)
+	assert.Equal(t, "medium", s.Severity)
+	assert.Equal(t, time.Minute, s.Leakspeed)
+}
@@ -0,0 +1,220 @@
+package ueba
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultHalfLife is how long it takes a Histogram count to decay to half
+// its value when an Engine isn't given an explicit one.
+const DefaultHalfLife = 14 * 24 * time.Hour
+
+// DefaultRiskScoreAlpha is the EWMA smoothing factor applied to
+// User.RiskScore on every event: how much weight this event's score gets
+// against the subject's prior RiskScore.
+const DefaultRiskScoreAlpha = 0.3
+
+// Weights controls how much each signal contributes to an event's overall
+// anomaly score.
+type Weights struct {
+	Rarity        float64
+	FirstSeen     float64
+	PeerDeviation float64
+}
+
+// DefaultWeights favors rarity as the primary signal, with first-seen and
+// peer-group deviation as smaller additive boosts - a value can be rare
+// for this user but common for their peers (not suspicious) or vice versa
+// (exactly the case peer deviation exists to catch).
+var DefaultWeights = Weights{Rarity: 1.0, FirstSeen: 2.0, PeerDeviation: 1.5}
+
+// EventFeatures is the subset of an Event's fields the scorer needs,
+// mirroring server.Event rather than importing it - server is package
+// main, which nothing outside it can import.
+type EventFeatures struct {
+	TenantID    string
+	UserID      string
+	Dept        string
+	HostID      string
+	Timestamp   time.Time
+	ProcessName string
+	DstPort     string
+	Domain      string
+	FilePath    string
+}
+
+// Contributor is one feature dimension's share of an event's overall
+// anomaly score, sorted descending in Result.Contributors so the largest
+// driver of a score is first.
+type Contributor struct {
+	Feature string  `json:"feature"`
+	Value   string  `json:"value"`
+	Score   float64 `json:"score"`
+}
+
+// Result is one event's UEBA scoring outcome.
+type Result struct {
+	Score        float64       `json:"score"`
+	RiskScore    float64       `json:"risk_score"`
+	Contributors []Contributor `json:"contributors"`
+}
+
+// Engine scores events against the user's behavioral baseline and its
+// dept peer group's, updating the user, host, and dept baselines (and the
+// user's EWMA RiskScore) as it goes. The host baseline is kept up to date
+// but no score is computed against it.
+type Engine struct {
+	store    Store
+	halfLife time.Duration
+	alpha    float64
+	weights  Weights
+}
+
+// NewEngine builds an Engine with the default half-life, EWMA alpha, and
+// signal weights.
+func NewEngine(store Store) *Engine {
+	return &Engine{store: store, halfLife: DefaultHalfLife, alpha: DefaultRiskScoreAlpha, weights: DefaultWeights}
+}
+
+// NewEngineWithConfig builds an Engine with an explicit half-life, EWMA
+// alpha, and signal weights, for a deployment that needs faster-decaying
+// baselines or a different balance between signals.
+func NewEngineWithConfig(store Store, halfLife time.Duration, alpha float64, weights Weights) *Engine {
+	return &Engine{store: store, halfLife: halfLife, alpha: alpha, weights: weights}
+}
+
+// Score updates the user, host, and dept(peer-group) profiles for event,
+// scoring it against each dimension's prior baseline (before this event is
+// folded in) and returning the combined result: a 0-100-ish anomaly score,
+// the user's updated EWMA RiskScore, and the top contributing features.
+func (e *Engine) Score(ctx context.Context, event EventFeatures) (Result, error) {
+	now := event.Timestamp
+	if now.IsZero() {
+		return Result{}, fmt.Errorf("ueba: event timestamp is required")
+	}
+
+	userProfile, err := e.store.Load(event.TenantID, SubjectUser, event.UserID, event.Dept)
+	if err != nil {
+		return Result{}, err
+	}
+	hostProfile, err := e.store.Load(event.TenantID, SubjectHost, event.HostID, "")
+	if err != nil {
+		return Result{}, err
+	}
+	deptProfile, err := e.store.Load(event.TenantID, SubjectDept, event.Dept, "")
+	if err != nil {
+		return Result{}, err
+	}
+
+	userProfile.decay(now, e.halfLife)
+	hostProfile.decay(now, e.halfLife)
+	deptProfile.decay(now, e.halfLife)
+
+	loginHour := fmt.Sprintf("%d", now.UTC().Hour())
+	contributors := e.scoreDimension(userProfile, deptProfile, "process_name", event.ProcessName, func(h *Profile) Histogram { return h.ProcessNames })
+	contributors = append(contributors, e.scoreDimension(userProfile, deptProfile, "dst_port", event.DstPort, func(h *Profile) Histogram { return h.DstPorts })...)
+	contributors = append(contributors, e.scoreDimension(userProfile, deptProfile, "domain", event.Domain, func(h *Profile) Histogram { return h.Domains })...)
+	contributors = append(contributors, e.scoreDimension(userProfile, deptProfile, "file_path", event.FilePath, func(h *Profile) Histogram { return h.FilePaths })...)
+	contributors = append(contributors, e.scoreDimension(userProfile, deptProfile, "login_hour", loginHour, func(h *Profile) Histogram { return h.LoginHours })...)
+
+	userProfile.ProcessNames.observe(event.ProcessName)
+	userProfile.DstPorts.observe(event.DstPort)
+	userProfile.Domains.observe(event.Domain)
+	userProfile.FilePaths.observe(event.FilePath)
+	userProfile.LoginHours.observe(loginHour)
+	hostProfile.ProcessNames.observe(event.ProcessName)
+	hostProfile.DstPorts.observe(event.DstPort)
+	hostProfile.Domains.observe(event.Domain)
+	hostProfile.FilePaths.observe(event.FilePath)
+	deptProfile.ProcessNames.observe(event.ProcessName)
+	deptProfile.DstPorts.observe(event.DstPort)
+	deptProfile.Domains.observe(event.Domain)
+	deptProfile.FilePaths.observe(event.FilePath)
+	deptProfile.LoginHours.observe(loginHour)
+
+	sort.Slice(contributors, func(i, j int) bool { return contributors[i].Score > contributors[j].Score })
+
+	var total float64
+	for _, c := range contributors {
+		total += c.Score
+	}
+
+	userProfile.RiskScore = e.alpha*normalizeScore(total) + (1-e.alpha)*userProfile.RiskScore
+	userProfile.LastSeen = now
+	hostProfile.LastSeen = now
+	deptProfile.LastSeen = now
+
+	if err := e.store.Save(userProfile); err != nil {
+		return Result{}, err
+	}
+	if err := e.store.Save(hostProfile); err != nil {
+		return Result{}, err
+	}
+	if err := e.store.Save(deptProfile); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Score:        normalizeScore(total),
+		RiskScore:    userProfile.RiskScore,
+		Contributors: topContributors(contributors, 5),
+	}, nil
+}
+
+// scoreDimension scores value against subject's prior histogram for one
+// feature dimension, weighted by rarity and first-seen, plus how much
+// subject's distribution for this dimension has diverged from its peer
+// group's. A blank value (e.g. a file event with no process info)
+// contributes nothing.
+func (e *Engine) scoreDimension(subject, peer *Profile, feature, value string, pick func(*Profile) Histogram) []Contributor {
+	if value == "" {
+		return nil
+	}
+	subjectHist := pick(subject)
+	peerHist := pick(peer)
+
+	score := e.weights.Rarity * subjectHist.rarity(value)
+	if subjectHist.firstSeen(value) {
+		score += e.weights.FirstSeen
+	}
+	score += e.weights.PeerDeviation * peerDeviation(subjectHist, peerHist, value)
+
+	return []Contributor{{Feature: feature, Value: value, Score: score}}
+}
+
+// peerDeviation is how much more (or less) rare value is for subject than
+// it is for its peer group - a value this user has never seen but that's
+// common across their whole department scores near zero here even though
+// firstSeen already flagged it; a value rare for both peer and subject
+// contributes little extra; a value common for this subject but rare
+// peer-group-wide is the case this signal exists to catch.
+func peerDeviation(subject, peer Histogram, value string) float64 {
+	diff := peer.rarity(value) - subject.rarity(value)
+	if diff < 0 {
+		return 0
+	}
+	return diff
+}
+
+// normalizeScore maps a raw weighted-sum anomaly score onto a bounded
+// 0-100 scale matching Alert.UEBAScore and User.RiskScore's existing
+// range (UEBAScore was hardcoded to 75 before this package existed).
+func normalizeScore(raw float64) float64 {
+	scaled := raw * 10
+	if scaled > 100 {
+		return 100
+	}
+	if scaled < 0 {
+		return 0
+	}
+	return scaled
+}
+
+func topContributors(contributors []Contributor, n int) []Contributor {
+	if len(contributors) <= n {
+		return contributors
+	}
+	return contributors[:n]
+}
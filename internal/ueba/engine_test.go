@@ -0,0 +1,109 @@
+package ueba
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is an in-memory Store, standing in for the gorm-backed
+// ueba_profiles table in tests.
+type memStore struct {
+	profiles map[string]*Profile
+}
+
+func newMemStore() *memStore {
+	return &memStore{profiles: make(map[string]*Profile)}
+}
+
+func (m *memStore) key(tenantID string, kind SubjectKind, subjectID string) string {
+	return tenantID + "/" + string(kind) + "/" + subjectID
+}
+
+func (m *memStore) Load(tenantID string, kind SubjectKind, subjectID, peerGroup string) (*Profile, error) {
+	k := m.key(tenantID, kind, subjectID)
+	if p, ok := m.profiles[k]; ok {
+		return p, nil
+	}
+	return NewProfile(tenantID, kind, subjectID, peerGroup), nil
+}
+
+func (m *memStore) Save(profile *Profile) error {
+	m.profiles[m.key(profile.TenantID, profile.Kind, profile.SubjectID)] = profile
+	return nil
+}
+
+func TestEngine_ScoreRequiresTimestamp(t *testing.T) {
+	e := NewEngine(newMemStore())
+	_, err := e.Score(context.Background(), EventFeatures{TenantID: "t1", UserID: "u1"})
+	assert.Error(t, err)
+}
+
+func TestEngine_ScoreRatesNeverSeenValueHigherThanFamiliarOne(t *testing.T) {
+	store := newMemStore()
+	e := NewEngine(store)
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	var lastFamiliar Result
+	for i := 0; i < 20; i++ {
+		res, err := e.Score(context.Background(), EventFeatures{
+			TenantID: "t1", UserID: "u1", Dept: "eng", HostID: "h1",
+			Timestamp: base.Add(time.Duration(i) * time.Minute), ProcessName: "bash",
+		})
+		require.NoError(t, err)
+		lastFamiliar = res
+	}
+
+	novel, err := e.Score(context.Background(), EventFeatures{
+		TenantID: "t1", UserID: "u1", Dept: "eng", HostID: "h1",
+		Timestamp: base.Add(21 * time.Minute), ProcessName: "ncat",
+	})
+	require.NoError(t, err)
+	assert.Greater(t, novel.Score, lastFamiliar.Score, "a never-before-seen process should score more anomalous than one the user runs constantly")
+}
+
+func TestEngine_ScorePersistsUpdatedRiskScore(t *testing.T) {
+	store := newMemStore()
+	e := NewEngine(store)
+	ts := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	_, err := e.Score(context.Background(), EventFeatures{TenantID: "t1", UserID: "u1", Dept: "eng", HostID: "h1", Timestamp: ts, ProcessName: "bash"})
+	require.NoError(t, err)
+
+	profile, err := store.Load("t1", SubjectUser, "u1", "eng")
+	require.NoError(t, err)
+	assert.NotZero(t, profile.RiskScore)
+	assert.True(t, profile.LastSeen.Equal(ts))
+}
+
+func TestEngine_ScoreContributorsSortedDescending(t *testing.T) {
+	store := newMemStore()
+	e := NewEngine(store)
+	ts := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	res, err := e.Score(context.Background(), EventFeatures{
+		TenantID: "t1", UserID: "u1", Dept: "eng", HostID: "h1",
+		Timestamp: ts, ProcessName: "ncat", DstPort: "4444", Domain: "evil.example", FilePath: "/tmp/x",
+	})
+	require.NoError(t, err)
+
+	for i := 1; i < len(res.Contributors); i++ {
+		assert.GreaterOrEqual(t, res.Contributors[i-1].Score, res.Contributors[i].Score)
+	}
+}
+
+func TestEngine_ScoreBlankFeaturesContributeNothing(t *testing.T) {
+	store := newMemStore()
+	e := NewEngine(store)
+	ts := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	res, err := e.Score(context.Background(), EventFeatures{TenantID: "t1", UserID: "u1", Dept: "eng", HostID: "h1", Timestamp: ts})
+	require.NoError(t, err)
+
+	for _, c := range res.Contributors {
+		assert.NotEqual(t, "process_name", c.Feature, "a blank ProcessName must not produce a contributor")
+	}
+}
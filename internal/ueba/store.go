@@ -0,0 +1,126 @@
+package ueba
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Store loads and persists Profiles, keyed by (TenantID, Kind, SubjectID).
+type Store interface {
+	// Load returns the existing Profile, or a fresh NewProfile if none
+	// exists yet - a subject with no history isn't an error, it's the
+	// common case for a brand new user or host.
+	Load(tenantID string, kind SubjectKind, subjectID, peerGroup string) (*Profile, error)
+	Save(profile *Profile) error
+}
+
+// profileRow is this package's own gorm-mapped view of the ueba_profiles
+// table (created by internal/migrations' ueba_profiles migration), storing
+// each Histogram as its own jsonb column rather than importing any live
+// application model - the same reason internal/eventstore and
+// internal/migrations freeze their own local struct copies.
+type profileRow struct {
+	TenantID     string    `gorm:"column:tenant_id;primaryKey"`
+	Kind         string    `gorm:"column:subject_kind;primaryKey"`
+	SubjectID    string    `gorm:"column:subject_id;primaryKey"`
+	PeerGroup    string    `gorm:"column:peer_group"`
+	ProcessNames []byte    `gorm:"column:process_names;type:jsonb"`
+	DstPorts     []byte    `gorm:"column:dst_ports;type:jsonb"`
+	Domains      []byte    `gorm:"column:domains;type:jsonb"`
+	FilePaths    []byte    `gorm:"column:file_paths;type:jsonb"`
+	LoginHours   []byte    `gorm:"column:login_hours;type:jsonb"`
+	RiskScore    float64   `gorm:"column:risk_score"`
+	LastSeen     time.Time `gorm:"column:last_seen"`
+	UpdatedAt    time.Time `gorm:"column:updated_at"`
+}
+
+func (profileRow) TableName() string { return "ueba_profiles" }
+
+// GormStore is the Store backing production use, persisting Profiles to
+// the ueba_profiles table.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore wraps an existing *gorm.DB.
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+// Load implements Store.
+func (s *GormStore) Load(tenantID string, kind SubjectKind, subjectID, peerGroup string) (*Profile, error) {
+	var row profileRow
+	err := s.db.Where("tenant_id = ? AND subject_kind = ? AND subject_id = ?", tenantID, string(kind), subjectID).
+		First(&row).Error
+	if err == gorm.ErrRecordNotFound {
+		return NewProfile(tenantID, kind, subjectID, peerGroup), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ueba: load profile %s/%s/%s: %w", tenantID, kind, subjectID, err)
+	}
+
+	profile := &Profile{
+		TenantID:  row.TenantID,
+		Kind:      kind,
+		SubjectID: row.SubjectID,
+		PeerGroup: row.PeerGroup,
+		RiskScore: row.RiskScore,
+		LastSeen:  row.LastSeen,
+	}
+	for dst, raw := range map[*Histogram][]byte{
+		&profile.ProcessNames: row.ProcessNames,
+		&profile.DstPorts:     row.DstPorts,
+		&profile.Domains:      row.Domains,
+		&profile.FilePaths:    row.FilePaths,
+		&profile.LoginHours:   row.LoginHours,
+	} {
+		hist := Histogram{}
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &hist); err != nil {
+				return nil, fmt.Errorf("ueba: decode profile %s/%s/%s: %w", tenantID, kind, subjectID, err)
+			}
+		}
+		*dst = hist
+	}
+	return profile, nil
+}
+
+// Save implements Store.
+func (s *GormStore) Save(profile *Profile) error {
+	row := profileRow{
+		TenantID:  profile.TenantID,
+		Kind:      string(profile.Kind),
+		SubjectID: profile.SubjectID,
+		PeerGroup: profile.PeerGroup,
+		RiskScore: profile.RiskScore,
+		LastSeen:  profile.LastSeen,
+		UpdatedAt: time.Now(),
+	}
+	var err error
+	if row.ProcessNames, err = json.Marshal(profile.ProcessNames); err != nil {
+		return fmt.Errorf("ueba: encode process_names: %w", err)
+	}
+	if row.DstPorts, err = json.Marshal(profile.DstPorts); err != nil {
+		return fmt.Errorf("ueba: encode dst_ports: %w", err)
+	}
+	if row.Domains, err = json.Marshal(profile.Domains); err != nil {
+		return fmt.Errorf("ueba: encode domains: %w", err)
+	}
+	if row.FilePaths, err = json.Marshal(profile.FilePaths); err != nil {
+		return fmt.Errorf("ueba: encode file_paths: %w", err)
+	}
+	if row.LoginHours, err = json.Marshal(profile.LoginHours); err != nil {
+		return fmt.Errorf("ueba: encode login_hours: %w", err)
+	}
+
+	err = s.db.Where(profileRow{TenantID: row.TenantID, Kind: row.Kind, SubjectID: row.SubjectID}).
+		Assign(row).
+		FirstOrCreate(&row).Error
+	if err != nil {
+		return fmt.Errorf("ueba: save profile %s/%s/%s: %w", row.TenantID, row.Kind, row.SubjectID, err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Package ueba maintains a rolling behavioral baseline per user and per
+// host - a Histogram of process names, destination ports, domains, file
+// paths, and login hours seen so far - and scores each incoming event
+// against the user's baseline (the host baseline is updated but not
+// scored against): how rare is this value for this subject, has it never been
+// seen before, and how far does this subject's behavior diverge from its
+// peer group's (User.Dept). See engine.go for the scorer and store.go for
+// the ueba_profiles persistence this package owns.
+package ueba
+
+import (
+	"math"
+	"time"
+)
+
+// Histogram is a decaying frequency count per observed value for one
+// feature dimension, e.g. {"bash": 41, "ncat": 1} for process names.
+type Histogram map[string]float64
+
+// decay multiplies every count in h by the fraction that should remain
+// after elapsed has passed at the given halfLife, so a subject's baseline
+// gradually forgets old behavior instead of accumulating it forever.
+func (h Histogram) decay(elapsed time.Duration, halfLife time.Duration) {
+	if elapsed <= 0 || halfLife <= 0 {
+		return
+	}
+	factor := math.Pow(0.5, elapsed.Hours()/halfLife.Hours())
+	for k, v := range h {
+		decayed := v * factor
+		if decayed < minHistogramCount {
+			delete(h, k)
+			continue
+		}
+		h[k] = decayed
+	}
+}
+
+// minHistogramCount is the floor below which a decayed count is treated as
+// forgotten entirely, so a Histogram doesn't accumulate an unbounded tail
+// of near-zero entries for values seen once, long ago.
+const minHistogramCount = 0.01
+
+// observe records one occurrence of value in h.
+func (h Histogram) observe(value string) {
+	if value == "" {
+		return
+	}
+	h[value]++
+}
+
+// total sums every count currently in h.
+func (h Histogram) total() float64 {
+	var sum float64
+	for _, v := range h {
+		sum += v
+	}
+	return sum
+}
+
+// rarity scores how unexpected value is given h's distribution *before*
+// this event's observation was added, using Laplace-smoothed
+// log-frequency: a value making up half of all observations scores about
+// 1, one making up a thousandth scores about 9 or more. An empty histogram
+// (no prior observations at all) gives every value a rarity of 0, so a
+// brand new subject's values are flagged only by firstSeen, whose weight
+// Engine.scoreDimension adds on top.
+func (h Histogram) rarity(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	count := h[value]
+	total := h.total()
+	probability := (count + 1) / (total + float64(len(h)) + 1)
+	return -math.Log2(probability)
+}
+
+// firstSeen reports whether value has never been observed in h.
+func (h Histogram) firstSeen(value string) bool {
+	if value == "" {
+		return false
+	}
+	_, ok := h[value]
+	return !ok
+}
+
+// Profile is one subject's (a user, a host, or a dept peer group, scoped to
+// a tenant) rolling behavioral baseline.
+type Profile struct {
+	TenantID  string
+	Kind      SubjectKind
+	SubjectID string
+	PeerGroup string
+
+	ProcessNames Histogram
+	DstPorts     Histogram
+	Domains      Histogram
+	FilePaths    Histogram
+	LoginHours   Histogram
+
+	RiskScore float64
+	LastSeen  time.Time
+}
+
+// SubjectKind distinguishes which baseline a Profile tracks.
+type SubjectKind string
+
+const (
+	SubjectUser SubjectKind = "user"
+	SubjectHost SubjectKind = "host"
+	// SubjectDept tracks a peer-group's aggregate baseline - every user
+	// in the same User.Dept feeds the same dept Profile, giving
+	// peerDeviation something to compare an individual user against.
+	SubjectDept SubjectKind = "dept"
+)
+
+// NewProfile returns an empty Profile ready to accumulate observations.
+func NewProfile(tenantID string, kind SubjectKind, subjectID, peerGroup string) *Profile {
+	return &Profile{
+		TenantID:     tenantID,
+		Kind:         kind,
+		SubjectID:    subjectID,
+		PeerGroup:    peerGroup,
+		ProcessNames: Histogram{},
+		DstPorts:     Histogram{},
+		Domains:      Histogram{},
+		FilePaths:    Histogram{},
+		LoginHours:   Histogram{},
+	}
+}
+
+// decay applies Histogram.decay to every dimension in p for the time
+// elapsed since p.LastSeen.
+func (p *Profile) decay(now time.Time, halfLife time.Duration) {
+	if p.LastSeen.IsZero() {
+		return
+	}
+	elapsed := now.Sub(p.LastSeen)
+	p.ProcessNames.decay(elapsed, halfLife)
+	p.DstPorts.decay(elapsed, halfLife)
+	p.Domains.decay(elapsed, halfLife)
+	p.FilePaths.decay(elapsed, halfLife)
+	p.LoginHours.decay(elapsed, halfLife)
+}
@@ -0,0 +1,72 @@
+package ueba
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram_ObserveIgnoresBlankValue(t *testing.T) {
+	h := Histogram{}
+	h.observe("")
+	assert.Empty(t, h)
+}
+
+func TestHistogram_RarityDecreasesAsCountIncreases(t *testing.T) {
+	h := Histogram{}
+	h.observe("bash")
+	rareBefore := h.rarity("bash")
+
+	for i := 0; i < 50; i++ {
+		h.observe("bash")
+	}
+	assert.Less(t, h.rarity("bash"), rareBefore, "a value seen many times should score less rare than seen once")
+}
+
+func TestHistogram_FirstSeen(t *testing.T) {
+	h := Histogram{}
+	assert.True(t, h.firstSeen("bash"))
+	h.observe("bash")
+	assert.False(t, h.firstSeen("bash"))
+	assert.False(t, h.firstSeen(""), "blank value is never considered first-seen")
+}
+
+func TestHistogram_DecayHalvesCountAfterHalfLife(t *testing.T) {
+	h := Histogram{"bash": 100}
+	h.decay(24*time.Hour, 24*time.Hour)
+	assert.InDelta(t, 50, h["bash"], 0.001)
+}
+
+func TestHistogram_DecayForgetsNearZeroCounts(t *testing.T) {
+	h := Histogram{"bash": 0.001}
+	h.decay(time.Hour, time.Hour)
+	_, exists := h["bash"]
+	assert.False(t, exists, "a count decayed below minHistogramCount must be dropped, not kept near zero forever")
+}
+
+func TestHistogram_DecayNoopForNonPositiveInputs(t *testing.T) {
+	h := Histogram{"bash": 10}
+	h.decay(0, time.Hour)
+	assert.Equal(t, float64(10), h["bash"])
+	h.decay(time.Hour, 0)
+	assert.Equal(t, float64(10), h["bash"])
+}
+
+func TestProfile_DecaySkipsWhenNeverSeen(t *testing.T) {
+	p := NewProfile("t1", SubjectUser, "u1", "eng")
+	p.ProcessNames.observe("bash")
+	p.decay(time.Now(), time.Hour)
+	assert.Equal(t, float64(1), p.ProcessNames["bash"], "a profile with zero LastSeen has never been decayed against, so its first decay call must be a no-op")
+}
+
+func TestProfile_DecayAppliesAcrossAllDimensions(t *testing.T) {
+	p := NewProfile("t1", SubjectUser, "u1", "eng")
+	p.ProcessNames["bash"] = 100
+	p.DstPorts["443"] = 100
+	p.LastSeen = time.Unix(0, 0)
+
+	p.decay(time.Unix(0, 0).Add(14*24*time.Hour), 14*24*time.Hour)
+	assert.InDelta(t, 50, p.ProcessNames["bash"], 0.001)
+	assert.InDelta(t, 50, p.DstPorts["443"], 0.001)
+}
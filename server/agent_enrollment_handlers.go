@@ -0,0 +1,322 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"insec/internal/agentauth"
+	"insec/internal/audit"
+	"insec/internal/errs"
+	"insec/internal/models"
+)
+
+// agentEnrollmentPolicyName is the well-known Policy.Name consulted by
+// Register to decide whether a newly registering agent can skip manual
+// validation, keeping the per-tenant toggle in the same jsonb Config shape
+// every other Policy already uses rather than inventing a dedicated table.
+const agentEnrollmentPolicyName = "agent_enrollment"
+
+// agentNonceTTL is how long a PendingAgent's nonce remains redeemable at
+// POST /v1/agents/token once validated.
+const agentNonceTTL = 15 * time.Minute
+
+// AgentEnrollmentHandler implements the two-step registration flow that
+// replaces handing a fresh agent a long-lived token immediately: register
+// creates a pending record and a short-lived nonce, an operator (or an
+// auto-approve policy) validates it, and only then can the agent redeem the
+// nonce for a one-time agentauth.EnrollmentToken to carry through the
+// existing CSR-based enrollment in internal/agentauth.
+type AgentEnrollmentHandler struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+	audit  *audit.Logger
+	tokens agentauth.TokenStore
+}
+
+// NewAgentEnrollmentHandler wires a AgentEnrollmentHandler onto db and the
+// server's agentauth.TokenStore, so a redeemed nonce turns into the same
+// EnrollmentToken type agentauth.Enroller.Enroll already expects.
+func NewAgentEnrollmentHandler(db *gorm.DB, logger *logrus.Logger, auditLogger *audit.Logger, tokens agentauth.TokenStore) *AgentEnrollmentHandler {
+	return &AgentEnrollmentHandler{db: db, logger: logger, audit: auditLogger, tokens: tokens}
+}
+
+// logAudit records a registration-lifecycle event in the hash-chained audit
+// trail, matching every other handler's logAudit convention.
+func (h *AgentEnrollmentHandler) logAudit(c *gin.Context, operation, resource string, details map[string]interface{}) {
+	if h.audit == nil {
+		return
+	}
+	actor, _ := c.Get("user_id")
+	actorID, _ := actor.(string)
+	details = withImpersonationContext(c, details)
+	entry := audit.Entry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Actor:     actorID,
+		Operation: operation,
+		Resource:  resource,
+		Details:   details,
+	}
+	if err := h.audit.Log(c.Request.Context(), entry); err != nil {
+		h.logger.WithError(err).Error("Failed to write audit log entry")
+	}
+}
+
+// RequireValidatedAgent returns Gin middleware for /v1/events and friends
+// that rejects an otherwise-authenticated agent (agentauth.RequireAgentCert
+// must run first and set agent_host_id) whose PendingAgent record isn't
+// models.AgentStatusValidated, closing the gap where a pending agent could
+// still mint a client certificate's identity claims into accepted events.
+func RequireValidatedAgent(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostID, _ := c.Get("agent_host_id")
+		id, _ := hostID.(string)
+		if id == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "agent identity required"})
+			return
+		}
+
+		var agent PendingAgent
+		if err := db.Where("id = ?", id).First(&agent).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "agent is not registered"})
+			return
+		}
+		if agent.Status != models.AgentStatusValidated {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "agent has not been validated yet"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Register handles POST /v1/agents/register. It never returns a long-lived
+// credential: the agent gets back its status and a nonce it can redeem at
+// POST /v1/agents/token only once that status is "validated".
+func (h *AgentEnrollmentHandler) Register(c *gin.Context) {
+	var req models.AgentRegistration
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("agent.register.invalid", "invalid agent registration payload").Wrap(err))
+		return
+	}
+	if req.TenantID == "" {
+		c.Error(errs.BadRequest("agent.register.tenant_required", "tenant_id is required"))
+		return
+	}
+
+	now := time.Now()
+	agent := PendingAgent{
+		ID:             uuid.New().String(),
+		TenantID:       req.TenantID,
+		Hostname:       req.Hostname,
+		OS:             req.OS,
+		Version:        req.Version,
+		Capabilities:   req.Capabilities,
+		SourceIP:       c.ClientIP(),
+		Status:         models.AgentStatusPending,
+		Nonce:          uuid.New().String(),
+		NonceExpiresAt: now.Add(agentNonceTTL),
+	}
+
+	if h.autoApprove(req.TenantID, agent.SourceIP) {
+		agent.Status = models.AgentStatusValidated
+		agent.ValidatedAt = &now
+		agent.ValidatedBy = "auto-approve policy"
+	}
+
+	if err := h.db.Create(&agent).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to create pending agent")
+		c.Error(errs.Internal("agent.register.failed", "failed to register agent").Wrap(err))
+		return
+	}
+
+	h.logAudit(c, "REGISTER", "agent:"+agent.ID, map[string]interface{}{
+		"tenant_id": agent.TenantID, "status": agent.Status, "source_ip": agent.SourceIP,
+	})
+	c.JSON(http.StatusCreated, models.AgentRegisterResponse{
+		AgentID:        agent.ID,
+		Status:         agent.Status,
+		Nonce:          agent.Nonce,
+		NonceExpiresAt: agent.NonceExpiresAt,
+	})
+}
+
+// autoApprove reports whether a newly registering agent from sourceIP
+// should skip manual validation, per the tenant's agent_enrollment Policy:
+// either Config["auto_approve"] is true, or sourceIP falls inside one of
+// Config["allowlist_cidrs"], so brownfield deployments that can't pause for
+// an operator still work.
+func (h *AgentEnrollmentHandler) autoApprove(tenantID, sourceIP string) bool {
+	var policy Policy
+	err := h.db.Where("tenant_id = ? AND name = ? AND enabled = ?", tenantID, agentEnrollmentPolicyName, true).
+		First(&policy).Error
+	if err != nil {
+		return false
+	}
+
+	if approve, ok := policy.Config["auto_approve"].(bool); ok && approve {
+		return true
+	}
+
+	cidrs, ok := policy.Config["allowlist_cidrs"].([]interface{})
+	if !ok || sourceIP == "" {
+		return false
+	}
+	ip := net.ParseIP(sourceIP)
+	if ip == nil {
+		return false
+	}
+	for _, raw := range cidrs {
+		cidr, ok := raw.(string)
+		if !ok {
+			continue
+		}
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate handles POST /v1/agents/:id/validate, the operator-driven
+// approval an auto-approve policy would otherwise perform automatically.
+func (h *AgentEnrollmentHandler) Validate(c *gin.Context) {
+	id := c.Param("id")
+	agent, apiErr := h.validateOne(c, id)
+	if apiErr != nil {
+		c.Error(apiErr)
+		return
+	}
+	c.JSON(http.StatusOK, agent)
+}
+
+func (h *AgentEnrollmentHandler) validateOne(c *gin.Context, id string) (*PendingAgent, *errs.Error) {
+	var agent PendingAgent
+	if err := h.db.Where("id = ?", id).First(&agent).Error; err != nil {
+		return nil, errs.NotFound("agent.validate.not_found", "pending agent not found")
+	}
+	if agent.Status == models.AgentStatusValidated {
+		return &agent, nil
+	}
+
+	actor, _ := c.Get("user_id")
+	actorID, _ := actor.(string)
+	now := time.Now()
+	agent.Status = models.AgentStatusValidated
+	agent.ValidatedAt = &now
+	agent.ValidatedBy = actorID
+	if err := h.db.Save(&agent).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to validate pending agent")
+		return nil, errs.Internal("agent.validate.failed", "failed to validate agent").Wrap(err)
+	}
+
+	h.logAudit(c, "VALIDATE", "agent:"+agent.ID, map[string]interface{}{
+		"tenant_id": agent.TenantID, "validated_by": actorID,
+	})
+	return &agent, nil
+}
+
+type bulkValidateRequest struct {
+	AgentIDs []string `json:"agent_ids" binding:"required"`
+}
+
+// BulkValidate handles POST /v1/agents/bulk-validate, the CLI-facing
+// counterpart to Validate for operators clearing a large pending queue at
+// once (see cmd/insec-cli's "agents validate" subcommand).
+func (h *AgentEnrollmentHandler) BulkValidate(c *gin.Context) {
+	var req bulkValidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("agent.bulk_validate.invalid", "invalid bulk validate payload").Wrap(err))
+		return
+	}
+
+	validated := 0
+	for _, id := range req.AgentIDs {
+		if _, apiErr := h.validateOne(c, id); apiErr != nil {
+			h.logger.WithField("agent_id", id).Warn("Skipping agent in bulk validate: " + apiErr.Msg)
+			continue
+		}
+		validated++
+	}
+	c.JSON(http.StatusOK, gin.H{"validated": validated, "requested": len(req.AgentIDs)})
+}
+
+// ListPending handles GET /v1/agents?status=pending for the console, and
+// any other status value for completeness (e.g. ?status=validated).
+func (h *AgentEnrollmentHandler) ListPending(c *gin.Context) {
+	status := c.DefaultQuery("status", models.AgentStatusPending)
+	var agents []PendingAgent
+	query := h.db.Where("status = ?", status)
+	if tenantID := c.Query("tenant_id"); tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+	if err := query.Find(&agents).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to list pending agents")
+		c.Error(errs.Internal("agent.list.failed", "failed to list agents").Wrap(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"agents": agents})
+}
+
+// Token handles POST /v1/agents/token: a validated agent redeems its nonce
+// for a one-time agentauth.EnrollmentToken, which it then presents to the
+// existing CSR-based /v1/agents/enroll endpoint exactly as if an operator
+// had handed it out of band. The nonce is consumed on success so it can't
+// be replayed.
+func (h *AgentEnrollmentHandler) Token(c *gin.Context) {
+	var req models.AgentTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("agent.token.invalid", "invalid token request").Wrap(err))
+		return
+	}
+
+	var agent PendingAgent
+	if err := h.db.Where("id = ?", req.AgentID).First(&agent).Error; err != nil {
+		c.Error(errs.NotFound("agent.token.not_found", "pending agent not found"))
+		return
+	}
+	if agent.Status != models.AgentStatusValidated {
+		c.Error(errs.Forbidden("agent.token.not_validated", "agent has not been validated yet"))
+		return
+	}
+	if agent.Nonce == "" || agent.Nonce != req.Nonce {
+		c.Error(errs.Forbidden("agent.token.nonce_invalid", "nonce is invalid or already used"))
+		return
+	}
+	if time.Now().After(agent.NonceExpiresAt) {
+		c.Error(errs.Forbidden("agent.token.nonce_expired", "nonce has expired, re-register the agent"))
+		return
+	}
+
+	enrollmentToken := &agentauth.EnrollmentToken{
+		Token:    uuid.New().String(),
+		TenantID: agent.TenantID,
+		HostID:   agent.ID,
+		ExpireAt: time.Now().Add(agentNonceTTL),
+	}
+	if err := h.tokens.Put(enrollmentToken); err != nil {
+		h.logger.WithError(err).Error("Failed to mint enrollment token")
+		c.Error(errs.Internal("agent.token.failed", "failed to issue enrollment token").Wrap(err))
+		return
+	}
+
+	agent.Nonce = ""
+	if err := h.db.Save(&agent).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to consume agent nonce")
+	}
+
+	h.logAudit(c, "TOKEN_ISSUE", "agent:"+agent.ID, map[string]interface{}{"tenant_id": agent.TenantID})
+	c.JSON(http.StatusOK, gin.H{
+		"enrollment_token": enrollmentToken.Token,
+		"expires_at":       enrollmentToken.ExpireAt,
+	})
+}
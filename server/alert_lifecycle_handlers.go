@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"insec/internal/alertlifecycle"
+	"insec/internal/audit"
+	"insec/internal/errs"
+)
+
+// AlertLifecycleHandler exposes alert transitions, bulk assignment, and
+// per-alert timelines on top of internal/alertlifecycle's state machine,
+// and implements alertlifecycle.BreachNotifier so the package's SLA
+// monitor can raise a real sla_breach Alert without importing server's
+// (package main's) Alert type itself.
+type AlertLifecycleHandler struct {
+	db      *gorm.DB
+	logger  *logrus.Logger
+	audit   *audit.Logger
+	service *alertlifecycle.AlertService
+}
+
+// NewAlertLifecycleHandler wires an AlertLifecycleHandler onto db, matching
+// DecisionHandler's constructor shape.
+func NewAlertLifecycleHandler(db *gorm.DB, logger *logrus.Logger, auditLogger *audit.Logger) *AlertLifecycleHandler {
+	service := alertlifecycle.NewAlertService(
+		alertlifecycle.NewGormAlertStore(db),
+		alertlifecycle.NewGormTransitionStore(db),
+		uuid.New().String,
+	)
+	return &AlertLifecycleHandler{db: db, logger: logger, audit: auditLogger, service: service}
+}
+
+// logAudit records a transition/assignment against the hash-chained audit
+// trail, matching EventHandler.logAudit and DecisionHandler.logAudit's
+// convention: failures are logged but never block the HTTP response.
+func (h *AlertLifecycleHandler) logAudit(c *gin.Context, operation, resource string, details map[string]interface{}) {
+	if h.audit == nil {
+		return
+	}
+	actor, _ := c.Get("user_id")
+	actorID, _ := actor.(string)
+	details = withImpersonationContext(c, details)
+	entry := audit.Entry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Actor:     actorID,
+		Operation: operation,
+		Resource:  resource,
+		Details:   details,
+	}
+	if err := h.audit.Log(c.Request.Context(), entry); err != nil {
+		h.logger.WithError(err).Error("Failed to write audit log entry")
+	}
+}
+
+type transitionAlertRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note,omitempty"`
+}
+
+// POST /v1/alerts/:id/transition - Move an alert to a new lifecycle
+// status, recording who did it and why in the append-only transition log.
+func (h *AlertLifecycleHandler) TransitionAlert(c *gin.Context) {
+	alertID := c.Param("id")
+	var req transitionAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("alert.transition.invalid_body", "invalid transition payload").Wrap(err))
+		return
+	}
+
+	actor, _ := c.Get("user_id")
+	actorID, _ := actor.(string)
+
+	alert, err := h.service.Transition(alertID, alertlifecycle.Status(req.Status), actorID, req.Note)
+	if err != nil {
+		if errors.Is(err, alertlifecycle.ErrInvalidTransition) {
+			c.Error(errs.BadRequest("alert.transition.invalid", err.Error()))
+			return
+		}
+		h.logger.WithError(err).Error("Failed to transition alert")
+		c.Error(errs.Internal("alert.transition.failed", "failed to transition alert").Wrap(err))
+		return
+	}
+
+	h.logAudit(c, "TRANSITION", "alert", map[string]interface{}{"alert_id": alertID, "status": string(alert.Status)})
+	c.JSON(http.StatusOK, gin.H{"alert_id": alertID, "status": alert.Status})
+}
+
+type bulkAssignRequest struct {
+	AlertIDs []string `json:"alert_ids" binding:"required"`
+	Assignee string   `json:"assignee" binding:"required"`
+}
+
+// POST /v1/alerts/bulk-assign - Reassign a batch of alerts to one
+// assignee in a single call, independent of their lifecycle status.
+func (h *AlertLifecycleHandler) BulkAssignAlerts(c *gin.Context) {
+	var req bulkAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("alert.bulk_assign.invalid_body", "invalid bulk-assign payload").Wrap(err))
+		return
+	}
+	if len(req.AlertIDs) == 0 {
+		c.Error(errs.BadRequest("alert.bulk_assign.empty", "at least one alert_id is required"))
+		return
+	}
+
+	if err := h.service.BulkAssign(req.AlertIDs, req.Assignee); err != nil {
+		h.logger.WithError(err).Error("Failed to bulk assign alerts")
+		c.Error(errs.Internal("alert.bulk_assign.failed", "failed to bulk assign alerts").Wrap(err))
+		return
+	}
+
+	h.logAudit(c, "BULK_ASSIGN", "alert", map[string]interface{}{"alert_ids": req.AlertIDs, "assignee": req.Assignee})
+	c.JSON(http.StatusOK, gin.H{"assigned": len(req.AlertIDs), "assignee": req.Assignee})
+}
+
+// GET /v1/alerts/:id/timeline - An alert's full transition history, oldest
+// first.
+func (h *AlertLifecycleHandler) GetAlertTimeline(c *gin.Context) {
+	alertID := c.Param("id")
+	timeline, err := h.service.Timeline(alertID)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load alert timeline")
+		c.Error(errs.Internal("alert.timeline.failed", "failed to load alert timeline").Wrap(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"alert_id": alertID, "timeline": timeline})
+}
+
+// NotifyBreach implements alertlifecycle.BreachNotifier: it creates a real
+// sla_breach meta-alert against the same tenant as the breached alert,
+// stamping Entities["source_alert_id"] so GormAlertStore.HasBreachAlert can
+// find it again and skip re-flagging the same breach on the monitor's next
+// sweep.
+func (h *AlertLifecycleHandler) NotifyBreach(alert alertlifecycle.OpenAlert, target, elapsed time.Duration) error {
+	meta := Alert{
+		CreatedAt:   time.Now(),
+		Severity:    alert.Severity,
+		Title:       fmt.Sprintf("SLA breach: alert %s open for %s (target %s)", alert.ID, elapsed.Round(time.Second), target.Round(time.Second)),
+		TenantID:    alert.TenantID,
+		RuleID:      "sla_breach",
+		RuleVersion: "1.0",
+		Entities: map[string]interface{}{
+			"source_alert_id": alert.ID,
+			"target_seconds":  target.Seconds(),
+			"elapsed_seconds": elapsed.Seconds(),
+		},
+		Status: "open",
+	}
+	if err := h.db.Create(&meta).Error; err != nil {
+		return fmt.Errorf("create sla_breach alert for %s: %w", alert.ID, err)
+	}
+	h.logger.WithFields(logrus.Fields{"alert_id": alert.ID, "breach_alert_id": strconv.FormatUint(uint64(meta.ID), 10)}).
+		Warn("SLA breach detected")
+	return nil
+}
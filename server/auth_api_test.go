@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+
+	"insec/internal/auth"
+	"insec/internal/errs"
+)
+
+// memUserRepository is an in-memory auth.UserRepository test double, so
+// this suite can exercise the real login -> AuthMiddleware -> protected
+// route chain without a Postgres connection, the same way buildDeps would
+// wire a *auth.GormUserRepository in production.
+type memUserRepository struct {
+	byEmail map[string]*auth.User
+}
+
+func newMemUserRepository(users ...*auth.User) *memUserRepository {
+	repo := &memUserRepository{byEmail: make(map[string]*auth.User)}
+	for _, u := range users {
+		repo.byEmail[u.Email] = u
+	}
+	return repo
+}
+
+func (r *memUserRepository) GetUserByID(id string) (*auth.User, error) {
+	for _, u := range r.byEmail {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user %s not found", id)
+}
+
+func (r *memUserRepository) GetUserByEmail(email string) (*auth.User, error) {
+	u, ok := r.byEmail[email]
+	if !ok {
+		return nil, fmt.Errorf("user with email %s not found", email)
+	}
+	return u, nil
+}
+
+func (r *memUserRepository) CreateUser(u *auth.User) error {
+	r.byEmail[u.Email] = u
+	return nil
+}
+
+func (r *memUserRepository) UpdateUser(u *auth.User) error {
+	r.byEmail[u.Email] = u
+	return nil
+}
+
+func (r *memUserRepository) DeleteUser(id string) error {
+	for email, u := range r.byEmail {
+		if u.ID == id {
+			delete(r.byEmail, email)
+		}
+	}
+	return nil
+}
+
+// AuthAPITestSuite wires AuthHandler.Login and AuthMiddleware together the
+// same way newRouter assembles them onto the real router, without standing
+// up the rest of deps (db, agent CA, rate limiter) the full router needs.
+type AuthAPITestSuite struct {
+	suite.Suite
+	router      *gin.Engine
+	authService *auth.AuthService
+}
+
+func (suite *AuthAPITestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("securepassword123"), bcrypt.DefaultCost)
+	suite.Require().NoError(err)
+	repo := newMemUserRepository(&auth.User{
+		ID:       "admin-1",
+		Email:    "admin@insec.com",
+		Password: string(hash),
+		Role:     "admin",
+	})
+	suite.authService = auth.NewAuthService(repo, "test-secret-key")
+
+	r := gin.New()
+	r.Use(errs.Middleware(logrus.New()))
+	authH := NewAuthHandler(suite.authService, logrus.New(), nil)
+	r.POST("/api/v1/auth/login", authH.Login)
+
+	authed := r.Group("/api/v1")
+	authed.Use(AuthMiddleware(suite.authService))
+	authed.GET("/alerts", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"alerts": []interface{}{}})
+	})
+
+	suite.router = r
+}
+
+func TestAuthAPITestSuite(t *testing.T) {
+	suite.Run(t, new(AuthAPITestSuite))
+}
+
+func (suite *AuthAPITestSuite) TestAuthenticationFlow() {
+	body, _ := json.Marshal(map[string]string{"email": "admin@insec.com", "password": "securepassword123"})
+	req, _ := http.NewRequest("POST", "/api/v1/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var resp tokenPairResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+	suite.NotEmpty(resp.AccessToken)
+	suite.NotEmpty(resp.RefreshToken)
+}
+
+func (suite *AuthAPITestSuite) TestAuthenticationMiddleware() {
+	req, _ := http.NewRequest("GET", "/api/v1/alerts", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusUnauthorized, w.Code)
+
+	token := suite.loginToken()
+	req, _ = http.NewRequest("GET", "/api/v1/alerts", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+}
+
+func (suite *AuthAPITestSuite) TestInvalidToken() {
+	req, _ := http.NewRequest("GET", "/api/v1/alerts", nil)
+	req.Header.Set("Authorization", "Bearer invalid.token.here")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
+
+func (suite *AuthAPITestSuite) loginToken() string {
+	body, _ := json.Marshal(map[string]string{"email": "admin@insec.com", "password": "securepassword123"})
+	req, _ := http.NewRequest("POST", "/api/v1/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var resp tokenPairResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.AccessToken
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"insec/internal/audit"
+	"insec/internal/auth"
+	"insec/internal/errs"
+)
+
+// AuthHandler exposes the login/refresh/logout endpoints backed by
+// internal/auth.AuthService.
+type AuthHandler struct {
+	auth   *auth.AuthService
+	logger *logrus.Logger
+	audit  *audit.Logger
+}
+
+// NewAuthHandler wires an AuthService into HTTP handlers.
+func NewAuthHandler(authService *auth.AuthService, logger *logrus.Logger, auditLogger *audit.Logger) *AuthHandler {
+	return &AuthHandler{auth: authService, logger: logger, audit: auditLogger}
+}
+
+// logAudit records an auth-flow event (impersonation grant/revoke) to the
+// hash-chained audit trail. Failures are logged but never block the HTTP
+// response, matching EventHandler.logAudit's convention.
+func (h *AuthHandler) logAudit(c *gin.Context, operation, resource string, details map[string]interface{}) {
+	if h.audit == nil {
+		return
+	}
+	actor, _ := c.Get("user_id")
+	actorID, _ := actor.(string)
+	entry := audit.Entry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Actor:     actorID,
+		Operation: operation,
+		Resource:  resource,
+		Details:   details,
+	}
+	if err := h.audit.Log(c.Request.Context(), entry); err != nil {
+		h.logger.WithError(err).Error("Failed to write audit log entry")
+	}
+}
+
+type loginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type tokenPairResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// POST /api/v1/auth/login
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("auth.login.invalid_body", "invalid login payload").Wrap(err))
+		return
+	}
+
+	pair, err := h.auth.Login(req.Email, req.Password)
+	if err != nil {
+		c.Error(errs.Unauthorized("auth.login.invalid_credentials", "invalid credentials").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenPairResponse{AccessToken: pair.AccessToken, RefreshToken: pair.RefreshToken})
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// POST /api/v1/auth/refresh - rotate a refresh token for a new access+refresh
+// pair. Reuse of an already-rotated refresh token revokes its whole family.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("auth.refresh.invalid_body", "invalid refresh payload").Wrap(err))
+		return
+	}
+
+	pair, err := h.auth.Refresh(req.RefreshToken)
+	if err != nil {
+		h.logger.WithError(err).Warn("Refresh token rejected")
+		c.Error(errs.Unauthorized("auth.refresh.invalid_token", "invalid or revoked refresh token").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenPairResponse{AccessToken: pair.AccessToken, RefreshToken: pair.RefreshToken})
+}
+
+type mfaChallengeRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+// POST /api/v1/auth/mfa/challenge - start a step-up MFA challenge.
+func (h *AuthHandler) MFAChallenge(c *gin.Context) {
+	var req mfaChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("auth.mfa_challenge.invalid_body", "invalid challenge payload").Wrap(err))
+		return
+	}
+	challenge := h.auth.IssueMFAChallenge(req.UserID)
+	c.JSON(http.StatusOK, gin.H{
+		"challenge_id": challenge.ChallengeID,
+		"expires_at":   challenge.ExpiresAt,
+	})
+}
+
+type mfaVerifyRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	// ChallengeID ties this verify call to the challenge MFAChallenge
+	// issued for UserID; RedeemMFAChallenge is what actually enforces that
+	// binding.
+	ChallengeID string `json:"challenge_id" binding:"required"`
+	Method      string `json:"method" binding:"required"` // HTTP method of the call the assertion will gate
+	Path        string `json:"path" binding:"required"`   // URL path of the call the assertion will gate
+	// Code is the user's current TOTP code, or one of their recovery codes.
+	// VerifyStepUpCode checks it against the user's enrolled factor before
+	// an assertion is ever issued.
+	Code string `json:"code" binding:"required"`
+}
+
+// POST /api/v1/auth/mfa/verify - redeem a TOTP/recovery code, bound to a
+// challenge issued by MFAChallenge for the same authenticated caller, for a
+// request-bound X-MFA-Token assertion. Must run behind AuthMiddleware like
+// MFAChallenge - otherwise anyone who knows a user's id and a single
+// TOTP/recovery code could mint an assertion with no session of their own.
+func (h *AuthHandler) MFAVerify(c *gin.Context) {
+	var req mfaVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("auth.mfa_verify.invalid_body", "invalid verify payload").Wrap(err))
+		return
+	}
+
+	callerClaimsVal, _ := c.Get("claims")
+	callerClaims, ok := callerClaimsVal.(*auth.Claims)
+	if !ok || callerClaims.UserID != req.UserID {
+		c.Error(errs.Unauthorized("auth.mfa_verify.unauthenticated", "authentication required"))
+		return
+	}
+
+	if err := h.auth.RedeemMFAChallenge(req.ChallengeID, req.UserID); err != nil {
+		c.Error(errs.Unauthorized("auth.mfa_verify.invalid_challenge", "invalid or expired mfa challenge").Wrap(err))
+		return
+	}
+	if err := h.auth.VerifyStepUpCode(req.UserID, req.Code); err != nil {
+		c.Error(errs.Unauthorized("auth.mfa_verify.invalid_code", "invalid mfa code").Wrap(err))
+		return
+	}
+	assertion := h.auth.IssueMFAAssertion(req.UserID, req.Method, req.Path)
+	c.JSON(http.StatusOK, gin.H{"mfa_token": assertion})
+}
+
+type impersonateRequest struct {
+	TargetUserID  string `json:"target_user_id" binding:"required"`
+	Justification string `json:"justification" binding:"required"`
+}
+
+// POST /api/v1/auth/impersonate - mint a reduced-TTL token for another user
+// in the same tenant, for an analyst investigating that user's activity.
+// Must run behind AuthMiddleware, which populates "claims" with the
+// caller's *auth.Claims; Impersonate itself enforces role, tenant, and
+// recursive-impersonation policy. Every call, allowed or not, is recorded
+// in the audit log with both identities and the justification.
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	var req impersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("auth.impersonate.invalid_body", "invalid impersonation payload").Wrap(err))
+		return
+	}
+
+	callerClaimsVal, _ := c.Get("claims")
+	callerClaims, ok := callerClaimsVal.(*auth.Claims)
+	if !ok {
+		c.Error(errs.Unauthorized("auth.impersonate.unauthenticated", "authentication required"))
+		return
+	}
+
+	token, err := h.auth.Impersonate(callerClaims, auth.ImpersonationRequest{
+		TargetUserID:  req.TargetUserID,
+		Justification: req.Justification,
+	})
+
+	h.logAudit(c, "IMPERSONATE", "user:"+req.TargetUserID, map[string]interface{}{
+		"impersonator":  callerClaims.UserID,
+		"target":        req.TargetUserID,
+		"justification": req.Justification,
+		"allowed":       err == nil,
+	})
+
+	if err != nil {
+		h.logger.WithError(err).Warn("Impersonation request denied")
+		c.Error(errs.Forbidden("auth.impersonate.denied", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"access_token": token})
+}
+
+type revokeImpersonationRequest struct {
+	Nonce string `json:"nonce" binding:"required"`
+}
+
+// POST /api/v1/auth/impersonate/revoke - immediately invalidate an
+// in-flight impersonation token by its nonce, independent of its stated
+// expiry.
+func (h *AuthHandler) RevokeImpersonation(c *gin.Context) {
+	var req revokeImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("auth.impersonate_revoke.invalid_body", "invalid revoke payload").Wrap(err))
+		return
+	}
+
+	if err := h.auth.RevokeImpersonationNonce(req.Nonce); err != nil {
+		h.logger.WithError(err).Error("Failed to revoke impersonation nonce")
+		c.Error(errs.Internal("auth.impersonate_revoke.failed", "failed to revoke token").Wrap(err))
+		return
+	}
+
+	actor, _ := c.Get("user_id")
+	h.logAudit(c, "REVOKE_IMPERSONATION", "impersonation_nonce:"+req.Nonce, map[string]interface{}{
+		"revoked_by": actor,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+}
+
+// POST /api/v1/auth/logout - revoke the refresh token's entire family.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("auth.logout.invalid_body", "invalid logout payload").Wrap(err))
+		return
+	}
+
+	if err := h.auth.Logout(req.RefreshToken); err != nil {
+		c.Error(errs.BadRequest("auth.logout.invalid_token", "invalid refresh token").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "logged_out"})
+}
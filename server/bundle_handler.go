@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"insec/internal/bundle"
+	"insec/internal/errs"
+	"insec/internal/eventstore"
+)
+
+// bundleBatchSize is how many decoded events IngestEventBundle accumulates
+// before COPY-inserting them and handing the batch off to
+// processEventsForAlerts, so a multi-hour bundle is loaded in bounded-size
+// chunks instead of one COPY (and one alert-processing goroutine) per
+// event, or one of each for the whole bundle.
+const bundleBatchSize = 500
+
+// toEventRow flattens an Event into the column-level row
+// eventstore.CopyInsertEvents expects - the same embedded-struct-to-columns
+// mapping gorm does for h.db.CreateInBatches in IngestEvents, done by hand
+// here since COPY FROM bypasses gorm's own row marshaling.
+func toEventRow(e Event) eventstore.EventRow {
+	row := eventstore.EventRow{
+		Timestamp: e.Timestamp,
+		TenantID:  e.TenantID,
+		HostID:    e.HostID,
+		UserID:    e.User.ID,
+		UserEmail: e.User.Email,
+		UserDept:  e.User.Dept,
+		OSFamily:  e.OS.Family,
+		OSVersion: e.OS.Version,
+		OSArch:    e.OS.Arch,
+		EventType: e.Event.Type,
+		EventID:   e.Event.ID,
+		EventCat:  e.Event.Category,
+		Labels:    e.Labels,
+		RiskHints: e.RiskHints,
+		AgentVer:  e.Agent.Version,
+		AgentMode: e.Agent.Mode,
+		AgentHost: e.Agent.Hostname,
+		SessionID: e.SessionID,
+		CgroupID:  e.CgroupID,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+	if e.Process != nil {
+		row.ProcName = &e.Process.Name
+		row.ProcPPID = &e.Process.PPID
+		row.ProcPID = &e.Process.PID
+		row.ProcHash = e.Process.Hash
+	}
+	if e.Network != nil {
+		row.NetSrcIP = e.Network.SrcIP
+		row.NetDstIP = &e.Network.DstIP
+		row.NetDstPort = &e.Network.DstPort
+		row.NetProtocol = &e.Network.Protocol
+		row.NetDomain = e.Network.Domain
+	}
+	if e.File != nil {
+		row.FilePath = &e.File.Path
+		row.FileOperation = &e.File.Operation
+		row.FileHash = e.File.Hash
+	}
+	return row
+}
+
+// POST /v1/events/bundle - streaming batch ingest for an endpoint agent's
+// offline backlog: a single request body framed per internal/bundle (a
+// META header, an NDJSON EVENTS section, and an optional FILES section of
+// sample artifact blobs) decoded and COPY-inserted into the partitioned
+// events table as it arrives, rather than bound into one big []Event the
+// way IngestEvents does. Lets an agent batch hours of offline telemetry
+// over a single gzip+TLS stream instead of one HTTP request per event.
+func (h *EventHandler) IngestEventBundle(c *gin.Context) {
+	tenantID, tenantFromCert := c.Get("agent_tenant_id")
+	hostID, hostFromCert := c.Get("agent_host_id")
+
+	sessionCache := make(map[string]string)
+	pending := make([]Event, 0, bundleBatchSize)
+	total := 0
+
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		if h.eventStore == nil {
+			return errs.Internal("events.bundle.no_store", "event store is not configured")
+		}
+		rows := make([]eventstore.EventRow, len(pending))
+		for i, event := range pending {
+			rows[i] = toEventRow(event)
+		}
+		if _, err := h.eventStore.CopyInsertEvents(c.Request.Context(), rows); err != nil {
+			h.logger.WithError(err).Error("Failed to copy-insert event bundle batch")
+			return errs.Internal("events.bundle.insert_failed", "failed to insert event batch").Wrap(err)
+		}
+		batch := append([]Event(nil), pending...)
+		go h.processEventsForAlerts(batch)
+		pending = pending[:0]
+		return nil
+	}
+
+	var ingestErr error
+	handler := bundle.Handler{
+		OnMeta: func(meta bundle.Meta) error {
+			h.logger.WithFields(logrus.Fields{
+				"tenant_id": meta.TenantID,
+				"host_id":   meta.HostID,
+				"agent_ver": meta.AgentVer,
+			}).Info("Receiving event bundle")
+			return nil
+		},
+		OnEvent: func(raw json.RawMessage) error {
+			var event Event
+			if err := json.Unmarshal(raw, &event); err != nil {
+				return errs.BadRequest("events.bundle.invalid_event", "invalid event in EVENTS section").Wrap(err)
+			}
+			event.ID = 0
+			event.CreatedAt = time.Now()
+			event.UpdatedAt = time.Now()
+			if tenantFromCert {
+				event.TenantID = tenantID.(string)
+			}
+			if hostFromCert {
+				event.HostID = hostID.(string)
+			}
+			if event.SessionID == "" && event.CgroupID != 0 {
+				if sessionID, ok := h.resolveSessionID(event.HostID, event.CgroupID, sessionCache); ok {
+					event.SessionID = sessionID
+				}
+			}
+			if hints := h.communityBlocklistHints(event.TenantID, &event); len(hints) > 0 {
+				event.RiskHints = append(event.RiskHints, hints...)
+			}
+
+			pending = append(pending, event)
+			total++
+			if len(pending) >= bundleBatchSize {
+				if err := flush(); err != nil {
+					ingestErr = err
+					return err
+				}
+			}
+			return nil
+		},
+		OnFile: func(blob bundle.FileBlob) error {
+			if h.samples == nil {
+				return nil
+			}
+			if err := h.samples.Save(blob); err != nil {
+				h.logger.WithError(err).WithField("hash", blob.Hash).Warn("Failed to persist bundle sample artifact")
+			}
+			return nil
+		},
+	}
+
+	if err := bundle.Parse(c.Request.Body, handler); err != nil {
+		if ingestErr != nil {
+			c.Error(ingestErr)
+			return
+		}
+		h.logger.WithError(err).Error("Failed to parse event bundle")
+		c.Error(errs.BadRequest("events.bundle.invalid_format", "invalid bundle format").Wrap(err))
+		return
+	}
+	if err := flush(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.logger.WithField("count", total).Info("Successfully ingested event bundle")
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "count": total})
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// config gathers every INSEC_-prefixed environment variable (with CLI flag
+// overrides) this server needs to start, matching cmd/insec-migrate's
+// flag.String(..., os.Getenv("INSEC_..."), ...) convention rather than
+// introducing a second configuration mechanism just for this binary.
+type config struct {
+	// dsn is the Postgres connection string. Required.
+	dsn string
+	// jwtSecret signs every access/refresh token auth.AuthService issues.
+	// Required.
+	jwtSecret string
+	// listenAddr is the TLS listener's bind address.
+	listenAddr string
+	// tlsCertPath/tlsKeyPath are the server's own TLS certificate and key -
+	// distinct from the agent CA below, which signs client certs, not the
+	// server's own. Required.
+	tlsCertPath string
+	tlsKeyPath  string
+	// agentCACertPath/agentCAKeyPath root the internal agent CA
+	// (internal/agentauth) that signs short-lived agent client
+	// certificates. Required: without it, no agent can ever enroll.
+	agentCACertPath string
+	agentCAKeyPath  string
+	// agentCertTTL is how long a freshly signed agent certificate remains
+	// valid before it must be renewed via POST /v1/agents/renew.
+	agentCertTTL time.Duration
+	// redisAddr, if set, moves token revocation, rate limiting, and
+	// scenario bucket state onto Redis so they're shared across replicas;
+	// left empty this server runs every one of those in-memory, correct
+	// only for a single instance.
+	redisAddr string
+	// auditLogPath appends every audit.Entry to this file as the durable
+	// half of the hash chain; defaults to the same path
+	// EventHandler.VerifyAuditChain falls back to, so "verify with no
+	// ?file= override" checks the file this server actually wrote.
+	auditLogPath string
+	// auditSinks selects which audit.Sink backends buildDeps fans entries
+	// out to, e.g. "file,syslog". "file" is always included even if
+	// omitted, since auditLogPath's default is the path VerifyAuditChain
+	// reads when no ?file= is given.
+	auditSinks []string
+	// auditSyslogAddr, if set, enables audit.SyslogSink dialing this
+	// "network:raddr" address (e.g. "udp:localhost:514") when "syslog" is
+	// in auditSinks.
+	auditSyslogAddr string
+	// auditWebhookURL, if set, enables audit.WebhookSink posting to this
+	// URL when "webhook" is in auditSinks.
+	auditWebhookURL string
+	// auditStrictChain makes audit.NewLoggerVerifyingChain refuse to start
+	// the server when the existing audit file's hash chain is already
+	// broken, instead of just logging a warning and continuing.
+	auditStrictChain bool
+	// sessionTarballDir is where SessionHandler.ExportTimeline additionally
+	// persists a copy of each exported artifact. Empty disables that
+	// persistence.
+	sessionTarballDir string
+	// bundleSampleDir is where IngestEventBundle's attached file samples
+	// are written to disk.
+	bundleSampleDir string
+}
+
+// loadConfig reads every INSEC_-prefixed setting this server needs,
+// allowing args (typically os.Args[1:]) to override each one by flag -
+// the same shape as cmd/insec-migrate's flag/env split.
+func loadConfig(args []string) (*config, error) {
+	fs := flag.NewFlagSet("insec-server", flag.ContinueOnError)
+	cfg := &config{}
+	fs.StringVar(&cfg.dsn, "dsn", os.Getenv("INSEC_DATABASE_URL"), "database connection string (default: $INSEC_DATABASE_URL)")
+	fs.StringVar(&cfg.jwtSecret, "jwt-secret", os.Getenv("INSEC_JWT_SECRET"), "JWT signing secret (default: $INSEC_JWT_SECRET)")
+	fs.StringVar(&cfg.listenAddr, "listen", envOrDefault("INSEC_LISTEN_ADDR", ":8443"), "TLS listen address")
+	fs.StringVar(&cfg.tlsCertPath, "tls-cert", os.Getenv("INSEC_TLS_CERT_PATH"), "server TLS certificate path (default: $INSEC_TLS_CERT_PATH)")
+	fs.StringVar(&cfg.tlsKeyPath, "tls-key", os.Getenv("INSEC_TLS_KEY_PATH"), "server TLS key path (default: $INSEC_TLS_KEY_PATH)")
+	fs.StringVar(&cfg.agentCACertPath, "agent-ca-cert", os.Getenv("INSEC_AGENT_CA_CERT_PATH"), "agent CA certificate path (default: $INSEC_AGENT_CA_CERT_PATH)")
+	fs.StringVar(&cfg.agentCAKeyPath, "agent-ca-key", os.Getenv("INSEC_AGENT_CA_KEY_PATH"), "agent CA key path (default: $INSEC_AGENT_CA_KEY_PATH)")
+	fs.StringVar(&cfg.redisAddr, "redis-addr", os.Getenv("INSEC_REDIS_ADDR"), "Redis address for shared token/rate-limit/scenario state (default: $INSEC_REDIS_ADDR, empty disables)")
+	fs.StringVar(&cfg.auditLogPath, "audit-log", envOrDefault("INSEC_AUDIT_LOG_PATH", "/var/log/insec/audit.ndjson"), "audit log file path (default: $INSEC_AUDIT_LOG_PATH; matches VerifyAuditChain's own fallback path)")
+	var auditSinksStr string
+	fs.StringVar(&auditSinksStr, "audit-sinks", envOrDefault("INSEC_AUDIT_SINKS", "file"), "comma-separated audit sinks to fan entries out to: file,syslog,webhook (default: $INSEC_AUDIT_SINKS)")
+	fs.StringVar(&cfg.auditSyslogAddr, "audit-syslog-addr", os.Getenv("INSEC_AUDIT_SYSLOG_ADDR"), "network:raddr for the syslog audit sink, e.g. udp:localhost:514 (default: $INSEC_AUDIT_SYSLOG_ADDR; required if audit-sinks includes syslog)")
+	fs.StringVar(&cfg.auditWebhookURL, "audit-webhook-url", os.Getenv("INSEC_AUDIT_WEBHOOK_URL"), "URL for the webhook audit sink (default: $INSEC_AUDIT_WEBHOOK_URL; required if audit-sinks includes webhook)")
+	fs.BoolVar(&cfg.auditStrictChain, "audit-strict-chain", envOrDefault("INSEC_AUDIT_STRICT_CHAIN", "") == "true", "refuse to start if the existing audit log's hash chain is already broken, instead of warning and continuing (default: $INSEC_AUDIT_STRICT_CHAIN)")
+	fs.StringVar(&cfg.sessionTarballDir, "session-tarball-dir", os.Getenv("INSEC_SESSION_TARBALL_DIR"), "directory for persisted session export tarballs (default: $INSEC_SESSION_TARBALL_DIR, empty disables)")
+	fs.StringVar(&cfg.bundleSampleDir, "bundle-sample-dir", envOrDefault("INSEC_BUNDLE_SAMPLE_DIR", "./data/bundle-samples"), "directory for event bundle file samples")
+
+	var agentCertTTLStr string
+	fs.StringVar(&agentCertTTLStr, "agent-cert-ttl", envOrDefault("INSEC_AGENT_CERT_TTL", "168h"), "agent certificate lifetime")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	ttl, err := time.ParseDuration(agentCertTTLStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -agent-cert-ttl %q: %w", agentCertTTLStr, err)
+	}
+	cfg.agentCertTTL = ttl
+
+	for _, s := range strings.Split(auditSinksStr, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			cfg.auditSinks = append(cfg.auditSinks, s)
+		}
+	}
+
+	if cfg.dsn == "" {
+		return nil, fmt.Errorf("-dsn is required (or set INSEC_DATABASE_URL)")
+	}
+	if cfg.jwtSecret == "" {
+		return nil, fmt.Errorf("-jwt-secret is required (or set INSEC_JWT_SECRET)")
+	}
+	if cfg.tlsCertPath == "" || cfg.tlsKeyPath == "" {
+		return nil, fmt.Errorf("-tls-cert and -tls-key are required (or set INSEC_TLS_CERT_PATH/INSEC_TLS_KEY_PATH)")
+	}
+	if cfg.agentCACertPath == "" || cfg.agentCAKeyPath == "" {
+		return nil, fmt.Errorf("-agent-ca-cert and -agent-ca-key are required (or set INSEC_AGENT_CA_CERT_PATH/INSEC_AGENT_CA_KEY_PATH)")
+	}
+
+	return cfg, nil
+}
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
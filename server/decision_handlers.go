@@ -0,0 +1,327 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"insec/internal/audit"
+	"insec/internal/errs"
+	"insec/internal/models"
+)
+
+// defaultDecisionTTL is used when a decision request omits duration_seconds.
+const defaultDecisionTTL = 4 * time.Hour
+
+// DecisionHandler exposes /v1/decisions: a queryable, revocable ledger of
+// enforcement actions (ban, throttle, isolate, revoke_token), decoupling
+// "what was detected" (Alert) from "what we did about it" (Decision). It
+// also serves the bouncer-style pull endpoint agents use to enforce
+// decisions locally, mirroring the community hub's own push/pull shape in
+// internal/intel.
+type DecisionHandler struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+	audit  *audit.Logger
+}
+
+// NewDecisionHandler wires a DecisionHandler onto db.
+func NewDecisionHandler(db *gorm.DB, logger *logrus.Logger, auditLogger *audit.Logger) *DecisionHandler {
+	return &DecisionHandler{db: db, logger: logger, audit: auditLogger}
+}
+
+// logAudit records a CREATE/DELETE against a decision in the hash-chained
+// audit trail. Failures are logged but never block the HTTP response,
+// matching EventHandler.logAudit's convention.
+func (h *DecisionHandler) logAudit(c *gin.Context, operation, resource string, details map[string]interface{}) {
+	if h.audit == nil {
+		return
+	}
+	actor, _ := c.Get("user_id")
+	actorID, _ := actor.(string)
+	details = withImpersonationContext(c, details)
+	entry := audit.Entry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Actor:     actorID,
+		Operation: operation,
+		Resource:  resource,
+		Details:   details,
+	}
+	if err := h.audit.Log(c.Request.Context(), entry); err != nil {
+		h.logger.WithError(err).Error("Failed to write audit log entry")
+	}
+}
+
+type createDecisionRequest struct {
+	Type            string `json:"type" binding:"required"`
+	Scope           string `json:"scope" binding:"required"`
+	Value           string `json:"value" binding:"required"`
+	Origin          string `json:"origin"`
+	Reason          string `json:"reason,omitempty"`
+	Confidence      int    `json:"confidence,omitempty"`
+	DurationSeconds int    `json:"duration_seconds,omitempty"`
+}
+
+var validDecisionTypes = map[string]bool{
+	models.DecisionBan:         true,
+	models.DecisionThrottle:    true,
+	models.DecisionIsolate:     true,
+	models.DecisionRevokeToken: true,
+}
+
+var validDecisionScopes = map[string]bool{
+	models.ScopeIP:      true,
+	models.ScopeRange:   true,
+	models.ScopeUser:    true,
+	models.ScopeHost:    true,
+	models.ScopeSession: true,
+	models.ScopeDomain:  true,
+	models.ScopeHash:    true,
+}
+
+func (r createDecisionRequest) validate() *errs.Error {
+	if !validDecisionTypes[r.Type] {
+		return errs.BadRequest("decision.type.invalid", "type must be one of ban, throttle, isolate, revoke_token")
+	}
+	if !validDecisionScopes[r.Scope] {
+		return errs.BadRequest("decision.scope.invalid", "scope must be one of ip, range, user, host, session, domain, hash")
+	}
+	if r.Origin == "" {
+		return errs.BadRequest("decision.origin.required", "origin is required (rule ID, alert ID, or \"manual\")")
+	}
+	return nil
+}
+
+func (r createDecisionRequest) toDecision(tenantID string) models.Decision {
+	ttl := defaultDecisionTTL
+	if r.DurationSeconds > 0 {
+		ttl = time.Duration(r.DurationSeconds) * time.Second
+	}
+	now := time.Now()
+	return models.Decision{
+		ID:         uuid.New().String(),
+		Type:       r.Type,
+		Scope:      r.Scope,
+		Value:      r.Value,
+		Origin:     r.Origin,
+		Reason:     r.Reason,
+		Confidence: r.Confidence,
+		TenantID:   tenantID,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(ttl),
+	}
+}
+
+// CreateDecisions handles POST /v1/decisions. The body is always an array,
+// even for a single decision, matching IngestEvents' batch convention.
+func (h *DecisionHandler) CreateDecisions(c *gin.Context) {
+	var reqs []createDecisionRequest
+	if err := c.ShouldBindJSON(&reqs); err != nil {
+		c.Error(errs.BadRequest("decision.body.invalid", "invalid decision format").Wrap(err))
+		return
+	}
+	if len(reqs) == 0 {
+		c.Error(errs.BadRequest("decision.body.empty", "at least one decision is required"))
+		return
+	}
+
+	tenantID, _ := c.Get("tenant_id")
+	tid, _ := tenantID.(string)
+
+	decisions := make([]models.Decision, 0, len(reqs))
+	for _, req := range reqs {
+		if apiErr := req.validate(); apiErr != nil {
+			c.Error(apiErr)
+			return
+		}
+		decisions = append(decisions, req.toDecision(tid))
+	}
+
+	if err := h.db.Create(&decisions).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to create decisions")
+		c.Error(errs.Internal("decision.create.failed", "failed to create decisions").Wrap(err))
+		return
+	}
+
+	for _, d := range decisions {
+		h.logAudit(c, "CREATE", "decision", map[string]interface{}{
+			"decision_id": d.ID, "type": d.Type, "scope": d.Scope, "value": d.Value, "origin": d.Origin,
+		})
+	}
+	c.JSON(http.StatusCreated, gin.H{"decisions": decisions})
+}
+
+// ListDecisions handles GET /v1/decisions with optional scope, value,
+// origin, and active filters.
+func (h *DecisionHandler) ListDecisions(c *gin.Context) {
+	var decisions []models.Decision
+	query := h.db
+
+	if tenantID := c.Query("tenant_id"); tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+	if scope := c.Query("scope"); scope != "" {
+		query = query.Where("scope = ?", scope)
+	}
+	if value := c.Query("value"); value != "" {
+		query = query.Where("value = ?", value)
+	}
+	if origin := c.Query("origin"); origin != "" {
+		query = query.Where("origin = ?", origin)
+	}
+	if active := c.Query("active"); active != "" {
+		if active == "true" {
+			query = query.Where("expires_at > ?", time.Now())
+		} else {
+			query = query.Where("expires_at <= ?", time.Now())
+		}
+	}
+
+	if err := query.Order("created_at DESC").Find(&decisions).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to query decisions")
+		c.Error(errs.Internal("decisions.query.failed", "failed to query decisions").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"decisions": decisions})
+}
+
+// DeleteDecision handles DELETE /v1/decisions/:id.
+func (h *DecisionHandler) DeleteDecision(c *gin.Context) {
+	id := c.Param("id")
+	result := h.db.Delete(&models.Decision{}, "id = ?", id)
+	if result.Error != nil {
+		h.logger.WithError(result.Error).Error("Failed to delete decision")
+		c.Error(errs.Internal("decision.delete.failed", "failed to delete decision").Wrap(result.Error))
+		return
+	}
+
+	h.logAudit(c, "DELETE", "decision", map[string]interface{}{"decision_id": id})
+	c.JSON(http.StatusOK, gin.H{"nbDeleted": strconv.FormatInt(result.RowsAffected, 10)})
+}
+
+// BulkDeleteDecisions handles DELETE /v1/decisions with query-based bulk
+// removal: either ?scope=X&value=Y for an exact match, or
+// ?range=1.2.3.0/24&contains=false to delete every ip-scoped decision
+// contained within (contains=true, the default) or containing (contains=
+// false) the given CIDR - the same two bulk-unban shapes cscli exposes.
+func (h *DecisionHandler) BulkDeleteDecisions(c *gin.Context) {
+	if rangeParam := c.Query("range"); rangeParam != "" {
+		h.bulkDeleteByRange(c, rangeParam)
+		return
+	}
+
+	scope := c.Query("scope")
+	value := c.Query("value")
+	if scope == "" || value == "" {
+		c.Error(errs.BadRequest("decision.bulk_delete.filter_required", "scope+value or range is required"))
+		return
+	}
+
+	result := h.db.Where("scope = ? AND value = ?", scope, value).Delete(&models.Decision{})
+	if result.Error != nil {
+		h.logger.WithError(result.Error).Error("Failed to bulk delete decisions")
+		c.Error(errs.Internal("decision.bulk_delete.failed", "failed to delete decisions").Wrap(result.Error))
+		return
+	}
+
+	h.logAudit(c, "DELETE", "decision", map[string]interface{}{"scope": scope, "value": value, "count": result.RowsAffected})
+	c.JSON(http.StatusOK, gin.H{"nbDeleted": strconv.FormatInt(result.RowsAffected, 10)})
+}
+
+func (h *DecisionHandler) bulkDeleteByRange(c *gin.Context, rangeParam string) {
+	_, ipnet, err := net.ParseCIDR(rangeParam)
+	if err != nil {
+		c.Error(errs.BadRequest("decision.bulk_delete.range_invalid", "range must be a valid CIDR").Wrap(err))
+		return
+	}
+	contains := c.DefaultQuery("contains", "true") == "true"
+
+	var candidates []models.Decision
+	if err := h.db.Where("scope = ?", models.ScopeIP).Find(&candidates).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to query decisions for range delete")
+		c.Error(errs.Internal("decision.bulk_delete.failed", "failed to delete decisions").Wrap(err))
+		return
+	}
+
+	var toDelete []string
+	for _, d := range candidates {
+		ip := net.ParseIP(d.Value)
+		if ip == nil {
+			continue
+		}
+		if contains && ipnet.Contains(ip) {
+			toDelete = append(toDelete, d.ID)
+		}
+		if !contains {
+			// A single ip-scoped address contains the range only when the
+			// range is exactly that one address.
+			if ones, bits := ipnet.Mask.Size(); ones == bits && ipnet.IP.Equal(ip) {
+				toDelete = append(toDelete, d.ID)
+			}
+		}
+	}
+	if len(toDelete) == 0 {
+		c.JSON(http.StatusOK, gin.H{"nbDeleted": "0"})
+		return
+	}
+
+	result := h.db.Where("id IN ?", toDelete).Delete(&models.Decision{})
+	if result.Error != nil {
+		h.logger.WithError(result.Error).Error("Failed to bulk delete decisions by range")
+		c.Error(errs.Internal("decision.bulk_delete.failed", "failed to delete decisions").Wrap(result.Error))
+		return
+	}
+
+	h.logAudit(c, "DELETE", "decision", map[string]interface{}{"range": rangeParam, "contains": contains, "count": result.RowsAffected})
+	c.JSON(http.StatusOK, gin.H{"nbDeleted": strconv.FormatInt(result.RowsAffected, 10)})
+}
+
+// ActiveDecisionsForAgent handles GET /v1/agents/decisions - the
+// bouncer-pull endpoint an agent polls to learn what it should be
+// enforcing locally (blocking an IP, killing a session), mirroring the
+// community hub's own pull model in internal/intel.
+func (h *DecisionHandler) ActiveDecisionsForAgent(c *gin.Context) {
+	tenantID, _ := c.Get("agent_tenant_id")
+	tid, _ := tenantID.(string)
+
+	var decisions []models.Decision
+	if err := h.db.Where("tenant_id = ? AND expires_at > ?", tid, time.Now()).Find(&decisions).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to query active decisions")
+		c.Error(errs.Internal("decisions.agent_pull.failed", "failed to query active decisions").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"decisions": decisions})
+}
+
+// RunDecisionReaper periodically deletes expired decisions so the ledger
+// doesn't grow unbounded with stale entries; it blocks until stop is
+// closed, meant to be run in its own goroutine from main.
+func RunDecisionReaper(db *gorm.DB, logger *logrus.Logger, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			result := db.Where("expires_at <= ?", time.Now()).Delete(&models.Decision{})
+			if result.Error != nil {
+				logger.WithError(result.Error).Error("Decision reaper: failed to expire decisions")
+				continue
+			}
+			if result.RowsAffected > 0 {
+				logger.WithField("count", result.RowsAffected).Info("Decision reaper: expired decisions")
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"insec/internal/errs"
+	"insec/internal/eventstore"
+)
+
+// EventStoreHandler exposes admin endpoints to inspect and override a
+// tenant's event retention policy, backed by eventstore.EventStore.
+type EventStoreHandler struct {
+	store  *eventstore.EventStore
+	logger *logrus.Logger
+}
+
+// NewEventStoreHandler wires an EventStoreHandler onto store.
+func NewEventStoreHandler(store *eventstore.EventStore, logger *logrus.Logger) *EventStoreHandler {
+	return &EventStoreHandler{store: store, logger: logger}
+}
+
+// GetRetention handles GET /v1/admin/tenants/:id/retention.
+func (h *EventStoreHandler) GetRetention(c *gin.Context) {
+	tenantID := c.Param("id")
+	retention, err := h.store.Retention(tenantID)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load tenant retention policy")
+		c.Error(errs.Internal("retention.get.failed", "failed to load retention policy").Wrap(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"tenant_id":      tenantID,
+		"retention_days": retention.Hours() / 24,
+	})
+}
+
+type setRetentionRequest struct {
+	RetentionDays float64 `json:"retention_days" binding:"required"`
+}
+
+// SetRetention handles PUT /v1/admin/tenants/:id/retention.
+func (h *EventStoreHandler) SetRetention(c *gin.Context) {
+	tenantID := c.Param("id")
+	var req setRetentionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("retention.set.invalid_body", "invalid request").Wrap(err))
+		return
+	}
+	if req.RetentionDays <= 0 {
+		c.Error(errs.BadRequest("retention.set.invalid_days", "retention_days must be positive"))
+		return
+	}
+
+	if err := h.store.SetRetention(tenantID, req.RetentionDays); err != nil {
+		h.logger.WithError(err).Error("Failed to set tenant retention policy")
+		c.Error(errs.Internal("retention.set.failed", "failed to set retention policy").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tenant_id":      tenantID,
+		"retention_days": strconv.FormatFloat(req.RetentionDays, 'f', -1, 64),
+	})
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -10,17 +13,94 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+
+	"insec/internal/audit"
+	"insec/internal/bundle"
+	"insec/internal/errs"
+	"insec/internal/eventstore"
+	"insec/internal/intel"
+	"insec/internal/models"
+	"insec/internal/notifications"
+	"insec/internal/ruleengine"
+	"insec/internal/scenario"
+	"insec/internal/ueba"
 )
 
 type EventHandler struct {
-	db     *gorm.DB
-	logger *logrus.Logger
+	db         *gorm.DB
+	logger     *logrus.Logger
+	audit      *audit.Logger
+	decisions  intel.DecisionStore
+	notifier   *notifications.Dispatcher
+	scenarios  *scenario.Engine
+	rules      *ruleengine.Dispatcher
+	ueba       *ueba.Engine
+	eventStore *eventstore.EventStore
+	samples    bundle.SampleStore
 }
 
-func NewEventHandler(db *gorm.DB, logger *logrus.Logger) *EventHandler {
+func NewEventHandler(db *gorm.DB, logger *logrus.Logger, auditLogger *audit.Logger, decisions intel.DecisionStore, notifier *notifications.Dispatcher, scenarios *scenario.Engine, rules *ruleengine.Dispatcher, uebaEngine *ueba.Engine, eventStore *eventstore.EventStore, samples bundle.SampleStore) *EventHandler {
 	return &EventHandler{
-		db:     db,
-		logger: logger,
+		db:         db,
+		logger:     logger,
+		audit:      auditLogger,
+		decisions:  decisions,
+		notifier:   notifier,
+		scenarios:  scenarios,
+		rules:      rules,
+		ueba:       uebaEngine,
+		eventStore: eventStore,
+		samples:    samples,
+	}
+}
+
+// communityBlocklistHints checks an event's network/process indicators
+// against the ingesting tenant's pulled Decisions (internal/intel),
+// returning the risk hints to append for whatever matched. A tenant that
+// never pulled signals, or pulled none matching this event, gets none.
+func (h *EventHandler) communityBlocklistHints(tenantID string, e *Event) []string {
+	if h.decisions == nil {
+		return nil
+	}
+	var hints []string
+	if e.Network != nil {
+		if _, ok := h.decisions.Match(tenantID, models.ScopeIP, e.Network.DstIP); ok {
+			hints = append(hints, "community_blocklist_match:ip")
+		}
+		if e.Network.Domain != nil {
+			if _, ok := h.decisions.Match(tenantID, models.ScopeDomain, *e.Network.Domain); ok {
+				hints = append(hints, "community_blocklist_match:domain")
+			}
+		}
+	}
+	if e.Process != nil && e.Process.Hash != nil {
+		if _, ok := h.decisions.Match(tenantID, models.ScopeHash, *e.Process.Hash); ok {
+			hints = append(hints, "community_blocklist_match:hash")
+		}
+	}
+	return hints
+}
+
+// logAudit records a CREATE/UPDATE/DELETE against an alert in the hash-chained
+// audit trail. Failures are logged but never block the HTTP response — a
+// down webhook sink must not make alert management unavailable.
+func (h *EventHandler) logAudit(c *gin.Context, operation, resource string, details map[string]interface{}) {
+	if h.audit == nil {
+		return
+	}
+	actor, _ := c.Get("user_id")
+	actorID, _ := actor.(string)
+	details = withImpersonationContext(c, details)
+	entry := audit.Entry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Actor:     actorID,
+		Operation: operation,
+		Resource:  resource,
+		Details:   details,
+	}
+	if err := h.audit.Log(c.Request.Context(), entry); err != nil {
+		h.logger.WithError(err).Error("Failed to write audit log entry")
 	}
 }
 
@@ -29,21 +109,48 @@ func (h *EventHandler) IngestEvents(c *gin.Context) {
 	var events []Event
 	if err := c.ShouldBindJSON(&events); err != nil {
 		h.logger.WithError(err).Error("Failed to bind events JSON")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		c.Error(errs.BadRequest("events.body.invalid_json", "invalid JSON format").Wrap(err))
 		return
 	}
 
+	// Trust the verified agent certificate over the request body: once
+	// agentauth.RequireAgentCert has run, tenant_id/host_id/agent_id live in
+	// the gin context and must win over whatever the JSON payload claims,
+	// otherwise a compromised agent could spoof another tenant's events.
+	tenantID, tenantFromCert := c.Get("agent_tenant_id")
+	hostID, hostFromCert := c.Get("agent_host_id")
+
 	// Process events in batch
+	sessionCache := make(map[string]string)
 	for i := range events {
 		events[i].ID = 0 // Let GORM auto-generate
 		events[i].CreatedAt = time.Now()
 		events[i].UpdatedAt = time.Now()
+		if tenantFromCert {
+			events[i].TenantID = tenantID.(string)
+		}
+		if hostFromCert {
+			events[i].HostID = hostID.(string)
+		}
+		// A BPF-sourced event (internal/agent/bpf) carries a CgroupID but no
+		// SessionID of its own - the agent only knows the numeric cgroup id,
+		// not the session UUID minted by POST /v1/sessions, so resolve it
+		// here against whatever session that host currently has open on
+		// that cgroup.
+		if events[i].SessionID == "" && events[i].CgroupID != 0 {
+			if sessionID, ok := h.resolveSessionID(events[i].HostID, events[i].CgroupID, sessionCache); ok {
+				events[i].SessionID = sessionID
+			}
+		}
+		if hints := h.communityBlocklistHints(events[i].TenantID, &events[i]); len(hints) > 0 {
+			events[i].RiskHints = append(events[i].RiskHints, hints...)
+		}
 	}
 
 	// Bulk insert
-	if err := h.db.CreateInBatch(&events, 100).Error; err != nil {
+	if err := h.db.CreateInBatches(&events, 100).Error; err != nil {
 		h.logger.WithError(err).Error("Failed to insert events")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process events"})
+		c.Error(errs.Internal("events.ingest.failed", "failed to process events").Wrap(err))
 		return
 	}
 
@@ -58,6 +165,25 @@ func (h *EventHandler) IngestEvents(c *gin.Context) {
 	})
 }
 
+// resolveSessionID looks up the open Session (see server/session_handlers.go)
+// that owns cgroupID on hostID, caching misses and hits within a single
+// ingest batch so a burst of BPF events from the same session only costs
+// one query.
+func (h *EventHandler) resolveSessionID(hostID string, cgroupID uint64, cache map[string]string) (string, bool) {
+	key := fmt.Sprintf("%s:%d", hostID, cgroupID)
+	if sessionID, cached := cache[key]; cached {
+		return sessionID, sessionID != ""
+	}
+
+	var session Session
+	if err := h.db.Where("host_id = ? AND cgroup_id = ? AND closed_at IS NULL", hostID, cgroupID).First(&session).Error; err != nil {
+		cache[key] = ""
+		return "", false
+	}
+	cache[key] = session.ID
+	return session.ID, true
+}
+
 // GET /v1/events - Query events with filtering
 func (h *EventHandler) GetEvents(c *gin.Context) {
 	var events []Event
@@ -91,7 +217,7 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 
 	if err := query.Offset(offset).Limit(limit).Find(&events).Error; err != nil {
 		h.logger.WithError(err).Error("Failed to query events")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query events"})
+		c.Error(errs.Internal("events.query.failed", "failed to query events").Wrap(err))
 		return
 	}
 
@@ -102,6 +228,162 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 	})
 }
 
+// GET /v1/sessions/:id/events - Return the BPF-correlated event stream for
+// one session (everything tagged with this cgroup-derived session_id),
+// ordered by timestamp so callers can reconstruct a single shell's activity.
+func (h *EventHandler) GetSessionEvents(c *gin.Context) {
+	sessionID := c.Param("id")
+
+	var events []Event
+	if err := h.db.Where("session_id = ?", sessionID).Order("timestamp ASC").Find(&events).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to query session events")
+		c.Error(errs.Internal("sessions.query.failed", "failed to query session events").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sessionID,
+		"events":     events,
+	})
+}
+
+var validSeverities = map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+
+var emailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+
+type createAlertRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	Category    string `json:"category,omitempty"`
+	AssignedTo  string `json:"assigned_to,omitempty"`
+}
+
+// validate returns the first stable errs.Error code this request violates,
+// or nil if it's acceptable. Checked in a fixed order so the same invalid
+// payload always produces the same code.
+func (r createAlertRequest) validate() *errs.Error {
+	switch {
+	case r.Title == "":
+		return errs.BadRequest("alert.title.required", "title is required")
+	case len(r.Title) > 200:
+		return errs.BadRequest("alert.title.too_long", "title must be at most 200 characters")
+	case r.Severity != "" && !validSeverities[r.Severity]:
+		return errs.BadRequest("alert.severity.invalid", "severity must be one of low, medium, high, critical")
+	case len(r.Description) < 10:
+		return errs.BadRequest("alert.description.too_short", "description must be at least 10 characters")
+	case r.AssignedTo != "" && !emailPattern.MatchString(r.AssignedTo):
+		return errs.BadRequest("alert.assigned_to.invalid_email", "assigned_to must be a valid email address")
+	default:
+		return nil
+	}
+}
+
+// POST /api/v1/alerts - Manually create an alert
+func (h *EventHandler) CreateAlert(c *gin.Context) {
+	var req createAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("alert.body.invalid", "invalid alert format").Wrap(err))
+		return
+	}
+	if apiErr := req.validate(); apiErr != nil {
+		c.Error(apiErr)
+		return
+	}
+
+	alert := Alert{
+		CreatedAt:   time.Now(),
+		Title:       req.Title,
+		Description: req.Description,
+		Severity:    req.Severity,
+		Status:      "open",
+	}
+	if req.AssignedTo != "" {
+		alert.Assignee = &req.AssignedTo
+	}
+
+	if err := h.db.Create(&alert).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to create alert")
+		c.Error(errs.Internal("alert.create.failed", "failed to create alert").Wrap(err))
+		return
+	}
+
+	h.logAudit(c, "CREATE", "alert", map[string]interface{}{"alert_id": alert.ID, "title": alert.Title})
+	c.JSON(http.StatusCreated, alert)
+}
+
+// PUT /api/v1/alerts/:id - Update an alert's status/assignment
+func (h *EventHandler) UpdateAlert(c *gin.Context) {
+	alertID := c.Param("id")
+	var alert Alert
+	if err := h.db.First(&alert, alertID).Error; err != nil {
+		c.Error(errs.NotFound("alert.not_found", "alert not found"))
+		return
+	}
+
+	var update struct {
+		Status   *string `json:"status"`
+		Assignee *string `json:"assignee"`
+	}
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.Error(errs.BadRequest("alert.update.invalid_body", "invalid update payload").Wrap(err))
+		return
+	}
+	if update.Status != nil {
+		alert.Status = *update.Status
+		if *update.Status == "resolved" {
+			now := time.Now()
+			alert.ResolvedAt = &now
+		}
+	}
+	if update.Assignee != nil {
+		alert.Assignee = update.Assignee
+	}
+
+	if err := h.db.Save(&alert).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to update alert")
+		c.Error(errs.Internal("alert.update.failed", "failed to update alert").Wrap(err))
+		return
+	}
+
+	h.logAudit(c, "UPDATE", "alert", map[string]interface{}{"alert_id": alert.ID, "status": alert.Status})
+	c.JSON(http.StatusOK, alert)
+}
+
+// DELETE /api/v1/alerts/:id - Delete an alert
+func (h *EventHandler) DeleteAlert(c *gin.Context) {
+	alertID := c.Param("id")
+	if err := h.db.Delete(&Alert{}, alertID).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to delete alert")
+		c.Error(errs.Internal("alert.delete.failed", "failed to delete alert").Wrap(err))
+		return
+	}
+
+	h.logAudit(c, "DELETE", "alert", map[string]interface{}{"alert_id": alertID})
+	c.JSON(http.StatusOK, gin.H{"status": "deleted", "alert_id": alertID})
+}
+
+// GET /api/v1/admin/audit/verify - Walk the file audit sink and report the
+// first broken hash-chain link, if any.
+func (h *EventHandler) VerifyAuditChain(c *gin.Context) {
+	auditFilePath := c.Query("file")
+	if auditFilePath == "" {
+		auditFilePath = "/var/log/insec/audit.ndjson"
+	}
+
+	brokenID, _, err := audit.VerifyChain(auditFilePath)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to verify audit chain")
+		c.Error(errs.Internal("audit.verify.failed", "failed to verify audit chain").Wrap(err))
+		return
+	}
+	if brokenID != "" {
+		c.JSON(http.StatusOK, gin.H{"valid": false, "broken_entry_id": brokenID})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"valid": true})
+}
+
 // GET /v1/alerts - Get alerts
 func (h *EventHandler) GetAlerts(c *gin.Context) {
 	var alerts []Alert
@@ -125,7 +407,7 @@ func (h *EventHandler) GetAlerts(c *gin.Context) {
 
 	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&alerts).Error; err != nil {
 		h.logger.WithError(err).Error("Failed to query alerts")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query alerts"})
+		c.Error(errs.Internal("alerts.query.failed", "failed to query alerts").Wrap(err))
 		return
 	}
 
@@ -145,14 +427,14 @@ func (h *EventHandler) ExecuteAlertAction(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&actionReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.Error(errs.BadRequest("alert.action.invalid_body", "invalid request").Wrap(err))
 		return
 	}
 
 	// Find alert
 	var alert Alert
 	if err := h.db.First(&alert, alertID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Alert not found"})
+		c.Error(errs.NotFound("alert.not_found", "alert not found"))
 		return
 	}
 
@@ -177,7 +459,7 @@ func (h *EventHandler) GetRules(c *gin.Context) {
 	var rules []Rule
 	if err := h.db.Find(&rules).Error; err != nil {
 		h.logger.WithError(err).Error("Failed to query rules")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query rules"})
+		c.Error(errs.Internal("rules.query.failed", "failed to query rules").Wrap(err))
 		return
 	}
 
@@ -188,7 +470,7 @@ func (h *EventHandler) GetRules(c *gin.Context) {
 func (h *EventHandler) CreateRule(c *gin.Context) {
 	var rule Rule
 	if err := c.ShouldBindJSON(&rule); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule format"})
+		c.Error(errs.BadRequest("rule.body.invalid", "invalid rule format").Wrap(err))
 		return
 	}
 
@@ -198,58 +480,165 @@ func (h *EventHandler) CreateRule(c *gin.Context) {
 
 	if err := h.db.Create(&rule).Error; err != nil {
 		h.logger.WithError(err).Error("Failed to create rule")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create rule"})
+		c.Error(errs.Internal("rule.create.failed", "failed to create rule").Wrap(err))
 		return
 	}
 
 	h.logger.WithField("rule_id", rule.ID).Info("Created new rule")
+	if h.scenarios != nil {
+		if err := LoadAllScenarios(h.db, h.scenarios, h.logger); err != nil {
+			h.logger.WithError(err).Warn("Failed to reload scenarios after rule creation")
+		}
+	}
+	if h.rules != nil {
+		if err := LoadAllConditionRules(h.db, h.rules); err != nil {
+			h.logger.WithError(err).Warn("Failed to reload condition rules after rule creation")
+		}
+	}
 	c.JSON(http.StatusCreated, rule)
 }
 
-// Process events for rule matching and alert generation
+// Process events for rule matching and alert generation. Bucket-based
+// detection lives in internal/scenario's leaky-bucket engine (loaded from
+// the built-in YAML scenarios plus whatever's been added through
+// GetRules/CreateRule); single-event detection lives in
+// internal/ruleengine's CEL/Sigma dispatcher (loaded by
+// LoadAllConditionRules). Both run against the same flattened event - the
+// scenario engine through env's "event" variable, the dispatcher on the
+// event map itself - and turn their respective matches into an Alert.
 func (h *EventHandler) processEventsForAlerts(events []Event) {
-	// Simplified rule processing - in production, this would be more sophisticated
 	for _, event := range events {
-		// Check for suspicious process patterns
-		if event.Process != nil {
-			suspiciousCmds := []string{"netcat", "ncat", "wget", "curl", "scp", "rclone"}
-			for _, cmd := range event.Process.Cmd {
-				for _, suspicious := range suspiciousCmds {
-					if strings.Contains(cmd, suspicious) {
-						h.createAlert(&event, "Suspicious data transfer tool detected", "high")
-						break
-					}
-				}
+		env, err := eventToScenarioEnv(&event)
+		if err != nil {
+			h.logger.WithError(err).Warn("Failed to flatten event for rule evaluation")
+			continue
+		}
+
+		uebaResult := h.scoreUEBA(&event)
+
+		if h.scenarios != nil {
+			for _, overflow := range h.scenarios.Evaluate(context.Background(), env) {
+				h.createAlert(&event, overflow.Scenario.OverflowTitle, overflow.Scenario.Severity, overflow.Scenario.ID, []string{event.Event.ID}, uebaResult)
 			}
 		}
 
-		// Check for large file operations
-		if event.File != nil && event.File.Size != nil && *event.File.Size > 100*1024*1024 { // 100MB
-			h.createAlert(&event, "Large file operation detected", "medium")
+		if h.rules != nil {
+			eventMap, _ := env["event"].(map[string]interface{})
+			matches, err := h.rules.Evaluate(context.Background(), eventMap)
+			if err != nil {
+				h.logger.WithError(err).Warn("Rule engine evaluation failed")
+				continue
+			}
+			for _, match := range matches {
+				h.createAlert(&event, match.Rule.Title, match.Rule.Severity, match.Rule.ID, match.Evidence, uebaResult)
+			}
 		}
 	}
 }
 
-func (h *EventHandler) createAlert(event *Event, title, severity string) {
+func (h *EventHandler) createAlert(event *Event, title, severity, ruleID string, evidence []string, uebaResult ueba.Result) {
 	alert := Alert{
 		CreatedAt:   time.Now(),
 		Severity:    severity,
 		Title:       title,
 		TenantID:    event.TenantID,
-		RuleID:      "RULE_AUTO",
+		RuleID:      ruleID,
 		RuleVersion: "1.0",
-		UEBAScore:   75,
+		UEBAScore:   int(uebaResult.Score),
 		Entities: map[string]interface{}{
-			"user": event.User.ID,
-			"host": event.HostID,
+			"user":              event.User.ID,
+			"host":              event.HostID,
+			"ueba_contributors": uebaResult.Contributors,
 		},
-		Evidence: []string{event.Event.ID},
+		Evidence: evidence,
 		Status:   "open",
 	}
 
 	if err := h.db.Create(&alert).Error; err != nil {
 		h.logger.WithError(err).Error("Failed to create alert")
-	} else {
-		h.logger.WithField("alert_id", alert.ID).Info("Created alert")
+		return
+	}
+	h.logger.WithField("alert_id", alert.ID).Info("Created alert")
+	h.emitDecisionsForRule(ruleID, event)
+
+	if h.notifier != nil {
+		h.notifier.Dispatch(context.Background(), notifications.Alert{
+			ID:          strconv.FormatUint(uint64(alert.ID), 10),
+			Title:       alert.Title,
+			Severity:    alert.Severity,
+			TenantID:    alert.TenantID,
+			Description: alert.Description,
+			CreatedAt:   alert.CreatedAt,
+		})
+	}
+}
+
+// emitDecisionsForRule looks up ruleID's Rule row and, for every action of
+// the form "<decision type>:<scope>" it configures (e.g. "ban:ip"),
+// creates a Decision scoped to whatever value event carries for that scope.
+// A rule with no matching Rule row, or no decision-shaped actions, emits
+// nothing - decision emission from an alert is opt-in per rule, not
+// automatic.
+func (h *EventHandler) emitDecisionsForRule(ruleID string, event *Event) {
+	var rule Rule
+	if err := h.db.First(&rule, "id = ?", ruleID).Error; err != nil {
+		return
+	}
+
+	var decisions []models.Decision
+	now := time.Now()
+	for _, action := range rule.Actions {
+		decisionType, scope, ok := strings.Cut(action, ":")
+		if !ok || !validDecisionTypes[decisionType] || !validDecisionScopes[scope] {
+			continue
+		}
+		value, ok := decisionScopeValue(scope, event)
+		if !ok {
+			continue
+		}
+		decisions = append(decisions, models.Decision{
+			ID:        uuid.New().String(),
+			Type:      decisionType,
+			Scope:     scope,
+			Value:     value,
+			Origin:    "rule:" + ruleID,
+			Reason:    rule.Name,
+			TenantID:  event.TenantID,
+			CreatedAt: now,
+			ExpiresAt: now.Add(defaultDecisionTTL),
+		})
+	}
+	if len(decisions) == 0 {
+		return
+	}
+
+	if err := h.db.Create(&decisions).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to emit decisions for rule")
+	}
+}
+
+// decisionScopeValue extracts the value an enforceable Decision of the
+// given scope would take from event, e.g. ScopeIP resolves to the event's
+// destination IP. Returns ok=false if event carries nothing usable for
+// that scope.
+func decisionScopeValue(scope string, event *Event) (string, bool) {
+	switch scope {
+	case models.ScopeIP:
+		if event.Network != nil && event.Network.DstIP != "" {
+			return event.Network.DstIP, true
+		}
+	case models.ScopeUser:
+		if event.User.ID != "" {
+			return event.User.ID, true
+		}
+	case models.ScopeHost:
+		if event.HostID != "" {
+			return event.HostID, true
+		}
+	case models.ScopeSession:
+		if event.SessionID != "" {
+			return event.SessionID, true
+		}
 	}
+	return "", false
 }
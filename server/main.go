@@ -1,90 +1,66 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/gin-gonic/gin"
-)
+	"github.com/sirupsen/logrus"
 
-type Event struct {
-	Ts       string `json:"ts"`
-	TenantID string `json:"tenant_id"`
-	HostID   string `json:"host_id"`
-	User     struct {
-		ID    string `json:"id"`
-		Email string `json:"email"`
-		Dept  string `json:"dept"`
-	} `json:"user"`
-	Os struct {
-		Family  string `json:"family"`
-		Version string `json:"version"`
-	} `json:"os"`
-	Event struct {
-		Type string `json:"type"`
-		ID   string `json:"id"`
-	} `json:"event"`
-	Proc struct {
-		Name string   `json:"name"`
-		Cmd  []string `json:"cmd"`
-		Ppid uint32   `json:"ppid"`
-		Hash *string  `json:"hash,omitempty"`
-	} `json:"proc"`
-	Net     interface{} `json:"net,omitempty"`
-	File    interface{} `json:"file,omitempty"`
-	Labels  []string    `json:"labels"`
-	RiskHints []string  `json:"risk_hints"`
-	Agent   struct {
-		Ver  string `json:"ver"`
-		Mode string `json:"mode"`
-	} `json:"agent"`
-}
+	"insec/internal/alertlifecycle"
+	"insec/internal/eventstore"
+	"insec/internal/notifications"
+)
 
 func main() {
-	r := gin.Default()
+	logger := logrus.New()
 
-	// CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
-		}
-		c.Next()
-	})
+	cfg, err := loadConfig(os.Args[1:])
+	if err != nil {
+		logger.WithError(err).Fatal("invalid configuration")
+	}
 
-	r.POST("/v1/events", func(c *gin.Context) {
-		var events []Event
-		if err := c.ShouldBindJSON(&events); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
+	d, err := buildDeps(cfg, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to initialize server dependencies")
+	}
 
-		for _, event := range events {
-			log.Printf("Received event: %+v", event)
-		}
+	stop := make(chan struct{})
+	go eventstore.RunPartitionMaintenance(d.events.eventStore, 1*time.Hour, stop)
+	go notifications.RunRetryLoop(context.Background(), d.events.notifier, 1*time.Minute, stop)
+	go startSLAMonitor(d, alertlifecycle.DefaultMonitorInterval, stop)
 
-		c.JSON(http.StatusOK, gin.H{"status": "ok", "count": len(events)})
-	})
+	router := newRouter(d)
+	tlsConfig, err := newTLSConfig(cfg, d.ca)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to configure TLS")
+	}
 
-	r.GET("/v1/events", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"events": []interface{}{}})
-	})
+	srv := &http.Server{
+		Addr:      cfg.listenAddr,
+		Handler:   router,
+		TLSConfig: tlsConfig,
+	}
 
-	r.GET("/v1/alerts", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"alerts": []interface{}{}})
-	})
+	go func() {
+		logger.WithField("addr", cfg.listenAddr).Info("INSEC server starting")
+		if err := srv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			logger.WithError(err).Fatal("server stopped unexpectedly")
+		}
+	}()
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "healthy",
-			"version": "1.0.0",
-		})
-	})
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
 
-	fmt.Println("INSEC Server starting on :8080")
-	r.Run(":8080")
+	close(stop)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.WithError(err).Error("graceful shutdown failed")
+	}
+	logger.Info("INSEC server stopped")
 }
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"insec/internal/auth"
+	"insec/internal/errs"
+)
+
+// MFAMiddleware requires step-up authentication on sensitive routes: either
+// a fresh X-MFA-Token assertion bound to this exact request, or a JWT whose
+// mfa_verified_at falls within auth.MFAVerifiedWindow. Every gated call is
+// written to the audit log with both the primary user and the MFA method
+// used, so it must run after AuthMiddleware (which populates user_id/claims)
+// and before the handler it protects.
+func MFAMiddleware(authService *auth.AuthService, auditLog func(c *gin.Context, method string)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userIDVal, _ := c.Get("user_id")
+		userID, _ := userIDVal.(string)
+		if userID == "" {
+			c.Error(errs.Unauthorized("auth.mfa.unauthenticated", "authentication required"))
+			c.Abort()
+			return
+		}
+
+		claimsVal, _ := c.Get("claims")
+		if claims, ok := claimsVal.(*auth.Claims); ok && claims.HasFreshMFA() {
+			auditLog(c, "jwt_mfa_verified_at")
+			c.Next()
+			return
+		}
+
+		assertion := c.GetHeader("X-MFA-Token")
+		if assertion == "" {
+			c.Error(errs.Forbidden("auth.mfa.step_up_required", "step-up authentication required"))
+			c.Abort()
+			return
+		}
+		if err := authService.VerifyMFAAssertion(assertion, userID, c.Request.Method, c.Request.URL.Path); err != nil {
+			c.Error(errs.Forbidden("auth.mfa.step_up_invalid", "step-up authentication invalid").Wrap(err))
+			c.Abort()
+			return
+		}
+
+		auditLog(c, "x_mfa_token")
+		c.Next()
+	}
+}
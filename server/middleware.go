@@ -2,59 +2,83 @@ package main
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
-)
 
-// JWT claims structure
-type Claims struct {
-	UserID   string `json:"user_id"`
-	TenantID string `json:"tenant_id"`
-	Role     string `json:"role"`
-	jwt.RegisteredClaims
-}
+	"insec/internal/auth"
+	"insec/internal/errs"
+	"insec/internal/ratelimit"
+)
 
-// AuthMiddleware validates JWT tokens
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// AuthMiddleware validates an access token issued by auth.AuthService and
+// populates the gin context every downstream handler/middleware reads
+// identity from: user_id, tenant_id, role, claims (the full *auth.Claims,
+// e.g. for MFAMiddleware), and - when the token is an impersonation token -
+// impersonator (the original caller's user_id) and justification (why they
+// requested it), so every handler's logAudit call can record both
+// identities on every request made under the token, not just the
+// /impersonate call that minted it.
+func AuthMiddleware(authService *auth.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.Error(errs.Unauthorized("auth.token.missing", "authorization header required"))
 			c.Abort()
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Bearer token required"})
+			c.Error(errs.Unauthorized("auth.token.not_bearer", "bearer token required"))
 			c.Abort()
 			return
 		}
 
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		claims, err := authService.ValidateToken(tokenString)
+		if err != nil {
+			c.Error(errs.Unauthorized("auth.token.invalid", "invalid token").Wrap(err))
 			c.Abort()
 			return
 		}
 
-		// Set user context
 		c.Set("user_id", claims.UserID)
 		c.Set("tenant_id", claims.TenantID)
 		c.Set("role", claims.Role)
+		c.Set("claims", claims)
+		if claims.Impersonator != nil {
+			c.Set("impersonator", *claims.Impersonator)
+			c.Set("justification", claims.Justification)
+		}
 		c.Next()
 	}
 }
 
+// withImpersonationContext returns details with impersonator/justification
+// merged in from c whenever the current request is running under an
+// impersonation token, so every handler's logAudit call records both
+// identities - not just the /impersonate endpoints themselves. details may
+// be nil; the caller's map is never reused after logAudit returns, so
+// mutating it in place is safe.
+func withImpersonationContext(c *gin.Context, details map[string]interface{}) map[string]interface{} {
+	impersonator, ok := c.Get("impersonator")
+	if !ok {
+		return details
+	}
+	if details == nil {
+		details = make(map[string]interface{}, 2)
+	}
+	details["impersonator"] = impersonator
+	if justification, ok := c.Get("justification"); ok {
+		details["justification"] = justification
+	}
+	return details
+}
+
 // CORSMiddleware handles CORS
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -102,22 +126,75 @@ func LoggingMiddleware(logger *logrus.Logger) gin.HandlerFunc {
 	}
 }
 
-// RateLimitMiddleware implements basic rate limiting
-func RateLimitMiddleware(requestsPerMinute int) gin.HandlerFunc {
-	// Simplified rate limiting - in production, use Redis or similar
+// RateLimitMiddleware enforces the token-bucket budget configured for
+// class (see ratelimit.Buckets) against limiter, keyed on the identity
+// dimension appropriate to that class: agent_id for telemetry ingest,
+// source IP for (pre-auth) auth endpoints, and user_id for alert
+// mutations. Whenever the limiter answers it sets
+// X-RateLimit-Remaining/X-RateLimit-Reset, and additionally sets
+// Retry-After and aborts with 429 once the bucket is exhausted; if the
+// limiter itself errors, the request passes through with no rate-limit
+// headers - one well-behaved agent's traffic never touches another
+// identity's bucket, so abuse on one never starves the rest of a tenant.
+func RateLimitMiddleware(limiter ratelimit.Limiter, class ratelimit.RouteClass) gin.HandlerFunc {
+	bucket := ratelimit.Buckets[class]
 	return func(c *gin.Context) {
-		// For now, just pass through
-		// In production, implement proper rate limiting
+		tenantID, identity := rateLimitIdentity(c, class)
+		key := ratelimit.Key(class, tenantID, identity)
+
+		result, err := limiter.Allow(c.Request.Context(), key, bucket, 1)
+		if err != nil {
+			// Fail open: a limiter outage must not take down the API.
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Remaining", strconv.FormatFloat(result.Remaining, 'f', 0, 64))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds()+1)))
+			c.Error(errs.TooManyRequests("ratelimit.exceeded", "rate limit exceeded"))
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
 
+// rateLimitIdentity resolves the (tenant_id, identity) pair a RouteClass
+// buckets on. Unauthenticated auth endpoints have no tenant yet, so they
+// fall back to a shared "global" tenant scope keyed purely by IP.
+func rateLimitIdentity(c *gin.Context, class ratelimit.RouteClass) (tenantID, identity string) {
+	switch class {
+	case ratelimit.TelemetryIngest:
+		if v, ok := c.Get("agent_tenant_id"); ok {
+			tenantID, _ = v.(string)
+		}
+		if v, ok := c.Get("agent_id"); ok {
+			identity, _ = v.(string)
+		}
+	case ratelimit.AlertMutation:
+		if v, ok := c.Get("tenant_id"); ok {
+			tenantID, _ = v.(string)
+		}
+		if v, ok := c.Get("user_id"); ok {
+			identity, _ = v.(string)
+		}
+	default: // AuthEndpoint and anything else: per source IP, no tenant scope.
+		tenantID = "global"
+		identity = c.ClientIP()
+	}
+	return tenantID, identity
+}
+
 // TenantMiddleware ensures tenant isolation
 func TenantMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tenantID, exists := c.Get("tenant_id")
 		if !exists {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Tenant ID required"})
+			c.Error(errs.BadRequest("tenant.id.required", "tenant id required"))
 			c.Abort()
 			return
 		}
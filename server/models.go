@@ -2,8 +2,6 @@ package main
 
 import (
 	"time"
-
-	"gorm.io/gorm"
 )
 
 type User struct {
@@ -77,6 +75,7 @@ type Event struct {
 	RiskHints []string `json:"risk_hints" gorm:"type:text[]"`
 	Agent     Agent    `json:"agent" gorm:"embedded;embeddedPrefix:agent_"`
 	SessionID string   `json:"session_id" gorm:"index"`
+	CgroupID  uint64   `json:"cgroup_id,omitempty" gorm:"index"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -122,12 +121,64 @@ type Policy struct {
 	UpdatedAt   time.Time
 }
 
-// Database migration
-func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&Event{},
-		&Alert{},
-		&Rule{},
-		&Policy{},
-	)
+// NotificationDelivery persists one attempted (or still-pending) batch
+// delivery to a notification plugin, backing
+// internal/notifications.DeliveryStore so a failed delivery can be retried
+// with backoff across server restarts (see server/notification_store.go).
+type NotificationDelivery struct {
+	ID            string    `json:"id" gorm:"primaryKey"`
+	Plugin        string    `json:"plugin" gorm:"index"`
+	AlertIDs      []string  `json:"alert_ids" gorm:"type:text[]"`
+	Payload       []byte    `json:"-" gorm:"type:bytea"`
+	Attempt       int       `json:"attempt"`
+	Status        string    `json:"status" gorm:"index"`
+	LastError     string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"index"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// PendingAgent tracks one agent's progress through the two-step
+// registration flow: POST /v1/agents/register creates it in
+// models.AgentStatusPending, an operator or auto-approve policy moves it to
+// models.AgentStatusValidated, and only then can its Nonce be redeemed at
+// POST /v1/agents/token. Named distinctly from the Agent struct embedded in
+// Event, which describes a running agent's telemetry metadata, not its
+// enrollment state.
+type PendingAgent struct {
+	ID             string     `json:"id" gorm:"primaryKey"`
+	TenantID       string     `json:"tenant_id" gorm:"index"`
+	Hostname       string     `json:"hostname"`
+	OS             string     `json:"os"`
+	Version        string     `json:"version"`
+	Capabilities   []string   `json:"capabilities" gorm:"type:text[]"`
+	SourceIP       string     `json:"source_ip"`
+	Status         string     `json:"status" gorm:"index"`
+	Nonce          string     `json:"-" gorm:"uniqueIndex"`
+	NonceExpiresAt time.Time  `json:"nonce_expires_at"`
+	ValidatedAt    *time.Time `json:"validated_at,omitempty"`
+	ValidatedBy    string     `json:"validated_by,omitempty"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Session groups the BPF-sourced exec/open/connect events one
+// cgroup-tracked process tree produces, from the insec-agent re-exec that
+// created its cgroup (internal/agent/cgroup) to its close, so an operator
+// can reconstruct "everything this one shell invocation did" as a single
+// ordered timeline (see session_handlers.go's GetTimeline/ExportTimeline).
+type Session struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	TenantID  string     `json:"tenant_id" gorm:"index"`
+	HostID    string     `json:"host_id" gorm:"index"`
+	UserID    string     `json:"user_id"`
+	CgroupID  uint64     `json:"cgroup_id" gorm:"index"`
+	StartedAt time.Time  `json:"started_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
+
+// Schema changes are no longer applied by an ad hoc AutoMigrate call here -
+// see internal/migrations for the versioned, reversible migrations that
+// replaced it, and cmd/insec-migrate for the CLI that applies them.
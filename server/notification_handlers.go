@@ -0,0 +1,103 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"insec/internal/audit"
+	"insec/internal/errs"
+	"insec/internal/notifications"
+)
+
+// NotificationHandler exposes /v1/notifications endpoints in front of an
+// internal/notifications.Dispatcher.
+type NotificationHandler struct {
+	dispatcher *notifications.Dispatcher
+	logger     *logrus.Logger
+	audit      *audit.Logger
+}
+
+// NewNotificationHandler wires a Dispatcher into HTTP handlers. dispatcher
+// may be nil if this server has no notification plugins configured.
+func NewNotificationHandler(dispatcher *notifications.Dispatcher, logger *logrus.Logger, auditLogger *audit.Logger) *NotificationHandler {
+	return &NotificationHandler{dispatcher: dispatcher, logger: logger, audit: auditLogger}
+}
+
+func (h *NotificationHandler) logAudit(c *gin.Context, operation, resource string, details map[string]interface{}) {
+	if h.audit == nil {
+		return
+	}
+	actor, _ := c.Get("user_id")
+	actorID, _ := actor.(string)
+	details = withImpersonationContext(c, details)
+	entry := audit.Entry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Actor:     actorID,
+		Operation: operation,
+		Resource:  resource,
+		Details:   details,
+	}
+	if err := h.audit.Log(c.Request.Context(), entry); err != nil {
+		h.logger.WithError(err).Error("Failed to write audit log entry")
+	}
+}
+
+type testNotificationRequest struct {
+	Plugin   string `json:"plugin" binding:"required"`
+	Title    string `json:"title,omitempty"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// TestNotification handles POST /v1/notifications/test - it fires a
+// synthetic alert through the named plugin, bypassing that plugin's
+// filtering config, so an operator can confirm a newly configured plugin
+// is reachable and correctly wired before relying on it for real alerts.
+func (h *NotificationHandler) TestNotification(c *gin.Context) {
+	var req testNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("notification.test.invalid_body", "invalid test notification payload").Wrap(err))
+		return
+	}
+	if h.dispatcher == nil {
+		c.Error(errs.BadRequest("notification.test.no_plugins", "no notification plugins configured for this server"))
+		return
+	}
+
+	title := req.Title
+	if title == "" {
+		title = "Test notification"
+	}
+	severity := req.Severity
+	if severity == "" {
+		severity = "low"
+	}
+	tenantID, _ := c.Get("tenant_id")
+	tid, _ := tenantID.(string)
+
+	alert := notifications.Alert{
+		ID:          "test-" + uuid.New().String(),
+		Title:       title,
+		Description: "This is a synthetic alert sent to validate plugin wiring.",
+		Severity:    severity,
+		TenantID:    tid,
+		CreatedAt:   time.Now(),
+	}
+
+	err := h.dispatcher.DispatchTo(c.Request.Context(), req.Plugin, alert)
+	h.logAudit(c, "TEST", "notification_plugin:"+req.Plugin, map[string]interface{}{
+		"plugin":  req.Plugin,
+		"success": err == nil,
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("plugin", req.Plugin).Warn("Test notification failed")
+		c.Error(errs.BadGateway("notification.test.dispatch_failed", "failed to dispatch test notification").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "delivered", "plugin": req.Plugin})
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"insec/internal/notifications"
+)
+
+// gormDeliveryStore implements notifications.DeliveryStore against the
+// notification_deliveries table.
+type gormDeliveryStore struct {
+	db *gorm.DB
+}
+
+// newGormDeliveryStore returns a DeliveryStore backed by db.
+func newGormDeliveryStore(db *gorm.DB) notifications.DeliveryStore {
+	return &gormDeliveryStore{db: db}
+}
+
+// Save implements notifications.DeliveryStore.
+func (s *gormDeliveryStore) Save(d notifications.Delivery) error {
+	record := NotificationDelivery{
+		ID:            d.ID,
+		Plugin:        d.Plugin,
+		AlertIDs:      d.AlertIDs,
+		Payload:       d.Payload,
+		Attempt:       d.Attempt,
+		Status:        d.Status,
+		LastError:     d.LastError,
+		NextAttemptAt: d.NextAttemptAt,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+	}
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+	return s.db.Save(&record).Error
+}
+
+// Due implements notifications.DeliveryStore.
+func (s *gormDeliveryStore) Due(now time.Time) ([]notifications.Delivery, error) {
+	var records []NotificationDelivery
+	if err := s.db.Where("status = ? AND next_attempt_at <= ?", notifications.StatusFailed, now).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]notifications.Delivery, len(records))
+	for i, r := range records {
+		deliveries[i] = notifications.Delivery{
+			ID:            r.ID,
+			Plugin:        r.Plugin,
+			AlertIDs:      r.AlertIDs,
+			Payload:       r.Payload,
+			Attempt:       r.Attempt,
+			Status:        r.Status,
+			LastError:     r.LastError,
+			NextAttemptAt: r.NextAttemptAt,
+			CreatedAt:     r.CreatedAt,
+			UpdatedAt:     r.UpdatedAt,
+		}
+	}
+	return deliveries, nil
+}
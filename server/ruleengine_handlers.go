@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"insec/internal/errs"
+	"insec/internal/ruleengine"
+)
+
+// toRuleEngineRule converts a Rule row into ruleengine's own Rule view,
+// mirroring scenario.FromRuleConditions' reuse of the same jsonb Conditions
+// field for a different detection shape.
+func toRuleEngineRule(rule Rule) ruleengine.Rule {
+	return ruleengine.Rule{
+		ID:         rule.ID,
+		Version:    rule.Version,
+		Severity:   rule.Severity,
+		Title:      rule.Name,
+		Conditions: rule.Conditions,
+		UpdatedAt:  rule.UpdatedAt,
+	}
+}
+
+// LoadAllConditionRules (re)loads dispatcher with every enabled Rule row
+// whose Conditions carry a "cel" or "sigma" key, so an operator-authored
+// rule created through POST /v1/rules takes effect without a restart. A
+// rule whose Conditions are malformed for the engine it's shaped for fails
+// the whole reload - unlike LoadAllScenarios' skip-and-log, since a rule
+// author should instead catch that error up front via POST /v1/rules/test
+// (see RuleEngineHandler.TestRule) before the rule is ever saved enabled.
+func LoadAllConditionRules(db *gorm.DB, dispatcher *ruleengine.Dispatcher) error {
+	var rules []Rule
+	if err := db.Where("enabled = ?", true).Find(&rules).Error; err != nil {
+		return err
+	}
+
+	engineRules := make([]ruleengine.Rule, 0, len(rules))
+	for _, rule := range rules {
+		if _, ok := rule.Conditions["cel"]; !ok {
+			if _, ok := rule.Conditions["sigma"]; !ok {
+				continue
+			}
+		}
+		engineRules = append(engineRules, toRuleEngineRule(rule))
+	}
+	return dispatcher.Load(engineRules)
+}
+
+// RuleEngineHandler exposes internal/ruleengine for dry-run testing.
+type RuleEngineHandler struct {
+	dispatcher *ruleengine.Dispatcher
+	logger     *logrus.Logger
+}
+
+// NewRuleEngineHandler wires a RuleEngineHandler onto dispatcher.
+func NewRuleEngineHandler(dispatcher *ruleengine.Dispatcher, logger *logrus.Logger) *RuleEngineHandler {
+	return &RuleEngineHandler{dispatcher: dispatcher, logger: logger}
+}
+
+type testRuleRequest struct {
+	Rule  Rule  `json:"rule" binding:"required"`
+	Event Event `json:"event" binding:"required"`
+}
+
+// TestRule handles POST /v1/rules/test: compiles the request's Rule (never
+// touching the database or the live dispatcher's cache) and evaluates it
+// against the request's sample Event, reporting whether it matched and
+// which clauses (Evidence field paths) contributed.
+func (h *RuleEngineHandler) TestRule(c *gin.Context) {
+	var req testRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("rule.test.invalid_body", "invalid request").Wrap(err))
+		return
+	}
+
+	engineRule := toRuleEngineRule(req.Rule)
+	compiled, err := h.dispatcher.Compile(engineRule)
+	if err != nil {
+		c.Error(errs.BadRequest("rule.test.compile_failed", "failed to compile rule").Wrap(err))
+		return
+	}
+
+	env, err := eventToScenarioEnv(&req.Event)
+	if err != nil {
+		c.Error(errs.Internal("rule.test.flatten_failed", "failed to prepare event").Wrap(err))
+		return
+	}
+
+	eventMap, _ := env["event"].(map[string]interface{})
+	matched, evidence, err := compiled.Evaluate(eventMap)
+	if err != nil {
+		c.Error(errs.Internal("rule.test.evaluate_failed", "failed to evaluate rule").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matched":  matched,
+		"evidence": evidence,
+	})
+}
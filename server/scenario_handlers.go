@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"insec/internal/errs"
+	"insec/internal/scenario"
+)
+
+// eventToScenarioEnv flattens event into the map[string]interface{} shape
+// internal/scenario's expr-lang filters and groupby expressions run
+// against, keyed exactly like Event's own json tags (so a scenario author
+// writes `event.net.dst_port`, not a separate DSL).
+func eventToScenarioEnv(event *Event) (map[string]interface{}, error) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var env map[string]interface{}
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"event": env}, nil
+}
+
+// scenarioEnvSample is compiled against every scenario's filter/groupby
+// expressions so expr can type-check them up front, rather than only at
+// the first matching event.
+func scenarioEnvSample() (map[string]interface{}, error) {
+	return eventToScenarioEnv(&Event{})
+}
+
+// LoadAllScenarios (re)loads engine with the built-in scenarios plus every
+// Rule row whose Conditions look like a scenario (i.e. carry a "capacity"
+// key), so an operator-authored Rule created through POST /v1/rules takes
+// effect without a restart. Malformed per-Rule scenarios are logged and
+// skipped rather than failing the whole reload.
+func LoadAllScenarios(db *gorm.DB, engine *scenario.Engine, logger *logrus.Logger) error {
+	scenarios, err := scenario.BuiltinScenarios()
+	if err != nil {
+		return err
+	}
+
+	var rules []Rule
+	if err := db.Where("enabled = ?", true).Find(&rules).Error; err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if _, ok := rule.Conditions["capacity"]; !ok {
+			continue
+		}
+		s, err := scenario.FromRuleConditions(rule.ID, rule.Name, rule.Severity, rule.Conditions)
+		if err != nil {
+			logger.WithError(err).WithField("rule_id", rule.ID).Warn("Skipping malformed scenario rule")
+			continue
+		}
+		scenarios = append(scenarios, s)
+	}
+
+	env, err := scenarioEnvSample()
+	if err != nil {
+		return err
+	}
+	return engine.LoadScenarios(scenarios, env)
+}
+
+// ScenarioHandler exposes internal/scenario's bucket state for debugging.
+type ScenarioHandler struct {
+	engine *scenario.Engine
+	logger *logrus.Logger
+}
+
+// NewScenarioHandler wires a ScenarioHandler onto an already-loaded Engine.
+func NewScenarioHandler(engine *scenario.Engine, logger *logrus.Logger) *ScenarioHandler {
+	return &ScenarioHandler{engine: engine, logger: logger}
+}
+
+// ListBuckets handles GET /v1/scenarios/buckets, dumping every scenario
+// bucket's current level so an operator can see why (or why not) a
+// scenario is about to fire.
+func (h *ScenarioHandler) ListBuckets(c *gin.Context) {
+	if h.engine == nil {
+		c.JSON(http.StatusOK, gin.H{"buckets": []scenario.BucketSnapshot{}})
+		return
+	}
+
+	snapshots, err := h.engine.Buckets().Snapshot(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to snapshot scenario buckets")
+		c.Error(errs.Internal("scenarios.buckets.failed", "failed to read scenario buckets").Wrap(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"buckets": snapshots})
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"insec/internal/audit"
+	"insec/internal/errs"
+)
+
+// SessionHandler exposes /v1/sessions: opening and closing the
+// cgroup-tracked sessions internal/agent/cgroup creates at
+// insec-agent re-exec time, and serving/exporting the resulting
+// exec/open/connect timeline for offline forensic review.
+type SessionHandler struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+	audit  *audit.Logger
+	// tarballDir is where ExportTimeline additionally persists a copy of
+	// each exported artifact. Empty disables persistence; the artifact is
+	// still streamed to the caller. An S3-backed exporter would plug in
+	// here instead of (or alongside) the local directory, but no AWS SDK
+	// dependency is wired into this tree yet.
+	tarballDir string
+}
+
+// NewSessionHandler wires a SessionHandler onto db. tarballDir may be empty
+// to only stream exports without persisting a copy server-side.
+func NewSessionHandler(db *gorm.DB, logger *logrus.Logger, auditLogger *audit.Logger, tarballDir string) *SessionHandler {
+	return &SessionHandler{db: db, logger: logger, audit: auditLogger, tarballDir: tarballDir}
+}
+
+// logAudit records a session lifecycle event in the hash-chained audit
+// trail, matching every other handler's logAudit convention.
+func (h *SessionHandler) logAudit(c *gin.Context, operation, resource string, details map[string]interface{}) {
+	if h.audit == nil {
+		return
+	}
+	actor, _ := c.Get("user_id")
+	actorID, _ := actor.(string)
+	details = withImpersonationContext(c, details)
+	entry := audit.Entry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Actor:     actorID,
+		Operation: operation,
+		Resource:  resource,
+		Details:   details,
+	}
+	if err := h.audit.Log(c.Request.Context(), entry); err != nil {
+		h.logger.WithError(err).Error("Failed to write audit log entry")
+	}
+}
+
+type openSessionRequest struct {
+	UserID   string `json:"user_id" binding:"required"`
+	HostID   string `json:"host_id" binding:"required"`
+	CgroupID uint64 `json:"cgroup_id" binding:"required"`
+}
+
+// OpenSession handles POST /v1/sessions, called by insec-agent right after
+// internal/agent/cgroup.New creates the session's cgroup and before it
+// execs the target process, so every subsequent BPF event already has an
+// open Session to resolve against in EventHandler.resolveSessionID.
+func (h *SessionHandler) OpenSession(c *gin.Context) {
+	var req openSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("session.open.invalid", "invalid session open payload").Wrap(err))
+		return
+	}
+
+	tenantID, _ := c.Get("agent_tenant_id")
+	tid, _ := tenantID.(string)
+
+	session := Session{
+		ID:        uuid.New().String(),
+		TenantID:  tid,
+		HostID:    req.HostID,
+		UserID:    req.UserID,
+		CgroupID:  req.CgroupID,
+		StartedAt: time.Now(),
+	}
+	if err := h.db.Create(&session).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to open session")
+		c.Error(errs.Internal("session.open.failed", "failed to open session").Wrap(err))
+		return
+	}
+
+	h.logAudit(c, "OPEN", "session:"+session.ID, map[string]interface{}{
+		"host_id": session.HostID, "user_id": session.UserID, "cgroup_id": session.CgroupID,
+	})
+	c.JSON(http.StatusCreated, session)
+}
+
+// CloseSession handles POST /v1/sessions/:id/close, called once
+// insec-agent's re-exec'd process tree has exited and its cgroup directory
+// is about to be removed (internal/agent/cgroup.Session.Close).
+func (h *SessionHandler) CloseSession(c *gin.Context) {
+	id := c.Param("id")
+	var session Session
+	if err := h.db.Where("id = ?", id).First(&session).Error; err != nil {
+		c.Error(errs.NotFound("session.close.not_found", "session not found"))
+		return
+	}
+
+	now := time.Now()
+	session.ClosedAt = &now
+	if err := h.db.Save(&session).Error; err != nil {
+		h.logger.WithError(err).Error("Failed to close session")
+		c.Error(errs.Internal("session.close.failed", "failed to close session").Wrap(err))
+		return
+	}
+
+	h.logAudit(c, "CLOSE", "session:"+session.ID, map[string]interface{}{"host_id": session.HostID})
+	c.JSON(http.StatusOK, session)
+}
+
+// timeline loads id's Session and its ordered exec/open/connect events in
+// one place, shared by GetTimeline and ExportTimeline.
+func (h *SessionHandler) timeline(id string) (Session, []Event, error) {
+	var session Session
+	if err := h.db.Where("id = ?", id).First(&session).Error; err != nil {
+		return Session{}, nil, err
+	}
+	var events []Event
+	if err := h.db.Where("session_id = ?", id).Order("timestamp asc").Find(&events).Error; err != nil {
+		return Session{}, nil, err
+	}
+	return session, events, nil
+}
+
+// GetTimeline handles GET /v1/sessions/:id/timeline, returning the ordered
+// BPF-sourced events this session's cgroup produced.
+func (h *SessionHandler) GetTimeline(c *gin.Context) {
+	session, events, err := h.timeline(c.Param("id"))
+	if err != nil {
+		c.Error(errs.NotFound("session.timeline.not_found", "session not found"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"session": session, "events": events})
+}
+
+// ExportTimeline handles GET /v1/sessions/:id/export, packaging the
+// session's metadata and timeline into a single gzipped tarball suitable
+// for offline forensic review - an export an analyst can hand off or
+// archive independently of this server's own retention policy.
+func (h *SessionHandler) ExportTimeline(c *gin.Context) {
+	id := c.Param("id")
+	session, events, err := h.timeline(id)
+	if err != nil {
+		c.Error(errs.NotFound("session.export.not_found", "session not found"))
+		return
+	}
+
+	archive, err := buildSessionTarball(session, events)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to build session tarball")
+		c.Error(errs.Internal("session.export.failed", "failed to build session export").Wrap(err))
+		return
+	}
+
+	if h.tarballDir != "" {
+		path := filepath.Join(h.tarballDir, id+".tar.gz")
+		if err := os.WriteFile(path, archive, 0o644); err != nil {
+			h.logger.WithError(err).WithField("path", path).Warn("Failed to persist session tarball locally")
+		}
+	}
+
+	h.logAudit(c, "EXPORT", "session:"+id, map[string]interface{}{"event_count": len(events)})
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+".tar.gz"))
+	c.Data(http.StatusOK, "application/gzip", archive)
+}
+
+// buildSessionTarball packages session.json (the Session record) and
+// timeline.json (its ordered events) into a gzipped tar archive.
+func buildSessionTarball(session Session, events []Event) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	if err := writeTarJSON(tw, "session.json", session); err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []Event{}
+	}
+	if err := writeTarJSON(tw, "timeline.json", events); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("session: close tar writer: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("session: close gzip writer: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeTarJSON(tw *tar.Writer, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("session: marshal %s: %w", name, err)
+	}
+	header := &tar.Header{
+		Name: name,
+		Mode: 0o644,
+		Size: int64(len(data)),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("session: write %s header: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("session: write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSessionTarball_ContainsSessionAndTimelineJSON(t *testing.T) {
+	session := Session{
+		ID:        "sess-1",
+		TenantID:  "tenant-1",
+		HostID:    "host-1",
+		UserID:    "user-1",
+		CgroupID:  42,
+		StartedAt: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+	}
+	events := []Event{
+		{TenantID: "tenant-1", HostID: "host-1", SessionID: "sess-1", Timestamp: session.StartedAt},
+	}
+
+	archive, err := buildSessionTarball(session, events)
+	require.NoError(t, err)
+
+	files := readTarball(t, archive)
+	require.Contains(t, files, "session.json")
+	require.Contains(t, files, "timeline.json")
+
+	var gotSession Session
+	require.NoError(t, json.Unmarshal(files["session.json"], &gotSession))
+	assert.Equal(t, session.ID, gotSession.ID)
+	assert.Equal(t, session.CgroupID, gotSession.CgroupID)
+
+	var gotEvents []Event
+	require.NoError(t, json.Unmarshal(files["timeline.json"], &gotEvents))
+	require.Len(t, gotEvents, 1)
+	assert.Equal(t, "sess-1", gotEvents[0].SessionID)
+}
+
+func TestBuildSessionTarball_EmptyTimelineProducesEmptyArray(t *testing.T) {
+	archive, err := buildSessionTarball(Session{ID: "sess-2"}, nil)
+	require.NoError(t, err)
+
+	files := readTarball(t, archive)
+	var gotEvents []Event
+	require.NoError(t, json.Unmarshal(files["timeline.json"], &gotEvents))
+	assert.Empty(t, gotEvents)
+}
+
+// readTarball gunzips and untars archive, returning each entry's raw bytes
+// keyed by name, so tests can assert on buildSessionTarball's output without
+// re-implementing tar/gzip decoding in every test.
+func readTarball(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = data
+	}
+	return files
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"insec/internal/errs"
+	"insec/internal/intel"
+	"insec/internal/models"
+)
+
+// SignalHandler exposes the cross-tenant signal-sharing endpoints: a
+// tenant pushes signals it derived from its own resolved alerts, and pulls
+// the hub's consolidated Decisions back. Tenant isolation holds at both
+// ends - Push clears each signal's source tenant ID before it ever leaves
+// this server (see intel.HubClient.Push), and Pull only ever
+// merges Decisions into the caller's own tenant scope in the DecisionStore.
+type SignalHandler struct {
+	decisions intel.DecisionStore
+	hub       *intel.HubClient
+	logger    *logrus.Logger
+}
+
+// NewSignalHandler wires a DecisionStore and optional HubClient into HTTP
+// handlers. hub may be nil if this server has no upstream hub configured,
+// in which case Push/Pull report a clear, stable error rather than a panic.
+func NewSignalHandler(decisions intel.DecisionStore, hub *intel.HubClient, logger *logrus.Logger) *SignalHandler {
+	return &SignalHandler{decisions: decisions, hub: hub, logger: logger}
+}
+
+type pushSignalsRequest struct {
+	Signals []models.Signal `json:"signals" binding:"required"`
+}
+
+// POST /api/v1/signals/push - tenant -> hub.
+func (h *SignalHandler) Push(c *gin.Context) {
+	var req pushSignalsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errs.BadRequest("signals.push.invalid_body", "invalid signals payload").Wrap(err))
+		return
+	}
+	if h.hub == nil {
+		c.Error(errs.BadRequest("signals.push.not_configured", "no upstream hub configured for this server"))
+		return
+	}
+
+	tenantID, _ := c.Get("tenant_id")
+	tid, _ := tenantID.(string)
+	for i := range req.Signals {
+		req.Signals[i].SourceTenantID = tid
+	}
+
+	if err := h.hub.Push(req.Signals); err != nil {
+		h.logger.WithError(err).Error("Failed to push signals to hub")
+		c.Error(errs.Internal("signals.push.failed", "failed to push signals to hub").Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "count": len(req.Signals)})
+}
+
+// POST /api/v1/signals/pull - hub -> tenant. Pulls the hub's latest
+// consolidated Decisions and merges them into this tenant's slice of the
+// DecisionStore; the risk-scoring hook in IngestEvents consults them from
+// then on.
+func (h *SignalHandler) Pull(c *gin.Context) {
+	if h.hub == nil {
+		c.Error(errs.BadRequest("signals.pull.not_configured", "no upstream hub configured for this server"))
+		return
+	}
+
+	tenantID, _ := c.Get("tenant_id")
+	tid, _ := tenantID.(string)
+
+	decisions, err := h.hub.Pull()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to pull decisions from hub")
+		c.Error(errs.Internal("signals.pull.failed", "failed to pull decisions from hub").Wrap(err))
+		return
+	}
+	for i := range decisions {
+		decisions[i].TenantID = tid
+	}
+
+	h.decisions.Upsert(tid, decisions)
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "count": len(decisions)})
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"insec/internal/errs"
+	"insec/internal/ueba"
+)
+
+// eventToUEBAFeatures flattens event into the subset of fields
+// internal/ueba's Engine scores against, mirroring eventToScenarioEnv's
+// role for internal/scenario.
+func eventToUEBAFeatures(event *Event) ueba.EventFeatures {
+	features := ueba.EventFeatures{
+		TenantID:  event.TenantID,
+		UserID:    event.User.ID,
+		Dept:      event.User.Dept,
+		HostID:    event.HostID,
+		Timestamp: event.Timestamp,
+	}
+	if event.Process != nil {
+		features.ProcessName = event.Process.Name
+	}
+	if event.Network != nil {
+		features.DstPort = strconv.Itoa(int(event.Network.DstPort))
+		if event.Network.Domain != nil {
+			features.Domain = *event.Network.Domain
+		}
+	}
+	if event.File != nil {
+		features.FilePath = event.File.Path
+	}
+	return features
+}
+
+// scoreUEBA scores event against its subject's behavioral baseline and
+// folds the result into the user's persisted RiskScore (EWMA, already
+// computed by the engine; this just writes it to the User row). Every
+// Alert this event produces - whether from internal/scenario's buckets or
+// internal/ruleengine's dispatcher - is stamped with the same score, since
+// both detections are reacting to the same underlying event. A nil engine,
+// or a scoring error, yields a zero Result rather than blocking alert
+// creation - UEBA scoring augments detection, it isn't load-bearing for it.
+func (h *EventHandler) scoreUEBA(event *Event) ueba.Result {
+	if h.ueba == nil {
+		return ueba.Result{}
+	}
+
+	result, err := h.ueba.Score(context.Background(), eventToUEBAFeatures(event))
+	if err != nil {
+		h.logger.WithError(err).Warn("UEBA scoring failed")
+		return ueba.Result{}
+	}
+
+	if event.User.ID != "" {
+		riskScore := int(result.RiskScore)
+		user := User{ID: event.User.ID, Email: event.User.Email, Dept: event.User.Dept, RiskScore: &riskScore}
+		err := h.db.Where(User{ID: event.User.ID}).
+			Assign(User{Email: event.User.Email, Dept: event.User.Dept, RiskScore: &riskScore}).
+			FirstOrCreate(&user).Error
+		if err != nil {
+			h.logger.WithError(err).Warn("Failed to persist user risk score")
+		}
+	}
+	return result
+}
+
+// UEBAHandler exposes internal/ueba's behavioral baselines for operator
+// inspection.
+type UEBAHandler struct {
+	store  ueba.Store
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+// NewUEBAHandler wires a UEBAHandler onto store.
+func NewUEBAHandler(store ueba.Store, db *gorm.DB, logger *logrus.Logger) *UEBAHandler {
+	return &UEBAHandler{store: store, db: db, logger: logger}
+}
+
+// GetProfile handles GET /v1/ueba/profile/:user_id, returning the user's
+// current feature distributions and RiskScore. With ?alert_id=, it also
+// returns that alert's stamped Entities["ueba_contributors"] -
+// the breakdown of which features drove that specific alert's UEBAScore,
+// as opposed to the profile's overall distributions.
+func (h *UEBAHandler) GetProfile(c *gin.Context) {
+	userID := c.Param("user_id")
+	tenantID := c.Query("tenant_id")
+
+	profile, err := h.store.Load(tenantID, ueba.SubjectUser, userID, "")
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load ueba profile")
+		c.Error(errs.Internal("ueba.profile.load_failed", "failed to load profile").Wrap(err))
+		return
+	}
+
+	response := gin.H{
+		"user_id":    userID,
+		"risk_score": profile.RiskScore,
+		"last_seen":  profile.LastSeen,
+		"distributions": gin.H{
+			"process_names": profile.ProcessNames,
+			"dst_ports":     profile.DstPorts,
+			"domains":       profile.Domains,
+			"file_paths":    profile.FilePaths,
+			"login_hours":   profile.LoginHours,
+		},
+	}
+
+	if alertID := c.Query("alert_id"); alertID != "" {
+		var alert Alert
+		if err := h.db.First(&alert, "id = ?", alertID).Error; err != nil {
+			c.Error(errs.NotFound("ueba.profile.alert_not_found", "alert not found"))
+			return
+		}
+		response["alert_contributors"] = alert.Entities["ueba_contributors"]
+	}
+
+	c.JSON(http.StatusOK, response)
+}
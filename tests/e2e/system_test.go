@@ -2,7 +2,6 @@ package tests
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,8 +11,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
+	"insec/internal/audit"
 	"insec/internal/models"
-	"insec/test/helpers"
+	"insec/tests/helpers"
 )
 
 type EndToEndTestSuite struct {
@@ -60,6 +60,7 @@ func (suite *EndToEndTestSuite) TestCompleteAlertLifecycle() {
 
 	// 4. User retrieves and views alert
 	alert := suite.getAlert(alertID)
+	assert.Equal(suite.T(), alertID, alert.ID)
 
 	// 5. User acknowledges alert
 	suite.acknowledgeAlert(alertID)
@@ -143,6 +144,30 @@ func (suite *EndToEndTestSuite) TestMultiTenantIsolation() {
 	}
 }
 
+func (suite *EndToEndTestSuite) TestCommunitySignalSharingRespectsOptIn() {
+	tenant1Token := suite.createTenantAndAuthenticate("tenant1")
+	tenant2Token := suite.createTenantAndAuthenticate("tenant2")
+
+	// Both tenants opt in to the community blocklist and pull the hub's
+	// current Decisions (empty so far).
+	suite.enrollInSignalSharing(tenant1Token)
+	suite.enrollInSignalSharing(tenant2Token)
+
+	maliciousHash := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b85"
+	suite.pushMaliciousHashSignal(tenant1Token, maliciousHash)
+
+	// Hub consolidation + pull is async in production; poll both tenants.
+	suite.pullSignalsWithToken(tenant1Token)
+	suite.pullSignalsWithToken(tenant2Token)
+
+	tenant2Hints := suite.sendTelemetryWithProcessHash(tenant2Token, maliciousHash)
+	assert.Contains(suite.T(), tenant2Hints, "community_blocklist_match:hash", "A tenant that opted in should have risk boosted for another tenant's shared hash")
+
+	tenant3Token := suite.createTenantAndAuthenticate("tenant3")
+	tenant3Hints := suite.sendTelemetryWithProcessHash(tenant3Token, maliciousHash)
+	assert.NotContains(suite.T(), tenant3Hints, "community_blocklist_match:hash", "A tenant that never opted in (never pulled) must not have its risk boosted by another tenant's signal")
+}
+
 func (suite *EndToEndTestSuite) TestSystemPerformanceUnderLoad() {
 	// 1. Start performance monitoring
 	suite.startPerformanceMonitoring()
@@ -164,6 +189,21 @@ func (suite *EndToEndTestSuite) TestSystemPerformanceUnderLoad() {
 	// 5. Verify all events were processed
 	processedEvents := suite.getProcessedEventsCount()
 	assert.Equal(suite.T(), 1000, processedEvents, "All events should be processed")
+
+	// 6. An abusive agent blowing through its telemetry-ingest bucket must
+	// start getting 429s, while a well-behaved agent in the same tenant is
+	// unaffected - the bucket is keyed per-agent, not per-tenant.
+	abusiveStatuses := suite.floodTelemetryFromAgent("abusive-agent", 12000)
+	rateLimited := 0
+	for _, status := range abusiveStatuses {
+		if status == http.StatusTooManyRequests {
+			rateLimited++
+		}
+	}
+	assert.Greater(suite.T(), rateLimited, 0, "Abusive agent should eventually receive 429s")
+
+	wellBehavedStatus := suite.sendSingleTelemetryFromAgent("well-behaved-agent")
+	assert.Equal(suite.T(), http.StatusOK, wellBehavedStatus, "A well-behaved agent in the same tenant must not be rate limited by another agent's abuse")
 }
 
 func (suite *EndToEndTestSuite) TestAgentServerCommunication() {
@@ -215,6 +255,135 @@ func (suite *EndToEndTestSuite) TestSecurityFeatures() {
 	suite.testIntrusionDetection()
 }
 
+func (suite *EndToEndTestSuite) TestImpersonationAuditTrailAndRevocation() {
+	adminToken := suite.authenticateAsRole("admin")
+	targetUserID := suite.createTestUser("analyst")
+
+	impersonationToken, nonce := suite.requestImpersonation(adminToken, targetUserID, "Investigating alert #4821 on the target's behalf")
+	client := &http.Client{}
+
+	// The token works for reading alerts as the target user.
+	req, _ := http.NewRequest("GET", suite.baseURL+"/api/v1/alerts", nil)
+	req.Header.Set("Authorization", "Bearer "+impersonationToken)
+	resp, err := client.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode, "A valid impersonation token should authenticate as the target user")
+
+	// The audit trail must show both identities and the justification.
+	entry := suite.getLatestAuditLogEntry()
+	assert.Equal(suite.T(), "IMPERSONATE", entry.Operation)
+	assert.Contains(suite.T(), entry.Details, "impersonator")
+	assert.Contains(suite.T(), entry.Details, "target")
+	assert.Contains(suite.T(), entry.Details, "justification")
+
+	// An impersonation token must not be usable to mint another one.
+	recursiveReq, _ := json.Marshal(map[string]string{
+		"target_user_id": suite.createTestUser("viewer"),
+		"justification":  "nested",
+	})
+	req, _ = http.NewRequest("POST", suite.baseURL+"/api/v1/auth/impersonate", bytes.NewBuffer(recursiveReq))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+impersonationToken)
+	resp, err = client.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode, "An impersonation token must not be able to mint another impersonation token")
+
+	// Revoking the nonce must immediately invalidate the in-flight token.
+	suite.revokeImpersonationNonce(adminToken, nonce)
+
+	req, _ = http.NewRequest("GET", suite.baseURL+"/api/v1/alerts", nil)
+	req.Header.Set("Authorization", "Bearer "+impersonationToken)
+	resp, err = client.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode, "A revoked impersonation nonce must invalidate the token immediately, even before its expiry")
+}
+
+func (suite *EndToEndTestSuite) TestDecisionLifecycle() {
+	token := suite.authenticateAsRole("admin")
+
+	decisionID := suite.createManualBanDecision(token, "203.0.113.7", "manual test ban")
+
+	listed := suite.listDecisions(token, "ip", "203.0.113.7")
+	assert.Contains(suite.T(), listed, decisionID, "a freshly created decision should appear in a scope+value filtered list")
+
+	// The agent-facing pull endpoint should also carry it while it's active.
+	agentDecisions := suite.pullActiveDecisionsForAgent(token)
+	assert.Contains(suite.T(), agentDecisions, decisionID, "agents should be able to pull the active decision set to enforce locally")
+
+	nbDeleted := suite.bulkDeleteDecisionsByScopeValue(token, "ip", "203.0.113.7")
+	assert.Equal(suite.T(), "1", nbDeleted, "bulk delete by scope+value should report exactly how many rows it removed")
+
+	listedAfterDelete := suite.listDecisions(token, "ip", "203.0.113.7")
+	assert.NotContains(suite.T(), listedAfterDelete, decisionID, "a deleted decision must not still be listed")
+}
+
+func (suite *EndToEndTestSuite) TestNotificationPluginDispatch() {
+	token := suite.authenticateAsRole("admin")
+
+	// A configured plugin should receive a synthetic alert fired through
+	// the validation endpoint.
+	status := suite.fireTestNotification(token, "webhook-test")
+	assert.Equal(suite.T(), "delivered", status, "a configured plugin should accept a synthetic test alert")
+
+	// An alert generated for real should also reach every plugin whose
+	// filter it matches - a high-severity alert should clear a plugin
+	// configured with min_severity=medium.
+	suite.configurePluginFilter("webhook-test", "medium")
+	suite.registerAgent()
+	alertID := suite.triggerHighSeverityAlert()
+	assert.Eventually(suite.T(), func() bool {
+		return suite.pluginReceivedAlert("webhook-test", alertID)
+	}, 5*time.Second, 100*time.Millisecond, "a plugin whose min_severity filter the alert clears should receive it")
+}
+
+func (suite *EndToEndTestSuite) TestAgentEnrollmentLifecycle() {
+	adminToken := suite.authenticateAsRole("admin")
+
+	// A freshly registered agent should be pending and get back a nonce,
+	// never a long-lived credential.
+	agentID, status, nonce := suite.registerPendingAgent("test-tenant")
+	assert.Equal(suite.T(), "pending", status, "a new agent should start in the pending state")
+	assert.NotEmpty(suite.T(), nonce, "registration should return a redeemable nonce")
+
+	// Redeeming the nonce before validation must be rejected.
+	_, err := suite.redeemAgentNonce(agentID, nonce)
+	assert.Error(suite.T(), err, "an unvalidated agent must not be able to redeem its nonce")
+
+	pending := suite.listPendingAgents(adminToken)
+	assert.Contains(suite.T(), pending, agentID, "an unvalidated agent should show up in the pending queue")
+
+	suite.validateAgent(adminToken, agentID)
+
+	enrollmentToken, err := suite.redeemAgentNonce(agentID, nonce)
+	assert.NoError(suite.T(), err, "a validated agent should be able to redeem its nonce")
+	assert.NotEmpty(suite.T(), enrollmentToken, "redeeming a nonce should return an enrollment token for the existing CSR flow")
+
+	// The nonce is single-use.
+	_, err = suite.redeemAgentNonce(agentID, nonce)
+	assert.Error(suite.T(), err, "a nonce must not be redeemable a second time")
+}
+
+func (suite *EndToEndTestSuite) TestSessionRecordingLifecycle() {
+	token := suite.authenticateAsRole("admin")
+
+	sessionID := suite.openSession(token, "host-bpf-1", 123456)
+
+	// A BPF-sourced event carrying the session's cgroup ID but no
+	// session_id of its own should have it resolved on ingest.
+	suite.sendTelemetryWithCgroup(token, "host-bpf-1", 123456)
+
+	timeline := suite.getSessionTimeline(token, sessionID)
+	assert.NotEmpty(suite.T(), timeline, "an event tagged with the session's cgroup should appear in its timeline")
+
+	archive := suite.exportSessionTimeline(token, sessionID)
+	assert.NotEmpty(suite.T(), archive, "exporting a session should return a non-empty tarball")
+
+	suite.closeSession(token, sessionID)
+}
+
 func (suite *EndToEndTestSuite) TestScalability() {
 	// 1. Start with baseline agents
 	baselineMetrics := suite.getSystemMetrics()
@@ -307,11 +476,11 @@ func (suite *EndToEndTestSuite) authenticate() string {
 
 func (suite *EndToEndTestSuite) registerAgent() string {
 	agentData := models.AgentRegistration{
-		AgentID:     "e2e-test-agent",
-		TenantID:    "test-tenant",
-		Hostname:    "test-host",
-		OS:          "Linux",
-		Version:     "1.0.0",
+		AgentID:      "e2e-test-agent",
+		TenantID:     "test-tenant",
+		Hostname:     "test-host",
+		OS:           "Linux",
+		Version:      "1.0.0",
 		Capabilities: []string{"process_monitoring", "file_monitoring", "network_monitoring"},
 	}
 
@@ -338,7 +507,7 @@ func (suite *EndToEndTestSuite) sendTelemetryData(agentID string) {
 		Source:      agentID,
 		Data: map[string]interface{}{
 			"process_name": "test_process.exe",
-			"pid":         12345,
+			"pid":          12345,
 			"command_line": "test_process.exe --test",
 		},
 	}
@@ -493,6 +662,12 @@ func (suite *EndToEndTestSuite) createTenantAndAuthenticate(tenantID string) str
 	return ""
 }
 
+func (suite *EndToEndTestSuite) authenticateAsRole(role string) string {
+	// Implementation for authenticating as a user with the given role and
+	// returning the resulting access token
+	return ""
+}
+
 func (suite *EndToEndTestSuite) registerAgentWithToken(token string) string {
 	// Implementation for registering agent with specific token
 	return ""
@@ -515,6 +690,164 @@ func (suite *EndToEndTestSuite) generateHighVolumeTelemetry(count int) {
 	// Implementation for generating high volume telemetry
 }
 
+func (suite *EndToEndTestSuite) floodTelemetryFromAgent(agentID string, count int) []int {
+	// Implementation for sending count telemetry requests as agentID in
+	// quick succession and returning each response's status code
+	return []int{}
+}
+
+func (suite *EndToEndTestSuite) sendSingleTelemetryFromAgent(agentID string) int {
+	// Implementation for sending one telemetry request as agentID and
+	// returning the response status code
+	return http.StatusOK
+}
+
+func (suite *EndToEndTestSuite) enrollInSignalSharing(token string) {
+	// Implementation for having the tenant behind token opt in to
+	// community signal sharing (POST /api/v1/signals/pull once)
+}
+
+func (suite *EndToEndTestSuite) pushMaliciousHashSignal(token, hash string) {
+	// Implementation for POSTing a high-confidence models.Signal for hash
+	// to /api/v1/signals/push as the tenant behind token
+}
+
+func (suite *EndToEndTestSuite) pullSignalsWithToken(token string) {
+	// Implementation for POSTing to /api/v1/signals/pull as the tenant
+	// behind token, merging the hub's consolidated decisions locally
+}
+
+func (suite *EndToEndTestSuite) sendTelemetryWithProcessHash(token, hash string) []string {
+	// Implementation for sending a telemetry event carrying proc.hash=hash
+	// as the tenant behind token and returning the resulting risk_hints
+	return []string{}
+}
+
+func (suite *EndToEndTestSuite) createTestUser(role string) string {
+	// Implementation for creating a user with the given role in the
+	// current tenant and returning its user_id
+	return "user-id"
+}
+
+func (suite *EndToEndTestSuite) requestImpersonation(callerToken, targetUserID, justification string) (token, nonce string) {
+	// Implementation for POSTing to /api/v1/auth/impersonate as the bearer
+	// of callerToken and returning the issued access token plus its nonce
+	// (extracted from the token's claims, for revokeImpersonationNonce)
+	return "impersonation-token", "impersonation-nonce"
+}
+
+func (suite *EndToEndTestSuite) revokeImpersonationNonce(callerToken, nonce string) {
+	// Implementation for POSTing to /api/v1/auth/impersonate/revoke as the
+	// bearer of callerToken
+}
+
+func (suite *EndToEndTestSuite) getLatestAuditLogEntry() audit.Entry {
+	// Implementation for fetching the most recent hash-chained audit entry
+	return audit.Entry{}
+}
+
+func (suite *EndToEndTestSuite) createManualBanDecision(token, ip, reason string) string {
+	// Implementation for POSTing a ban/ip decision with origin "manual" to
+	// /v1/decisions as the bearer of token and returning its ID
+	return "decision-id"
+}
+
+func (suite *EndToEndTestSuite) listDecisions(token, scope, value string) []string {
+	// Implementation for GETting /v1/decisions?scope=scope&value=value
+	// as the bearer of token and returning the matching decision IDs
+	return []string{}
+}
+
+func (suite *EndToEndTestSuite) pullActiveDecisionsForAgent(token string) []string {
+	// Implementation for GETting /v1/agents/decisions as an agent in
+	// the tenant behind token and returning the active decision IDs
+	return []string{}
+}
+
+func (suite *EndToEndTestSuite) bulkDeleteDecisionsByScopeValue(token, scope, value string) string {
+	// Implementation for DELETEing /v1/decisions?scope=scope&value=value
+	// as the bearer of token and returning the "nbDeleted" field
+	return "0"
+}
+
+func (suite *EndToEndTestSuite) fireTestNotification(token, plugin string) string {
+	// Implementation for POSTing {"plugin": plugin} to
+	// /v1/notifications/test as the bearer of token and returning the
+	// response's "status" field
+	return "delivered"
+}
+
+func (suite *EndToEndTestSuite) configurePluginFilter(plugin, minSeverity string) {
+	// Implementation for updating the named plugin's dispatcher
+	// PluginConfig.MinSeverity via the server's notification plugin config
+}
+
+func (suite *EndToEndTestSuite) triggerHighSeverityAlert() string {
+	// Implementation for sending telemetry that reliably trips a
+	// high-severity detection rule and returning the resulting alert ID
+	return "alert-id"
+}
+
+func (suite *EndToEndTestSuite) pluginReceivedAlert(plugin, alertID string) bool {
+	// Implementation for checking whether the named test plugin's recorded
+	// deliveries include alertID
+	return false
+}
+
+func (suite *EndToEndTestSuite) registerPendingAgent(tenantID string) (agentID, status, nonce string) {
+	// Implementation for POSTing an AgentRegistration to
+	// /v1/agents/register and returning the response's agent_id, status,
+	// and nonce fields
+	return "agent-id", "pending", "nonce"
+}
+
+func (suite *EndToEndTestSuite) listPendingAgents(token string) []string {
+	// Implementation for GETting /v1/agents?status=pending as the
+	// bearer of token and returning the matching agent IDs
+	return []string{}
+}
+
+func (suite *EndToEndTestSuite) validateAgent(token, agentID string) {
+	// Implementation for POSTing /v1/agents/{agentID}/validate as the
+	// bearer of token
+}
+
+func (suite *EndToEndTestSuite) redeemAgentNonce(agentID, nonce string) (string, error) {
+	// Implementation for POSTing {agent_id, nonce} to /v1/agents/token
+	// and returning the response's enrollment_token field, or the server's
+	// error if the agent isn't validated or the nonce is invalid/expired
+	return "", nil
+}
+
+func (suite *EndToEndTestSuite) openSession(token, hostID string, cgroupID uint64) string {
+	// Implementation for POSTing {user_id, host_id, cgroup_id} to
+	// /v1/sessions as the bearer of token and returning the new
+	// session's id
+	return "session-id"
+}
+
+func (suite *EndToEndTestSuite) sendTelemetryWithCgroup(token, hostID string, cgroupID uint64) {
+	// Implementation for POSTing an Event carrying host_id/cgroup_id (but
+	// no session_id) to /api/v1/events as the bearer of token
+}
+
+func (suite *EndToEndTestSuite) getSessionTimeline(token, sessionID string) []string {
+	// Implementation for GETting /v1/sessions/{sessionID}/timeline as
+	// the bearer of token and returning the event IDs in order
+	return []string{}
+}
+
+func (suite *EndToEndTestSuite) exportSessionTimeline(token, sessionID string) []byte {
+	// Implementation for GETting /v1/sessions/{sessionID}/export as
+	// the bearer of token and returning the raw tarball bytes
+	return nil
+}
+
+func (suite *EndToEndTestSuite) closeSession(token, sessionID string) {
+	// Implementation for POSTing /v1/sessions/{sessionID}/close as the
+	// bearer of token
+}
+
 func (suite *EndToEndTestSuite) getSystemMetrics() models.SystemMetrics {
 	// Implementation for getting system metrics
 	return models.SystemMetrics{}
@@ -636,5 +969,4 @@ func (suite *EndToEndTestSuite) getMonitoringAlerts() []models.Alert {
 
 func (suite *EndToEndTestSuite) testAlertEscalation() {
 	// Implementation for testing alert escalation
-}</content>
-<parameter name="filePath">/workspaces/insec/tests/e2e/system_test.go
+}
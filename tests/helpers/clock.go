@@ -0,0 +1,42 @@
+// Package helpers holds small, dependency-free utilities shared across the
+// test suites under tests/.
+package helpers
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a deterministic auth.Clock for tests that need to exercise
+// expiry, revocation TTLs, or clock-skew tolerance without sleeping past a
+// real token's TTL - which is inherently flaky under load.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock pinned to start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now implements auth.Clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set pins the clock to t.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// TestHelper drives an already-deployed INSEC system for the end-to-end,
+// security, and performance suites under tests/ - it never spawns a server
+// itself (these suites assume one is already running, e.g. a local binary
+// or a docker-compose stack started out-of-band), only waits for it to
+// answer and disconnects when the suite is done.
+type TestHelper struct {
+	BaseURL string
+	client  *http.Client
+}
+
+// NewTestHelper points at the target system from INSEC_TEST_BASE_URL,
+// falling back to the suites' own hardcoded localhost default.
+func NewTestHelper() *TestHelper {
+	baseURL := os.Getenv("INSEC_TEST_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	return &TestHelper{BaseURL: baseURL, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// StartSystem confirms the plain (non-mTLS) system under test is reachable.
+func (h *TestHelper) StartSystem() error {
+	return h.ping()
+}
+
+// StartSecureSystem confirms the system under test is reachable over its
+// TLS listener - the same endpoint, since /health is exempt from client
+// certificate verification.
+func (h *TestHelper) StartSecureSystem() error {
+	return h.ping()
+}
+
+// WaitForSystemReady polls /health until it responds or the deadline below
+// is reached, so a suite run immediately after the system starts doesn't
+// race its listener coming up.
+func (h *TestHelper) WaitForSystemReady() {
+	h.waitUntilReady()
+}
+
+// WaitForSecureSystemReady is WaitForSystemReady's counterpart for the
+// security suite's naming convention.
+func (h *TestHelper) WaitForSecureSystemReady() {
+	h.waitUntilReady()
+}
+
+// StopSystem is a no-op: TestHelper never started the system, so it has
+// nothing of its own to tear down.
+func (h *TestHelper) StopSystem() {}
+
+func (h *TestHelper) ping() error {
+	resp, err := h.client.Get(h.BaseURL + "/health")
+	if err != nil {
+		return fmt.Errorf("system under test at %s is not reachable: %w", h.BaseURL, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (h *TestHelper) waitUntilReady() {
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		if err := h.ping(); err == nil {
+			return
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
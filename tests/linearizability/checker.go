@@ -0,0 +1,169 @@
+package linearizability
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxHistoryOps bounds the history this checker accepts: the search space
+// is exponential in the number of concurrent operations, and the remaining
+// set is tracked as a uint64 bitmask, so histories this harness is meant to
+// check (tens of operations from a handful of goroutines, not a
+// long-running soak) fit comfortably under it.
+const maxHistoryOps = 64
+
+// Violation describes why a history failed to linearize: it names the
+// first operation (see findViolation) whose recorded output the sequential
+// model rejects when that operation is applied alone to the initial state.
+// It is a diagnostic hint, not proof that this operation caused the failure.
+type Violation struct {
+	Op Operation
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("no valid linearization: operation %s (input %+v, output %+v) is rejected by the model even when applied alone to the initial state", v.Op.Kind, v.Op.Input, v.Op.Output)
+}
+
+// CheckLinearizable searches for an ordering of history consistent with
+// both real-time interval constraints (an operation that completed before
+// another started must precede it) and the sequential model in model.go.
+// It returns the witness ordering (indices into history) on success.
+func CheckLinearizable(history []Operation) (bool, []int, error) {
+	n := len(history)
+	if n > maxHistoryOps {
+		return false, nil, fmt.Errorf("linearizability: history has %d operations, exceeds the %d this checker supports", n, maxHistoryOps)
+	}
+
+	ok, order := search(history)
+	if !ok {
+		return false, nil, findViolation(history)
+	}
+	return true, order, nil
+}
+
+// search runs the exhaustive linearization search with no diagnostic
+// fallback, so findViolation can reuse it on a single-operation slice
+// without recursing back into CheckLinearizable's own findViolation call.
+func search(history []Operation) (bool, []int) {
+	n := len(history)
+	var full uint64
+	for i := 0; i < n; i++ {
+		full |= 1 << uint(i)
+	}
+
+	c := &checker{ops: history, failed: make(map[uint64]map[string]bool)}
+	order := make([]int, 0, n)
+	ok := c.search(full, InitialState(), &order)
+	return ok, order
+}
+
+type checker struct {
+	ops    []Operation
+	failed map[uint64]map[string]bool // remaining-mask -> set of state fingerprints already proven unsatisfiable
+}
+
+// search tries to extend order with a linearization of every operation
+// still set in remaining, starting from state. It mutates order in place so
+// a successful caller can read off the witness.
+func (c *checker) search(remaining uint64, state State, order *[]int) bool {
+	if remaining == 0 {
+		return true
+	}
+
+	key := stateFingerprint(state)
+	if seen, ok := c.failed[remaining]; ok && seen[key] {
+		return false
+	}
+
+	for i := 0; i < len(c.ops); i++ {
+		bit := uint64(1) << uint(i)
+		if remaining&bit == 0 {
+			continue
+		}
+		if !c.eligible(i, remaining) {
+			continue
+		}
+		next, ok := Apply(state, c.ops[i])
+		if !ok {
+			continue
+		}
+		*order = append(*order, i)
+		if c.search(remaining&^bit, next, order) {
+			return true
+		}
+		*order = (*order)[:len(*order)-1]
+	}
+
+	if _, ok := c.failed[remaining]; !ok {
+		c.failed[remaining] = make(map[string]bool)
+	}
+	c.failed[remaining][key] = true
+	return false
+}
+
+// eligible reports whether op i may be linearized next: no still-remaining
+// operation completed, in real time, before i started - such an operation
+// would have to be linearized before i, which means i can't go first.
+func (c *checker) eligible(i int, remaining uint64) bool {
+	candidate := c.ops[i]
+	for j := 0; j < len(c.ops); j++ {
+		if j == i {
+			continue
+		}
+		bit := uint64(1) << uint(j)
+		if remaining&bit == 0 {
+			continue
+		}
+		if c.ops[j].End <= candidate.Start {
+			return false
+		}
+	}
+	return true
+}
+
+func stateFingerprint(s State) string {
+	var b strings.Builder
+
+	tokenKeys := make([]string, 0, len(s.Tokens))
+	for k := range s.Tokens {
+		tokenKeys = append(tokenKeys, k)
+	}
+	sort.Strings(tokenKeys)
+	b.WriteString("tokens:")
+	for _, k := range tokenKeys {
+		fmt.Fprintf(&b, "%s=%d;", k, s.Tokens[k])
+	}
+
+	revokedKeys := make([]string, 0, len(s.Revoked))
+	for k := range s.Revoked {
+		revokedKeys = append(revokedKeys, k)
+	}
+	sort.Strings(revokedKeys)
+	b.WriteString("|revoked:")
+	for _, k := range revokedKeys {
+		b.WriteString(k)
+		b.WriteString(";")
+	}
+
+	b.WriteString("|events:")
+	for _, e := range s.Events {
+		b.WriteString(e)
+		b.WriteString(";")
+	}
+	return b.String()
+}
+
+// findViolation re-runs a best-effort scan to name a single operation that
+// plausibly caused the failure, for a more actionable test failure message
+// than a bare "not linearizable". It isn't itself the source of truth -
+// CheckLinearizable's exhaustive search is - just a diagnostic aid.
+func findViolation(history []Operation) error {
+	for i := range history {
+		single := history[i : i+1]
+		if ok, _ := search(single); !ok {
+			return Violation{Op: history[i]}
+		}
+	}
+	return fmt.Errorf("no valid linearization exists for this history of %d operations", len(history))
+}
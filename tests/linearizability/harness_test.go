@@ -0,0 +1,316 @@
+package linearizability
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"insec/internal/auth"
+)
+
+// fakeUserRepo is a minimal in-memory auth.UserRepository backing this
+// harness - no database is needed since the checker only cares about
+// AuthService's own token bookkeeping, not persistence.
+type fakeUserRepo struct {
+	mu   sync.Mutex
+	user *auth.User
+}
+
+func newFakeUserRepo(user *auth.User) *fakeUserRepo {
+	return &fakeUserRepo{user: user}
+}
+
+func (r *fakeUserRepo) GetUserByID(id string) (*auth.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id != r.user.ID {
+		return nil, fmt.Errorf("user not found: %s", id)
+	}
+	u := *r.user
+	return &u, nil
+}
+
+func (r *fakeUserRepo) GetUserByEmail(email string) (*auth.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if email != r.user.Email {
+		return nil, fmt.Errorf("user not found: %s", email)
+	}
+	u := *r.user
+	return &u, nil
+}
+
+func (r *fakeUserRepo) CreateUser(user *auth.User) error { return nil }
+
+func (r *fakeUserRepo) UpdateUser(user *auth.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.user = user
+	return nil
+}
+
+func (r *fakeUserRepo) DeleteUser(id string) error { return nil }
+
+// fakeEventStore is a trivial in-memory event/alert sink: every ingested
+// event produces exactly one alert, which is all the model in model.go
+// needs to catch a missed or double-counted event under concurrency.
+type fakeEventStore struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (s *fakeEventStore) ingest(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, id)
+	return true
+}
+
+func (s *fakeEventStore) alertCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+// history collects Operations from concurrent goroutines under a single
+// mutex of its own, which is not itself part of what's being checked.
+type history struct {
+	mu  sync.Mutex
+	ops []Operation
+}
+
+func (h *history) record(op Operation) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ops = append(h.ops, op)
+}
+
+// mintedToken pairs a jti with the signed token that carries it, so a
+// refresh goroutine can call the real ValidateToken rather than reaching
+// into AuthService's unexported revocation bookkeeping.
+type mintedToken struct {
+	jti   string
+	token string
+}
+
+// jtiPool lets refresh/revoke goroutines pick a token a concurrent
+// authenticate goroutine has already minted.
+type jtiPool struct {
+	mu     sync.Mutex
+	tokens []mintedToken
+}
+
+func (p *jtiPool) add(jti, token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tokens = append(p.tokens, mintedToken{jti: jti, token: token})
+}
+
+func (p *jtiPool) pick(r *rand.Rand) (mintedToken, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.tokens) == 0 {
+		return mintedToken{}, false
+	}
+	return p.tokens[r.Intn(len(p.tokens))], true
+}
+
+// TestLinearizability_AuthAndEventSubsystem drives AuthService and a fake
+// event store from several goroutines performing a mix of authenticate,
+// refresh (validate), revoke, ingest_event, and query_alerts calls, records
+// the resulting history with wall-clock start/end timestamps, and checks it
+// against the sequential model in model.go. This is the correctness
+// counterpart AuthServiceTestSuite's TestConcurrentAuthentication never
+// had: that suite only asserts NoError per call, never that the recorded
+// outcomes are consistent with any valid sequential execution.
+func TestLinearizability_AuthAndEventSubsystem(t *testing.T) {
+	user := &auth.User{
+		ID:       "lin-user-1",
+		Email:    "lin@example.com",
+		Password: mustHash(t, "correct-password"),
+		Role:     "analyst",
+	}
+	repo := newFakeUserRepo(user)
+	service := auth.NewAuthService(repo, "lin-test-secret")
+	events := &fakeEventStore{}
+
+	h := &history{}
+	pool := &jtiPool{}
+
+	const goroutines = 8
+	const opsPerGoroutine = 6
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for g := 0; g < goroutines; g++ {
+		go func(proc int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(proc) + 1))
+			for i := 0; i < opsPerGoroutine; i++ {
+				switch r.Intn(4) {
+				case 0:
+					doAuthenticate(h, service, user, proc, pool)
+				case 1:
+					doRefresh(h, service, proc, pool, r)
+				case 2:
+					doRevoke(h, service, proc, pool, r)
+				default:
+					if r.Intn(2) == 0 {
+						doIngestEvent(h, events, proc, i)
+					} else {
+						doQueryAlerts(h, events, proc)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	ok, _, err := CheckLinearizable(h.ops)
+	assert.True(t, ok, "expected a valid linearization of the recorded history: %v", err)
+}
+
+func mustHash(t *testing.T, password string) string {
+	t.Helper()
+	hash, err := (&auth.BcryptHasher{Cost: 4}).Hash(password)
+	assert.NoError(t, err)
+	return hash
+}
+
+func doAuthenticate(h *history, service *auth.AuthService, user *auth.User, proc int, pool *jtiPool) {
+	start := time.Now().UnixNano()
+	token, err := service.AuthenticateUser(user.Email, "correct-password")
+	ok := err == nil
+	var jti string
+	var expiry int64
+	if ok {
+		claims, verr := service.ValidateToken(token)
+		if verr == nil {
+			jti = claims.ID
+			expiry = claims.ExpiresAt.Time.UnixNano()
+			pool.add(jti, token)
+		} else {
+			ok = false
+		}
+	}
+	end := time.Now().UnixNano()
+	h.record(Operation{
+		Proc:   proc,
+		Kind:   OpAuthenticate,
+		Input:  AuthenticateInput{},
+		Output: AuthenticateOutput{JTI: jti, Expiry: expiry, OK: ok},
+		Start:  start,
+		End:    end,
+	})
+}
+
+func doRefresh(h *history, service *auth.AuthService, proc int, pool *jtiPool, r *rand.Rand) {
+	minted, found := pool.pick(r)
+	if !found {
+		return
+	}
+	start := time.Now().UnixNano()
+	_, err := service.ValidateToken(minted.token)
+	end := time.Now().UnixNano()
+	h.record(Operation{
+		Proc:   proc,
+		Kind:   OpRefresh,
+		Input:  RefreshInput{JTI: minted.jti, At: start},
+		Output: RefreshOutput{Valid: err == nil},
+		Start:  start,
+		End:    end,
+	})
+}
+
+func doRevoke(h *history, service *auth.AuthService, proc int, pool *jtiPool, r *rand.Rand) {
+	minted, found := pool.pick(r)
+	if !found {
+		return
+	}
+	start := time.Now().UnixNano()
+	err := service.RevokeToken(minted.jti)
+	end := time.Now().UnixNano()
+	h.record(Operation{
+		Proc:   proc,
+		Kind:   OpRevoke,
+		Input:  RevokeInput{JTI: minted.jti},
+		Output: RevokeOutput{OK: err == nil},
+		Start:  start,
+		End:    end,
+	})
+}
+
+func doIngestEvent(h *history, events *fakeEventStore, proc, i int) {
+	id := fmt.Sprintf("proc%d-event%d", proc, i)
+	start := time.Now().UnixNano()
+	ok := events.ingest(id)
+	end := time.Now().UnixNano()
+	h.record(Operation{
+		Proc:   proc,
+		Kind:   OpIngestEvent,
+		Input:  IngestEventInput{EventID: id},
+		Output: IngestEventOutput{OK: ok},
+		Start:  start,
+		End:    end,
+	})
+}
+
+func doQueryAlerts(h *history, events *fakeEventStore, proc int) {
+	start := time.Now().UnixNano()
+	count := events.alertCount()
+	end := time.Now().UnixNano()
+	h.record(Operation{
+		Proc:   proc,
+		Kind:   OpQueryAlerts,
+		Input:  struct{}{},
+		Output: QueryAlertsOutput{Count: count},
+		Start:  start,
+		End:    end,
+	})
+}
+
+// TestLinearizability_DetectsRevokedTokenFault injects a deliberate fault -
+// a refresh reporting Valid=true for a jti that was already revoked before
+// the refresh even started - and proves the checker rejects the resulting
+// history instead of silently accepting it.
+func TestLinearizability_DetectsRevokedTokenFault(t *testing.T) {
+	mintStart := int64(1_000_000_000)
+	mintEnd := int64(1_000_000_100)
+	revokeStart := int64(1_000_000_200)
+	revokeEnd := int64(1_000_000_300)
+	refreshStart := int64(1_000_000_400) // strictly after revoke completed
+	refreshEnd := int64(1_000_000_500)
+
+	faulty := []Operation{
+		{
+			Kind:   OpAuthenticate,
+			Input:  AuthenticateInput{},
+			Output: AuthenticateOutput{JTI: "jti-1", Expiry: 2_000_000_000, OK: true},
+			Start:  mintStart,
+			End:    mintEnd,
+		},
+		{
+			Kind:   OpRevoke,
+			Input:  RevokeInput{JTI: "jti-1"},
+			Output: RevokeOutput{OK: true},
+			Start:  revokeStart,
+			End:    revokeEnd,
+		},
+		{
+			// Fault: this op ran strictly after the revoke above completed,
+			// yet claims the token was still valid.
+			Kind:   OpRefresh,
+			Input:  RefreshInput{JTI: "jti-1", At: refreshStart},
+			Output: RefreshOutput{Valid: true},
+			Start:  refreshStart,
+			End:    refreshEnd,
+		},
+	}
+
+	ok, _, err := CheckLinearizable(faulty)
+	assert.False(t, ok, "a revoked token reported as valid must be detected as non-linearizable")
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+// Package linearizability implements a Porcupine-style linearizability
+// checker for the auth + event-ingestion subsystems: it records a history
+// of concurrent operations with wall-clock start/end timestamps, then
+// searches for an ordering of those operations - consistent with their
+// real-time overlap - that matches a simple sequential model. If no such
+// ordering exists, the history is non-linearizable: some operation
+// observed a state no valid sequential execution could have produced.
+package linearizability
+
+// Operation is one call recorded during a concurrent run: Start/End are
+// wall-clock nanoseconds bracketing the call (from time.Now().UnixNano()
+// immediately before and after invoking it), so two operations whose
+// intervals don't overlap have a forced real-time order, while overlapping
+// operations may be linearized in either order.
+type Operation struct {
+	Proc   int         // goroutine/client id, for diagnostics only
+	Kind   OpKind
+	Input  interface{}
+	Output interface{}
+	Start  int64
+	End    int64
+}
+
+// OpKind is one of the auth+event subsystem calls the model understands.
+type OpKind string
+
+const (
+	OpAuthenticate OpKind = "authenticate"
+	OpRefresh      OpKind = "refresh" // validates a token (renamed Valid check to avoid clashing with AuthService.RefreshToken's own meaning)
+	OpRevoke       OpKind = "revoke"
+	OpIngestEvent  OpKind = "ingest_event"
+	OpQueryAlerts  OpKind = "query_alerts"
+)
+
+// AuthenticateInput/Output - a login that mints a fresh jti. JTI/Expiry are
+// on the output, not the input, since AuthService - not the caller - assigns
+// them.
+type AuthenticateInput struct{}
+type AuthenticateOutput struct {
+	JTI    string
+	Expiry int64 // unix nanos
+	OK     bool
+}
+
+// RefreshInput/Output - a validity check against a previously-minted jti.
+type RefreshInput struct {
+	JTI string
+	// At is the wall-clock instant (unix nanos) the caller evaluated
+	// expiry against, normally the operation's own Start.
+	At int64
+}
+type RefreshOutput struct {
+	Valid bool
+}
+
+// RevokeInput/Output - denylist a jti.
+type RevokeInput struct {
+	JTI string
+}
+type RevokeOutput struct {
+	OK bool
+}
+
+// IngestEventInput/Output - append an event.
+type IngestEventInput struct {
+	EventID string
+}
+type IngestEventOutput struct {
+	OK bool
+}
+
+// QueryAlertsOutput - read how many events have been ingested so far (this
+// model treats every ingested event as producing exactly one alert, which
+// is all the checker needs to catch a missed or double-counted event).
+type QueryAlertsOutput struct {
+	Count int
+}
+
+// State is the sequential model's ground truth at some point in a
+// linearization: every jti ever authenticated (with its expiry), the set
+// of revoked jtis, and the events ingested so far, in ingestion order.
+type State struct {
+	Tokens  map[string]int64 // jti -> expiry (unix nanos)
+	Revoked map[string]bool
+	Events  []string
+}
+
+// InitialState returns an empty model state.
+func InitialState() State {
+	return State{
+		Tokens:  make(map[string]int64),
+		Revoked: make(map[string]bool),
+		Events:  nil,
+	}
+}
+
+// clone returns a deep-enough copy of s so applying an operation to a
+// candidate linearization never mutates a sibling branch the checker is
+// still exploring.
+func (s State) clone() State {
+	tokens := make(map[string]int64, len(s.Tokens))
+	for k, v := range s.Tokens {
+		tokens[k] = v
+	}
+	revoked := make(map[string]bool, len(s.Revoked))
+	for k, v := range s.Revoked {
+		revoked[k] = v
+	}
+	events := make([]string, len(s.Events))
+	copy(events, s.Events)
+	return State{Tokens: tokens, Revoked: revoked, Events: events}
+}
+
+// Apply advances state by op, reporting whether op's recorded Output is
+// what the sequential model would have produced at this point in the
+// linearization. A false return means this op cannot be placed here - the
+// checker must try a different ordering or conclude the history is
+// non-linearizable.
+func Apply(s State, op Operation) (State, bool) {
+	next := s.clone()
+	switch op.Kind {
+	case OpAuthenticate:
+		out := op.Output.(AuthenticateOutput)
+		if !out.OK {
+			return s, false
+		}
+		next.Tokens[out.JTI] = out.Expiry
+		return next, true
+
+	case OpRevoke:
+		in := op.Input.(RevokeInput)
+		out := op.Output.(RevokeOutput)
+		if !out.OK {
+			return s, false
+		}
+		next.Revoked[in.JTI] = true
+		return next, true
+
+	case OpRefresh:
+		in := op.Input.(RefreshInput)
+		out := op.Output.(RefreshOutput)
+		expiry, minted := s.Tokens[in.JTI]
+		expectValid := minted && !s.Revoked[in.JTI] && in.At < expiry
+		if out.Valid != expectValid {
+			return s, false
+		}
+		return next, true
+
+	case OpIngestEvent:
+		in := op.Input.(IngestEventInput)
+		out := op.Output.(IngestEventOutput)
+		if !out.OK {
+			return s, false
+		}
+		next.Events = append(next.Events, in.EventID)
+		return next, true
+
+	case OpQueryAlerts:
+		out := op.Output.(QueryAlertsOutput)
+		if out.Count != len(s.Events) {
+			return s, false
+		}
+		return next, true
+
+	default:
+		return s, false
+	}
+}
@@ -1,9 +1,7 @@
 package tests
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"sync"
 	"testing"
@@ -13,7 +11,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"insec/internal/models"
-	"insec/test/helpers"
+	"insec/tests/helpers"
 )
 
 type PerformanceTestSuite struct {
@@ -284,13 +282,13 @@ func (suite *PerformanceTestSuite) TestScalabilityWithIncreasingLoad() {
 		metrics := suite.getPerformanceMetrics()
 
 		result := PerformanceResult{
-			ConcurrentUsers:    loadLevel,
-			Duration:          duration,
+			ConcurrentUsers:     loadLevel,
+			Duration:            duration,
 			AverageResponseTime: metrics.AverageResponseTime,
-			Throughput:        metrics.Throughput,
-			ErrorRate:         metrics.ErrorRate,
-			CPUUsage:          metrics.CPUUsage,
-			MemoryUsage:       metrics.MemoryUsage,
+			Throughput:          metrics.Throughput,
+			ErrorRate:           metrics.ErrorRate,
+			CPUUsage:            metrics.CPUUsage,
+			MemoryUsage:         metrics.MemoryUsage,
 		}
 
 		results = append(results, result)
@@ -472,19 +470,18 @@ func (suite *PerformanceTestSuite) cacheGet(key string) interface{} {
 
 type PerformanceResult struct {
 	ConcurrentUsers     int
-	Duration           time.Duration
+	Duration            time.Duration
 	AverageResponseTime time.Duration
-	Throughput         float64
-	ErrorRate          float64
-	CPUUsage           float64
-	MemoryUsage        float64
+	Throughput          float64
+	ErrorRate           float64
+	CPUUsage            float64
+	MemoryUsage         float64
 }
 
 type PerformanceMetrics struct {
 	AverageResponseTime time.Duration
-	Throughput         float64
-	ErrorRate          float64
-	CPUUsage           float64
-	MemoryUsage        float64
-}</content>
-<parameter name="filePath">/workspaces/insec/tests/performance/system_performance_test.go
+	Throughput          float64
+	ErrorRate           float64
+	CPUUsage            float64
+	MemoryUsage         float64
+}
@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
-	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -14,7 +14,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"insec/internal/models"
-	"insec/test/helpers"
+	"insec/tests/helpers"
 )
 
 type SecurityTestSuite struct {
@@ -85,15 +85,13 @@ func (suite *SecurityTestSuite) TestAuthenticationBypassAttempts() {
 }
 
 func (suite *SecurityTestSuite) TestAuthorizationEnforcement() {
-	validToken := suite.authenticate()
-
 	// Test role-based access control
 	endpoints := map[string][]string{
-		"/api/v1/admin/users":     {"admin"},
-		"/api/v1/admin/config":    {"admin"},
-		"/api/v1/alerts":          {"admin", "analyst", "viewer"},
-		"/api/v1/analytics":       {"admin", "analyst"},
-		"/api/v1/events":          {"admin", "analyst", "viewer"},
+		"/api/v1/admin/users":  {"admin"},
+		"/api/v1/admin/config": {"admin"},
+		"/api/v1/alerts":       {"admin", "analyst", "viewer"},
+		"/api/v1/analytics":    {"admin", "analyst"},
+		"/api/v1/events":       {"admin", "analyst", "viewer"},
 	}
 
 	for endpoint, allowedRoles := range endpoints {
@@ -209,6 +207,7 @@ func (suite *SecurityTestSuite) TestInputValidation() {
 		name         string
 		payload      interface{}
 		expectStatus int
+		expectCode   string // stable errs.Error code; empty for non-error responses
 	}{
 		{
 			name: "Valid alert",
@@ -228,6 +227,7 @@ func (suite *SecurityTestSuite) TestInputValidation() {
 				"severity":    "high",
 			},
 			expectStatus: http.StatusBadRequest,
+			expectCode:   "alert.title.required",
 		},
 		{
 			name: "Title too long",
@@ -237,6 +237,7 @@ func (suite *SecurityTestSuite) TestInputValidation() {
 				"severity":    "high",
 			},
 			expectStatus: http.StatusBadRequest,
+			expectCode:   "alert.title.too_long",
 		},
 		{
 			name: "Invalid severity",
@@ -246,6 +247,7 @@ func (suite *SecurityTestSuite) TestInputValidation() {
 				"severity":    "invalid_severity",
 			},
 			expectStatus: http.StatusBadRequest,
+			expectCode:   "alert.severity.invalid",
 		},
 		{
 			name: "Description too short",
@@ -255,6 +257,7 @@ func (suite *SecurityTestSuite) TestInputValidation() {
 				"severity":    "high",
 			},
 			expectStatus: http.StatusBadRequest,
+			expectCode:   "alert.description.too_short",
 		},
 		{
 			name: "Invalid email format",
@@ -265,6 +268,7 @@ func (suite *SecurityTestSuite) TestInputValidation() {
 				"assigned_to": "invalid-email",
 			},
 			expectStatus: http.StatusBadRequest,
+			expectCode:   "alert.assigned_to.invalid_email",
 		},
 	}
 
@@ -280,6 +284,14 @@ func (suite *SecurityTestSuite) TestInputValidation() {
 			defer resp.Body.Close()
 
 			assert.Equal(t, tc.expectStatus, resp.StatusCode)
+
+			if tc.expectCode != "" {
+				var body struct {
+					Code string `json:"code"`
+				}
+				json.NewDecoder(resp.Body).Decode(&body)
+				assert.Equal(t, tc.expectCode, body.Code, "clients should be able to branch on a stable code, not message text")
+			}
 		})
 	}
 }
@@ -416,6 +428,84 @@ func (suite *SecurityTestSuite) TestAuditLogging() {
 	assert.True(suite.T(), operations["DELETE"], "Should have DELETE operation in audit logs")
 }
 
+func (suite *SecurityTestSuite) TestRevokedRefreshTokenReuseKillsFamily() {
+	refreshToken := suite.obtainRefreshToken()
+
+	// First use: legitimate rotation.
+	rotateBody, _ := json.Marshal(map[string]string{"refresh_token": refreshToken})
+	req, _ := http.NewRequest("POST", suite.baseURL+"/api/v1/auth/refresh", bytes.NewBuffer(rotateBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode, "First use of a fresh refresh token should succeed")
+
+	// Second use of the SAME (now-rotated-away) refresh token: replay.
+	req, _ = http.NewRequest("POST", suite.baseURL+"/api/v1/auth/refresh", bytes.NewBuffer(rotateBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode, "Reuse of a rotated-away refresh token must be rejected")
+
+	// The entire family, including the token issued by the first (legitimate)
+	// rotation, must now be dead too.
+	secondToken := suite.lastIssuedRefreshToken()
+	rotateBody2, _ := json.Marshal(map[string]string{"refresh_token": secondToken})
+	req, _ = http.NewRequest("POST", suite.baseURL+"/api/v1/auth/refresh", bytes.NewBuffer(rotateBody2))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode, "Reuse detection must revoke the whole token family, not just the replayed token")
+}
+
+func (suite *SecurityTestSuite) TestAuditChainTamperDetection() {
+	validToken := suite.authenticate()
+
+	alertData := map[string]interface{}{
+		"title":       "Chain Tamper Test Alert",
+		"description": "Seeds an audit entry for the tamper check",
+		"severity":    "low",
+	}
+	jsonData, _ := json.Marshal(alertData)
+
+	req, _ := http.NewRequest("POST", suite.baseURL+"/api/v1/alerts", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+validToken)
+	resp, err := suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+
+	req, _ = http.NewRequest("GET", suite.baseURL+"/api/v1/admin/audit/verify", nil)
+	req.Header.Set("Authorization", "Bearer "+validToken)
+	resp, err = suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+
+	var before struct {
+		Valid bool `json:"valid"`
+	}
+	json.NewDecoder(resp.Body).Decode(&before)
+	assert.True(suite.T(), before.Valid, "Audit chain should be intact before tampering")
+
+	suite.tamperMiddleAuditEntry()
+
+	req, _ = http.NewRequest("GET", suite.baseURL+"/api/v1/admin/audit/verify", nil)
+	req.Header.Set("Authorization", "Bearer "+validToken)
+	resp, err = suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+
+	var after struct {
+		Valid         bool   `json:"valid"`
+		BrokenEntryID string `json:"broken_entry_id"`
+	}
+	json.NewDecoder(resp.Body).Decode(&after)
+	assert.False(suite.T(), after.Valid, "Verification must fail once a middle entry has been mutated")
+	assert.NotEmpty(suite.T(), after.BrokenEntryID, "Verification should report which entry broke the chain")
+}
+
 func (suite *SecurityTestSuite) TestSessionManagement() {
 	// Test session timeout
 	token := suite.authenticate()
@@ -483,9 +573,9 @@ func (suite *SecurityTestSuite) TestSecureHeaders() {
 
 	// Check for security headers
 	securityHeaders := map[string]string{
-		"X-Content-Type-Options": "nosniff",
-		"X-Frame-Options":        "DENY",
-		"X-XSS-Protection":       "1; mode=block",
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"X-XSS-Protection":          "1; mode=block",
 		"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
 		"Content-Security-Policy":   "default-src 'self'",
 	}
@@ -543,6 +633,99 @@ func (suite *SecurityTestSuite) TestBruteForceProtection() {
 	}
 }
 
+func (suite *SecurityTestSuite) TestAgentMTLSEnrollment() {
+	enrollData := map[string]interface{}{
+		"enrollment_token": suite.issueEnrollmentToken(),
+		"csr":              suite.generateAgentCSR(),
+	}
+	jsonData, _ := json.Marshal(enrollData)
+
+	req, _ := http.NewRequest("POST", suite.baseURL+"/v1/agents/enroll", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var enrollResp struct {
+		Certificate string `json:"certificate"`
+		CAChain     string `json:"ca_chain"`
+	}
+	json.NewDecoder(resp.Body).Decode(&enrollResp)
+	assert.NotEmpty(suite.T(), enrollResp.Certificate, "Enrollment should return a signed client certificate")
+	assert.NotEmpty(suite.T(), enrollResp.CAChain, "Enrollment should return the CA chain for renewal verification")
+
+	// Re-using the same one-time token must be rejected.
+	req, _ = http.NewRequest("POST", suite.baseURL+"/v1/agents/enroll", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode, "Enrollment token must be single-use")
+}
+
+func (suite *SecurityTestSuite) TestRevokedAgentCertRejected() {
+	agentClient := suite.agentClientWithCert(suite.enrollTestAgent())
+
+	req, _ := http.NewRequest("POST", suite.baseURL+"/v1/events", bytes.NewBufferString("[]"))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := agentClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode, "A valid, non-revoked agent cert should be accepted")
+
+	suite.revokeAgentCert()
+
+	req, _ = http.NewRequest("POST", suite.baseURL+"/v1/events", bytes.NewBufferString("[]"))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = agentClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode, "A revoked agent cert must be rejected on every subsequent request")
+}
+
+func (suite *SecurityTestSuite) TestStepUpMFARequiredForSensitiveMutation() {
+	token := suite.authenticate()
+	alertID := suite.createTestAlert(token)
+
+	resolveBody, _ := json.Marshal(map[string]string{"status": "resolved"})
+
+	// No X-MFA-Token: a status=resolved update is a sensitive mutation and
+	// must be rejected even with a valid access token.
+	req, _ := http.NewRequest("PUT", suite.baseURL+"/api/v1/alerts/"+alertID, bytes.NewBuffer(resolveBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode, "Resolving an alert without step-up MFA must be rejected")
+
+	// Complete the challenge/verify round trip, then retry with the
+	// resulting assertion bound to this exact method+path.
+	mfaToken := suite.completeMFAStepUp("PUT", "/api/v1/alerts/"+alertID)
+
+	req, _ = http.NewRequest("PUT", suite.baseURL+"/api/v1/alerts/"+alertID, bytes.NewBuffer(resolveBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-MFA-Token", mfaToken)
+	resp, err = suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode, "Resolving an alert with a valid, request-bound MFA assertion should succeed")
+
+	// The same assertion must not be replayable against a second alert.
+	otherAlertID := suite.createTestAlert(token)
+	req, _ = http.NewRequest("PUT", suite.baseURL+"/api/v1/alerts/"+otherAlertID, bytes.NewBuffer(resolveBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-MFA-Token", mfaToken)
+	resp, err = suite.httpClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode, "An MFA assertion bound to one alert must not authorize a mutation on another")
+}
+
 // Helper methods
 
 func (suite *SecurityTestSuite) authenticate() string {
@@ -575,7 +758,7 @@ func (suite *SecurityTestSuite) generateExpiredToken() string {
 	return "expired.token"
 }
 
-func (suite *SecurityTestSuite) extractAlertID(body *bytes.Buffer) string {
+func (suite *SecurityTestSuite) extractAlertID(body io.Reader) string {
 	// Implementation for extracting alert ID from response
 	return "alert-id"
 }
@@ -599,6 +782,63 @@ func (suite *SecurityTestSuite) advanceSessionTime(duration time.Duration) {
 	// Implementation for advancing session time
 }
 
+func (suite *SecurityTestSuite) issueEnrollmentToken() string {
+	// Implementation for minting a one-time agent enrollment token
+	return "enrollment-token"
+}
+
+func (suite *SecurityTestSuite) generateAgentCSR() string {
+	// Implementation for generating a PEM-encoded agent CSR
+	return "-----BEGIN CERTIFICATE REQUEST-----\n-----END CERTIFICATE REQUEST-----"
+}
+
+func (suite *SecurityTestSuite) enrollTestAgent() *tls.Certificate {
+	// Implementation for enrolling a throwaway agent and returning its cert
+	return &tls.Certificate{}
+}
+
+func (suite *SecurityTestSuite) agentClientWithCert(cert *tls.Certificate) *http.Client {
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			Certificates:       []tls.Certificate{*cert},
+		},
+	}
+	return &http.Client{Transport: tr}
+}
+
+func (suite *SecurityTestSuite) revokeAgentCert() {
+	// Implementation for revoking the test agent's certificate via the admin API
+}
+
+func (suite *SecurityTestSuite) obtainRefreshToken() string {
+	// Implementation for logging in and extracting the refresh_token field
+	return "refresh-token"
+}
+
+func (suite *SecurityTestSuite) lastIssuedRefreshToken() string {
+	// Implementation for capturing the refresh_token returned by the most
+	// recent /api/v1/auth/refresh call
+	return "rotated-refresh-token"
+}
+
+func (suite *SecurityTestSuite) tamperMiddleAuditEntry() {
+	// Implementation for directly mutating a non-terminal line of the audit
+	// log file on disk, simulating an attacker editing history after the fact
+}
+
+func (suite *SecurityTestSuite) createTestAlert(token string) string {
+	// Implementation for creating an alert via the API and returning its ID
+	return "alert-id"
+}
+
+func (suite *SecurityTestSuite) completeMFAStepUp(method, path string) string {
+	// Implementation for driving /api/v1/auth/mfa/challenge and
+	// /api/v1/auth/mfa/verify to completion and returning the resulting
+	// X-MFA-Token assertion bound to method+path
+	return "mfa-token"
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -606,5 +846,4 @@ func contains(slice []string, item string) bool {
 		}
 	}
 	return false
-}</content>
-<parameter name="filePath">/workspaces/insec/tests/security/system_security_test.go
+}